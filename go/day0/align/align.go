@@ -0,0 +1,455 @@
+// Package align finds struct types whose fields could be reordered to waste
+// less memory on padding, and optionally rewrites their declarations.
+//
+// It parses and type-checks the packages with go/parser and go/types, so the
+// sizes are the compiler's for the chosen architecture, not an estimate:
+//
+//	sugs, err := align.Analyze("./...", align.Options{})
+//	for _, s := range sugs {
+//		fmt.Println(s.Name, s.Size, "->", s.OptimalSize)
+//	}
+//
+// Two orderings are suggested for each struct. The optimal ordering has the
+// minimal size. The cache-friendly ordering puts the hot fields first, packed
+// as tightly as possible so they share as few cache lines as possible, and
+// orders the rest like the optimal one.
+package align
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"runtime"
+	"slices"
+	"strings"
+
+	"day0/analysis/load"
+)
+
+// CacheLine is the cache line size the cache-friendly ordering packs the hot
+// fields into.
+const CacheLine = 64
+
+// HotMarker marks a field as hot when it appears in the field's doc or line
+// comment, e.g. `count int64 // align:hot`.
+const HotMarker = "align:hot"
+
+// Options configures Analyze.
+type Options struct {
+	// Arch selects the sizes of the gc compiler for a GOARCH. Empty means
+	// the architecture of the running program.
+	Arch string
+	// Hot lists hot fields as "Type.Field", in addition to the fields marked
+	// with HotMarker.
+	Hot []string
+	// Archs additionally computes the current and optimal size of every
+	// struct for each of these GOARCH values; see Suggestion.Archs.
+	Archs []string
+}
+
+// ArchSize is the current and optimal size of a struct on one architecture.
+// The optimal field order can differ between architectures.
+type ArchSize struct {
+	Arch        string
+	Size        int64
+	OptimalSize int64
+}
+
+// Suggestion is the analysis of one struct type.
+type Suggestion struct {
+	Pos  token.Position // position of the type name
+	Name string
+
+	Size        int64 // current size in bytes
+	OptimalSize int64 // size with the fields in Optimal order
+	CacheSize   int64 // size with the fields in Cache order
+
+	// Current, Optimal and Cache list the field declarations in their
+	// current, minimal-size and cache-friendly order. A declaration that
+	// names several fields, like "x, y int32", is listed by its first name.
+	Current []string
+	Optimal []string
+	Cache   []string
+
+	Hot     []string // hot fields, in Cache order
+	HotSpan int64    // bytes from the start of the struct to the end of the last hot field, in Cache order
+
+	Archs []ArchSize // one per Options.Archs
+
+	// For Rewrite: the source of each field declaration and the permutations
+	// of the declarations into Optimal and Cache order.
+	spans        []span
+	spanErr      error
+	unkeyed      []token.Position // composite literals of the type without field names
+	optimalOrder []int
+	cacheOrder   []int
+}
+
+// Saved returns the bytes per instance the optimal ordering saves.
+func (s Suggestion) Saved() int64 { return s.Size - s.OptimalSize }
+
+// CanShrink reports whether reordering the fields saves memory.
+func (s Suggestion) CanShrink() bool { return s.Saved() > 0 }
+
+// Analyze type-checks the packages matched by pattern and returns a
+// suggestion for every struct type declared in them, in source order.
+// The pattern is a directory, or a directory followed by "/..." to include
+// its subdirectories; test files are not analyzed.
+func Analyze(pattern string, opts Options) ([]Suggestion, error) {
+	c := checker{hot: map[string]bool{}, archs: opts.Archs}
+	for _, arch := range append([]string{cmp.Or(opts.Arch, runtime.GOARCH)}, opts.Archs...) {
+		sizes := types.SizesFor("gc", arch)
+		if sizes == nil {
+			return nil, fmt.Errorf("align: unknown architecture %q", arch)
+		}
+		c.sizes = append(c.sizes, sizes)
+	}
+	for _, h := range opts.Hot {
+		c.hot[h] = true
+	}
+
+	dirs, err := load.Dirs(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("align: %w", err)
+	}
+
+	c.loader = load.New(c.sizes[0])
+	var sugs []Suggestion
+	for _, dir := range dirs {
+		s, err := c.analyzeDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		sugs = append(sugs, s...)
+	}
+	return sugs, nil
+}
+
+// checker holds the state shared by the packages of one Analyze call.
+type checker struct {
+	loader *load.Loader
+	sizes  []types.Sizes // the sizes of Options.Arch, then those of archs
+	archs  []string
+	hot    map[string]bool
+}
+
+// analyzeDir parses and type-checks the package in dir. Directories without
+// Go files yield nothing.
+func (c *checker) analyzeDir(dir string) ([]Suggestion, error) {
+	// Type errors are not fatal: a struct whose field types all check can
+	// still be analyzed, the others are skipped below. The test files of the
+	// package are only searched for unkeyed literals.
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}, Types: map[ast.Expr]types.TypeAndValue{}}
+	pkg, err := c.loader.Load(dir, info, true)
+	if err != nil || pkg == nil {
+		return nil, err
+	}
+	fset, files := c.loader.Fset, pkg.Files
+	unkeyed := unkeyedLiterals(fset, slices.Concat(pkg.Files, pkg.TestFiles), info)
+
+	var sugs []Suggestion
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok || spec.TypeParams != nil || len(st.Fields.List) == 0 {
+				return true
+			}
+			obj := info.Defs[spec.Name]
+			if obj == nil || !validStruct(obj.Type().Underlying()) {
+				return true
+			}
+			typ := obj.Type().Underlying().(*types.Struct)
+			s := suggest(c.sizes[0], typ, st, c.hot, spec.Name.Name)
+			s.Pos, s.Name = fset.Position(spec.Name.Pos()), spec.Name.Name
+			s.spans, s.spanErr = fieldSpans(fset, f, st)
+			s.unkeyed = unkeyed[obj]
+			for i, arch := range c.archs {
+				other := suggest(c.sizes[i+1], typ, st, c.hot, spec.Name.Name)
+				s.Archs = append(s.Archs, ArchSize{Arch: arch, Size: other.Size, OptimalSize: other.OptimalSize})
+			}
+			sugs = append(sugs, s)
+			return true
+		})
+	}
+	return sugs, nil
+}
+
+// unkeyedLiterals returns the positions of the composite literals that list
+// the fields of a named struct type by position, like T{1, 2}, by type name.
+// Reordering the fields of such a type reassigns their values, silently when
+// the fields have the same type. Other packages are not searched: go vet
+// already reports unkeyed literals of imported struct types.
+func unkeyedLiterals(fset *token.FileSet, files []*ast.File, info *types.Info) map[types.Object][]token.Position {
+	unkeyed := map[types.Object][]token.Position{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok || len(lit.Elts) == 0 {
+				return true
+			}
+			if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+				return true
+			}
+			named, ok := types.Unalias(info.TypeOf(lit)).(*types.Named)
+			if ok {
+				if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+					unkeyed[named.Obj()] = append(unkeyed[named.Obj()], fset.Position(lit.Pos()))
+				}
+			}
+			return true
+		})
+	}
+	return unkeyed
+}
+
+// validStruct reports whether t is a struct whose size the type checker
+// could compute.
+func validStruct(t types.Type) bool {
+	st, ok := t.(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := range st.NumFields() {
+		if !validType(st.Field(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func validType(t types.Type) bool {
+	switch t := t.Underlying().(type) {
+	case *types.Basic:
+		return t.Kind() != types.Invalid
+	case *types.Array:
+		return validType(t.Elem())
+	case *types.Struct:
+		return validStruct(t)
+	case *types.TypeParam:
+		return false
+	}
+	return true
+}
+
+// unit is one field declaration; its fields stay together when reordered.
+type unit struct {
+	index int // position in the declaration
+	name  string
+	vars  []*types.Var
+	tags  []string
+	size  int64
+	align int64
+	ptrs  bool
+	hot   bool
+}
+
+// suggest computes the orderings of a struct whose declaration is st.
+func suggest(sizes types.Sizes, t *types.Struct, st *ast.StructType, hot map[string]bool, typeName string) Suggestion {
+	units := make([]unit, 0, len(st.Fields.List))
+	i := 0
+	for j, f := range st.Fields.List {
+		n := max(len(f.Names), 1) // embedded fields have no names
+		u := unit{index: j, name: fieldName(f)}
+		for range n {
+			v := t.Field(i)
+			u.vars = append(u.vars, v)
+			u.tags = append(u.tags, t.Tag(i))
+			u.size += sizes.Sizeof(v.Type())
+			u.align = sizes.Alignof(v.Type())
+			u.ptrs = u.ptrs || hasPointers(v.Type())
+			i++
+		}
+		u.hot = isHot(f)
+		for _, id := range f.Names {
+			u.hot = u.hot || hot[typeName+"."+id.Name]
+		}
+		if len(f.Names) == 0 {
+			u.hot = u.hot || hot[typeName+"."+u.name]
+		}
+		units = append(units, u)
+	}
+
+	optimal := slices.Clone(units)
+	slices.SortStableFunc(optimal, compareUnits)
+
+	// The hot fields go first. The cold ones follow either like in the
+	// optimal ordering or by increasing alignment, which fills the hole the
+	// hot fields leave; whichever is smaller wins.
+	hotFirst := func(cold func(a, b unit) int) []unit {
+		us := slices.Clone(units)
+		slices.SortStableFunc(us, func(a, b unit) int {
+			switch {
+			case a.hot && b.hot:
+				return compareUnits(a, b)
+			case a.hot:
+				return -1
+			case b.hot:
+				return 1
+			}
+			return cold(a, b)
+		})
+		return us
+	}
+	cache := hotFirst(compareUnits)
+	if filling := hotFirst(compareFilling); sizes.Sizeof(structOf(filling)) < sizes.Sizeof(structOf(cache)) {
+		cache = filling
+	}
+
+	s := Suggestion{
+		Size:        sizes.Sizeof(t),
+		OptimalSize: sizes.Sizeof(structOf(optimal)),
+		CacheSize:   sizes.Sizeof(structOf(cache)),
+		Current:     names(units),
+		Optimal:     names(optimal),
+		Cache:       names(cache),
+
+		optimalOrder: indexes(optimal),
+		cacheOrder:   indexes(cache),
+	}
+
+	vars := structVars(cache)
+	offsets := sizes.Offsetsof(vars)
+	k := 0
+	for _, u := range cache {
+		if u.hot {
+			s.Hot = append(s.Hot, u.name)
+			last := k + len(u.vars) - 1
+			s.HotSpan = offsets[last] + sizes.Sizeof(vars[last].Type())
+		}
+		k += len(u.vars)
+	}
+	return s
+}
+
+// compareUnits orders fields for minimal size: zero-size fields first, since
+// a trailing one costs padding, then by decreasing alignment. Within an
+// alignment, fields holding pointers come first so the garbage collector
+// scans a shorter prefix of the struct, and larger fields before smaller.
+func compareUnits(a, b unit) int {
+	if za, zb := a.size == 0, b.size == 0; za != zb {
+		if za {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(b.align, a.align); c != 0 {
+		return c
+	}
+	if a.ptrs != b.ptrs {
+		if a.ptrs {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Compare(b.size, a.size)
+}
+
+// compareFilling orders fields by increasing alignment, zero-size fields
+// still first.
+func compareFilling(a, b unit) int {
+	if za, zb := a.size == 0, b.size == 0; za != zb {
+		if za {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(a.align, b.align); c != 0 {
+		return c
+	}
+	return compareUnits(a, b)
+}
+
+func structVars(units []unit) []*types.Var {
+	var vars []*types.Var
+	for _, u := range units {
+		vars = append(vars, u.vars...)
+	}
+	return vars
+}
+
+func structOf(units []unit) *types.Struct {
+	var tags []string
+	for _, u := range units {
+		tags = append(tags, u.tags...)
+	}
+	return types.NewStruct(structVars(units), tags)
+}
+
+func names(units []unit) []string {
+	result := make([]string, len(units))
+	for i, u := range units {
+		result[i] = u.name
+	}
+	return result
+}
+
+func indexes(units []unit) []int {
+	result := make([]int, len(units))
+	for i, u := range units {
+		result[i] = u.index
+	}
+	return result
+}
+
+// fieldName returns the first name of a field declaration, or the type name
+// of an embedded field.
+func fieldName(f *ast.Field) string {
+	if len(f.Names) > 0 {
+		return f.Names[0].Name
+	}
+	t := f.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return fmt.Sprint(t.X)
+	case *ast.IndexListExpr:
+		return fmt.Sprint(t.X)
+	}
+	return fmt.Sprint(t)
+}
+
+// isHot reports whether a field's comments carry HotMarker.
+func isHot(f *ast.Field) bool {
+	for _, cg := range []*ast.CommentGroup{f.Doc, f.Comment} {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, HotMarker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasPointers reports whether values of t contain pointers the garbage
+// collector has to scan.
+func hasPointers(t types.Type) bool {
+	switch t := t.Underlying().(type) {
+	case *types.Basic:
+		return t.Kind() == types.String || t.Kind() == types.UnsafePointer
+	case *types.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case *types.Struct:
+		for i := range t.NumFields() {
+			if hasPointers(t.Field(i).Type()) {
+				return true
+			}
+		}
+		return false
+	}
+	return true // pointers, slices, maps, channels, funcs and interfaces
+}
@@ -0,0 +1,169 @@
+package align
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func find(t *testing.T, sugs []Suggestion, name string) Suggestion {
+	t.Helper()
+	for _, s := range sugs {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no suggestion for %s in %d results", name, len(sugs))
+	return Suggestion{}
+}
+
+func TestAnalyze(t *testing.T) {
+	for _, tc := range []struct {
+		arch          string
+		size, optimal int64
+	}{
+		{"amd64", 64, 48},
+		{"386", 48, 40},
+	} {
+		sugs, err := Analyze("testdata/sample", Options{Arch: tc.arch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := find(t, sugs, "Order")
+		if s.Size != tc.size || s.OptimalSize != tc.optimal {
+			t.Errorf("%s: Order %d -> %d bytes, want %d -> %d", tc.arch, s.Size, s.OptimalSize, tc.size, tc.optimal)
+		}
+		if p := find(t, sugs, "Packed"); p.CanShrink() {
+			t.Errorf("%s: Packed can shrink by %d bytes", tc.arch, p.Saved())
+		}
+	}
+}
+
+func TestAnalyzeArchs(t *testing.T) {
+	sugs, err := Analyze("testdata/sample", Options{Arch: "amd64", Archs: []string{"arm64", "arm"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ArchSize{{"arm64", 64, 48}, {"arm", 48, 40}}
+	if got := find(t, sugs, "Order").Archs; !slices.Equal(got, want) {
+		t.Errorf("Archs = %v, want %v", got, want)
+	}
+	if _, err := Analyze("testdata/sample", Options{Archs: []string{"z80"}}); err == nil {
+		t.Error("Analyze with z80 succeeded")
+	}
+}
+
+func TestOrderings(t *testing.T) {
+	sugs, err := Analyze("testdata/sample", Options{Arch: "amd64", Hot: []string{"Order.Express"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := find(t, sugs, "Order")
+
+	wantOptimal := []string{"Placed", "ID", "Total", "x", "Paid", "Express"}
+	if !slices.Equal(s.Optimal, wantOptimal) {
+		t.Errorf("Optimal = %v, want %v", s.Optimal, wantOptimal)
+	}
+	// ID is marked in a comment, Express on the command line.
+	wantCache := []string{"ID", "Express", "x", "Paid", "Placed", "Total"}
+	if !slices.Equal(s.Cache, wantCache) {
+		t.Errorf("Cache = %v, want %v", s.Cache, wantCache)
+	}
+	if s.HotSpan != 9 || s.CacheSize != 48 {
+		t.Errorf("HotSpan = %d, CacheSize = %d, want 9 and 48", s.HotSpan, s.CacheSize)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	src, err := os.ReadFile("testdata/sample/sample.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(file, src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sugs, err := Analyze(dir, Options{Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := Rewrite(sugs, BySize)
+	if len(changed) != 1 || changed[0] != file {
+		t.Fatalf("changed = %v", changed)
+	}
+	if err != nil {
+		t.Fatalf("Rewrite: %v", err)
+	}
+
+	sugs, err = Analyze(dir, Options{Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := find(t, sugs, "Order"); s.Size != 48 {
+		t.Errorf("rewritten Order is %d bytes, want 48", s.Size)
+	}
+
+	got, _ := os.ReadFile(file)
+	for _, want := range []string{
+		"\t// Paid is set once the payment cleared.\n\tPaid",
+		"ID     int64   `json:\"id\"` // align:hot",
+		"`json:\"total\"`",
+		"x, y   int8",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("rewritten source lost %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRewriteSkipsLooseComments(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\ntype Grouped struct {\n\tA bool\n\n\t// Heading.\n\n\tB int64\n\tC bool\n}\n"
+	file := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sugs, err := Analyze(dir, Options{Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := Rewrite(sugs, BySize)
+	if err == nil || len(changed) != 0 {
+		t.Errorf("Rewrite = %v, %v; want an error and no change", changed, err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != src {
+		t.Errorf("file changed:\n%s", got)
+	}
+}
+
+func TestRewriteRefusesUnkeyedLiterals(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\ntype Pair struct {\n\tA bool\n\tB int64\n\tC bool\n}\n\ntype Keyed struct {\n\tA bool\n\tB int64\n\tC bool\n}\n\n" +
+		"var _ = Keyed{A: true, B: 1}\n"
+	test := "package p\n\nvar pairs = []Pair{{true, 1, false}}\n"
+	file := filepath.Join(dir, "p.go")
+	for name, data := range map[string]string{file: src, filepath.Join(dir, "p_test.go"): test} {
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sugs, err := Analyze(dir, Options{Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := Rewrite(sugs, BySize)
+	if err == nil || !strings.Contains(err.Error(), "Pair not rewritten: unkeyed composite literal at "+filepath.Join(dir, "p_test.go")+":3:") {
+		t.Errorf("Rewrite error = %v, want the unkeyed Pair literal reported", err)
+	}
+	if len(changed) != 1 {
+		t.Fatalf("changed = %v, want Keyed still rewritten", changed)
+	}
+	got, _ := os.ReadFile(file)
+	if !strings.Contains(string(got), "type Pair struct {\n\tA bool\n\tB int64") {
+		t.Errorf("Pair was reordered:\n%s", got)
+	}
+}
@@ -0,0 +1,145 @@
+package align
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Order selects the ordering Rewrite applies.
+type Order string
+
+const (
+	// BySize applies the minimal-size ordering to the structs that shrink.
+	BySize Order = "size"
+	// ByCache applies the cache-friendly ordering to the structs with hot fields.
+	ByCache Order = "cache"
+)
+
+// ParseOrder validates an --order value.
+func ParseOrder(s string) (Order, error) {
+	switch o := Order(strings.ToLower(s)); o {
+	case BySize, ByCache:
+		return o, nil
+	}
+	return "", fmt.Errorf("unknown order %q (want size or cache)", s)
+}
+
+// span is the source of a field declaration, from its doc comment to its line
+// comment, as byte offsets into the file.
+type span struct {
+	start, end int
+}
+
+// fieldSpans returns the source spans of the fields of st. Comments that
+// belong to no field, like a blank-line separated group heading, have no
+// place in a reordered struct, so such structs are not rewritten.
+func fieldSpans(fset *token.FileSet, f *ast.File, st *ast.StructType) ([]span, error) {
+	tf := fset.File(f.Pos())
+	attached := map[*ast.CommentGroup]bool{}
+	var spans []span
+	for _, field := range st.Fields.List {
+		start, end := field.Pos(), field.End()
+		if field.Doc != nil {
+			start = field.Doc.Pos()
+			attached[field.Doc] = true
+		}
+		if field.Comment != nil {
+			end = field.Comment.End()
+			attached[field.Comment] = true
+		}
+		spans = append(spans, span{tf.Offset(start), tf.Offset(end)})
+	}
+
+	for _, cg := range f.Comments {
+		if cg.Pos() > st.Fields.Opening && cg.End() < st.Fields.Closing && !attached[cg] {
+			return spans, fmt.Errorf("%s: comment not attached to a field", fset.Position(cg.Pos()))
+		}
+	}
+	return spans, nil
+}
+
+// Rewrite reorders the field declarations of the suggested structs in their
+// source files, keeping doc comments, line comments and tags with their
+// fields, and formats the files like gofmt. It returns the names of the files
+// it changed. Structs it cannot reorder safely, including those with unkeyed
+// composite literals in their package, are reported in the error and left
+// alone; the other structs are still rewritten.
+func Rewrite(sugs []Suggestion, order Order) ([]string, error) {
+	byFile := map[string][]Suggestion{}
+	var errs []error
+	for _, s := range sugs {
+		perm := s.optimalOrder
+		if order == ByCache {
+			if len(s.Hot) == 0 {
+				continue
+			}
+			perm = s.cacheOrder
+		} else if !s.CanShrink() {
+			continue
+		}
+		if slices.IsSorted(perm) {
+			continue // already in that order
+		}
+		if s.spanErr != nil {
+			errs = append(errs, fmt.Errorf("%s not rewritten: %w", s.Name, s.spanErr))
+			continue
+		}
+		if len(s.unkeyed) > 0 {
+			errs = append(errs, fmt.Errorf("%s not rewritten: unkeyed composite literal at %s would assign its values to other fields (%d in total)",
+				s.Name, s.unkeyed[0], len(s.unkeyed)))
+			continue
+		}
+		byFile[s.Pos.Filename] = append(byFile[s.Pos.Filename], s)
+	}
+
+	var changed []string
+	for _, file := range slices.Sorted(maps.Keys(byFile)) {
+		if err := rewriteFile(file, byFile[file], order); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, file)
+	}
+	return changed, errors.Join(errs...)
+}
+
+// rewriteFile applies the reorderings of one file, last struct first so the
+// offsets of the earlier ones stay valid.
+func rewriteFile(file string, sugs []Suggestion, order Order) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(sugs, func(a, b Suggestion) int { return cmp.Compare(b.spans[0].start, a.spans[0].start) })
+	for _, s := range sugs {
+		perm := s.optimalOrder
+		if order == ByCache {
+			perm = s.cacheOrder
+		}
+		fields := make([]string, len(perm))
+		for i, j := range perm {
+			fields[i] = string(src[s.spans[j].start:s.spans[j].end])
+		}
+		start, end := s.spans[0].start, s.spans[len(s.spans)-1].end
+		src = slices.Concat(src[:start:start], []byte(strings.Join(fields, "\n")), src[end:])
+	}
+
+	out, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s: formatting rewritten source: %w", file, err)
+	}
+	return os.WriteFile(file, out, info.Mode().Perm())
+}
@@ -0,0 +1,20 @@
+package sample
+
+import "time"
+
+// Order wastes 16 bytes on padding on 64-bit platforms.
+type Order struct {
+	// Paid is set once the payment cleared.
+	Paid    bool
+	ID      int64 `json:"id"` // align:hot
+	Express bool
+	Total   float64 `json:"total"`
+	Placed  time.Time
+	x, y    int8
+}
+
+// Packed is already optimal.
+type Packed struct {
+	ID   int64
+	Flag bool
+}
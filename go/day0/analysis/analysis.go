@@ -0,0 +1,76 @@
+// Package analysis is the subset of golang.org/x/tools/go/analysis the
+// analyzers of this module need: an Analyzer runs on one type-checked package
+// and reports diagnostics through its Pass.
+//
+// The module has no dependencies, so the types are declared here with the
+// same names and fields as upstream; an analyzer ports to x/tools by changing
+// its import path. Facts, suggested fixes and analyzer prerequisites are not
+// supported.
+//
+// Analyzers run standalone on source packages through package checker, and
+// under `go vet -vettool` through package unitchecker.
+package analysis
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// An Analyzer describes an analysis function and its options.
+type Analyzer struct {
+	// Name is the name of the analyzer; it prefixes its flags on the
+	// command line, e.g. -copysize.threshold.
+	Name string
+	// Doc is the documentation; the first line is a one-line summary.
+	Doc string
+	// Flags are the analyzer's options.
+	Flags flag.FlagSet
+	// Run applies the analyzer to a package.
+	Run func(*Pass) (any, error)
+}
+
+func (a *Analyzer) String() string { return a.Name }
+
+// A Pass provides information to the Run function that applies a specific
+// analyzer to a single Go package.
+type Pass struct {
+	Analyzer   *Analyzer
+	Fset       *token.FileSet
+	Files      []*ast.File
+	Pkg        *types.Package
+	TypesInfo  *types.Info
+	TypesSizes types.Sizes
+
+	// Report reports a Diagnostic.
+	Report func(Diagnostic)
+}
+
+// Reportf is a helper function that reports a Diagnostic using the
+// specified position and formatted error message.
+func (pass *Pass) Reportf(pos token.Pos, format string, args ...any) {
+	pass.Report(Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// A Diagnostic is a message associated with a source location or range.
+type Diagnostic struct {
+	Pos      token.Pos
+	End      token.Pos // optional
+	Category string    // optional
+	Message  string
+}
+
+// NewInfo returns a types.Info with every map an analyzer may consult.
+func NewInfo() *types.Info {
+	return &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Instances:  map[*ast.Ident]types.Instance{},
+		Scopes:     map[ast.Node]*types.Scope{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+}
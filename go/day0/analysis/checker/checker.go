@@ -0,0 +1,82 @@
+// Package checker runs analyzers on packages loaded from source, for use
+// outside `go vet`:
+//
+//	findings, err := checker.Run([]string{"./..."}, copysize.Analyzer)
+package checker
+
+import (
+	"cmp"
+	"fmt"
+	"go/build"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"day0/analysis"
+	"day0/analysis/load"
+)
+
+// Finding is a diagnostic with its position resolved.
+type Finding struct {
+	Analyzer string
+	Pos      token.Position
+	Category string
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+// Run loads the packages matched by the patterns - directories, optionally
+// followed by "/..." for their subdirectories - and applies each analyzer to
+// each package. Test files are not analyzed. Findings are sorted by position.
+func Run(patterns []string, analyzers ...*analysis.Analyzer) ([]Finding, error) {
+	var dirs []string
+	for _, p := range patterns {
+		d, err := load.Dirs(p)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d...)
+	}
+
+	l := load.New(types.SizesFor("gc", build.Default.GOARCH))
+	var findings []Finding
+	for _, dir := range dirs {
+		f, err := runDir(l, dir, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, f...)
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(cmp.Compare(a.Pos.Filename, b.Pos.Filename), cmp.Compare(a.Pos.Offset, b.Pos.Offset))
+	})
+	return findings, nil
+}
+
+// runDir type-checks the package in dir and runs the analyzers on it.
+func runDir(l *load.Loader, dir string, analyzers []*analysis.Analyzer) ([]Finding, error) {
+	pkg, err := l.Load(dir, analysis.NewInfo(), false)
+	if err != nil || pkg == nil {
+		return nil, err
+	}
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("type-checking %s: %w", dir, pkg.Errors[0])
+	}
+
+	var findings []Finding
+	for _, a := range analyzers {
+		pass := &analysis.Pass{
+			Analyzer: a, Fset: l.Fset, Files: pkg.Files, Pkg: pkg.Types, TypesInfo: pkg.Info, TypesSizes: l.Sizes,
+			Report: func(d analysis.Diagnostic) {
+				findings = append(findings, Finding{Analyzer: a.Name, Pos: l.Fset.Position(d.Pos), Category: d.Category, Message: d.Message})
+			},
+		}
+		if _, err := a.Run(pass); err != nil {
+			return nil, fmt.Errorf("%s on %s: %w", a.Name, pkg.ImportPath, err)
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,111 @@
+// Package load finds the packages matched by directory patterns and parses
+// and type-checks them from source. It is the loader behind the analysis
+// checker and the align command, so both read the same packages the same way.
+package load
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Dirs expands a pattern - a directory, optionally followed by "/..." for
+// its subdirectories - into package directories. Like the go command, it
+// skips testdata and vendor directories and those starting with "." or "_".
+func Dirs(pattern string) ([]string, error) {
+	root, recursive := strings.CutSuffix(pattern, "/...")
+	if pattern == "..." {
+		root, recursive = ".", true
+	}
+	root = cmp.Or(root, ".")
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+	if !recursive {
+		return []string{root}, nil
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if name := d.Name(); path != root && (name == "testdata" || name == "vendor" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
+
+// Loader loads packages into one file set, resolving imports from source.
+type Loader struct {
+	Fset  *token.FileSet
+	Sizes types.Sizes
+	imp   types.Importer
+}
+
+// New returns a loader that type-checks with sizes.
+func New(sizes types.Sizes) *Loader {
+	fset := token.NewFileSet()
+	return &Loader{Fset: fset, Sizes: sizes, imp: importer.ForCompiler(fset, "source", nil)}
+}
+
+// Package is a package loaded from source.
+type Package struct {
+	Dir        string
+	ImportPath string
+	Files      []*ast.File // the non-test files
+	TestFiles  []*ast.File // the _test.go files of the package itself, if requested
+	Types      *types.Package
+	Info       *types.Info
+	Errors     []error // type errors; the package is checked as far as possible
+}
+
+// Load parses the package in dir and type-checks it, recording into info.
+// With tests, the _test.go files of the package itself are checked along
+// with it; external test packages are not loaded. A directory without Go
+// files yields nil and no error.
+func (l *Loader) Load(dir string, info *types.Info, tests bool) (*Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		if _, ok := errors.AsType[*build.NoGoError](err); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := bp.GoFiles
+	if tests {
+		names = slices.Concat(bp.GoFiles, bp.TestGoFiles)
+	}
+	var files []*ast.File
+	for _, name := range names {
+		f, err := parser.ParseFile(l.Fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	p := &Package{Dir: dir, ImportPath: bp.ImportPath, Info: info}
+	conf := types.Config{Importer: l.imp, Sizes: l.Sizes, Error: func(err error) { p.Errors = append(p.Errors, err) }}
+	p.Types, _ = conf.Check(bp.ImportPath, l.Fset, files, info)
+	p.Files, p.TestFiles = files[:len(bp.GoFiles)], files[len(bp.GoFiles):]
+	return p, nil
+}
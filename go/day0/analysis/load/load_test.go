@@ -0,0 +1,67 @@
+package load
+
+import (
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a/b", "testdata/x", "vendor/y", ".git", "_old", "c"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs, err := Dirs(root + "/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rel []string
+	for _, d := range dirs {
+		r, _ := filepath.Rel(root, d)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	if want := []string{".", "a", "a/b", "c"}; !slices.Equal(rel, want) {
+		t.Errorf("Dirs = %q, want %q", rel, want)
+	}
+	if dirs, err := Dirs(root); err != nil || !slices.Equal(dirs, []string{root}) {
+		t.Errorf("Dirs(%s) = %q, %v; want just the root", root, dirs, err)
+	}
+	if _, err := Dirs(filepath.Join(root, "missing")); err == nil {
+		t.Error("Dirs of a missing directory succeeded")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"p.go":      "package p\n\ntype T struct{ A int }\n",
+		"p_test.go": "package p\n\nvar _ = T{1}\n",
+		"bad.go":    "package p\n\nvar _ int = \"not an int\"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := New(types.SizesFor("gc", "amd64"))
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	pkg, err := l.Load(dir, info, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Files) != 2 || len(pkg.TestFiles) != 1 {
+		t.Errorf("loaded %d files and %d test files, want 2 and 1", len(pkg.Files), len(pkg.TestFiles))
+	}
+	if len(pkg.Errors) != 1 || pkg.Types.Scope().Lookup("T") == nil {
+		t.Errorf("Errors = %v, want the one type error and T still checked", pkg.Errors)
+	}
+
+	if pkg, err := l.Load(t.TempDir(), info, false); pkg != nil || err != nil {
+		t.Errorf("Load of an empty directory = %v, %v; want nil, nil", pkg, err)
+	}
+}
@@ -0,0 +1,289 @@
+// Package unitchecker implements the protocol `go vet -vettool` uses to run
+// a program's analyzers on each package of a build:
+//
+//	-V=full    describe the executable, for the build cache
+//	-flags     describe the flags, as JSON
+//	foo.cfg    analyze the package described by the JSON config file
+//
+// The go command compiles the dependencies and hands over their export data,
+// so the package is type-checked exactly as the compiler saw it. go vet asks
+// for -json and prints the diagnostics itself; without it they go to stderr
+// as "file:line:col: message".
+package unitchecker
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"day0/analysis"
+)
+
+// Config describes a compilation unit; go vet writes it as JSON to a file
+// whose name ends in ".cfg". Fields for facts and fixes are ignored.
+type Config struct {
+	ID                        string // e.g. "fmt [fmt.test]"
+	Compiler                  string // gc or gccgo
+	ImportPath                string
+	GoVersion                 string
+	GoFiles                   []string
+	NonGoFiles                []string
+	ImportMap                 map[string]string // import path to package path
+	PackageFile               map[string]string // package path to export data file
+	VetxOnly                  bool              // analyze only for facts
+	VetxOutput                string            // where to write the facts
+	Stdout                    string            // where to write the JSON output
+	SucceedOnTypecheckFailure bool
+}
+
+// IsInvocation reports whether args, without the program name, are a call
+// from go vet rather than a command line typed by a user.
+func IsInvocation(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	return slices.Contains(args, "-V=full") || slices.Contains(args, "-flags") ||
+		strings.HasSuffix(args[len(args)-1], ".cfg")
+}
+
+// Main runs the protocol on args, without the program name, and returns the
+// exit status: 0 when there was nothing to report, 1 otherwise.
+func Main(args []string, stdout, stderr io.Writer, analyzers ...*analysis.Analyzer) int {
+	fs := flag.NewFlagSet("vettool", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	version := fs.String("V", "", "print version and exit")
+	printFlags := fs.Bool("flags", false, "print analyzer flags in JSON")
+	jsonOut := fs.Bool("json", false, "emit JSON output")
+	// No analyzer suggests fixes, so there is nothing to apply or show.
+	fs.Bool("fix", false, "apply all suggested fixes (none are supported)")
+	fs.Bool("diff", false, "with -fix, print the fixes as diffs instead of applying them")
+	fs.Int("c", -1, "display offending line with this many lines of context (ignored)")
+	for _, a := range analyzers {
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			fs.Var(f.Value, a.Name+"."+f.Name, f.Usage)
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	switch {
+	case *version != "":
+		return printVersion(stdout, stderr, *version)
+	case *printFlags:
+		return describeFlags(fs, stdout)
+	case fs.NArg() != 1 || !strings.HasSuffix(fs.Arg(0), ".cfg"):
+		fmt.Fprintln(stderr, `vettool: expected a single .cfg file; run as "go vet -vettool=$(which day0) ./..."`)
+		return 1
+	}
+
+	cfg, err := readConfig(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, "vettool:", err)
+		return 1
+	}
+	// No analyzer here produces facts, but go vet expects the file.
+	if cfg.VetxOutput != "" {
+		if err := os.WriteFile(cfg.VetxOutput, nil, 0o666); err != nil {
+			fmt.Fprintln(stderr, "vettool:", err)
+			return 1
+		}
+	}
+	if cfg.VetxOnly {
+		return 0
+	}
+	if cfg.Stdout != "" {
+		f, err := os.Create(cfg.Stdout)
+		if err != nil {
+			fmt.Fprintln(stderr, "vettool:", err)
+			return 1
+		}
+		defer f.Close()
+		stdout = f
+	}
+
+	fset := token.NewFileSet()
+	diags, err := run(fset, cfg, analyzers)
+	if err != nil && cfg.SucceedOnTypecheckFailure {
+		return 0
+	}
+	if *jsonOut {
+		return printJSON(stdout, stderr, fset, cfg, diags, err)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "vettool:", err)
+		return 1
+	}
+	for _, d := range diags {
+		fmt.Fprintf(stderr, "%s: %s\n", fset.Position(d.Pos), d.Message)
+	}
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printJSON writes the diagnostics, or the error, of the package as the tree
+// go vet parses: package ID to analyzer name to a list of diagnostics or an
+// error object. Problems are reported in the tree, so the exit status is 0.
+func printJSON(stdout, stderr io.Writer, fset *token.FileSet, cfg *Config, diags []namedDiagnostic, err error) int {
+	type jsonDiagnostic struct {
+		Category string `json:"category,omitempty"`
+		Posn     string `json:"posn"`
+		End      string `json:"end,omitempty"`
+		Message  string `json:"message"`
+	}
+	units := map[string]any{}
+	if err != nil {
+		units["typecheck"] = map[string]string{"error": err.Error()}
+	}
+	for _, d := range diags {
+		list, _ := units[d.analyzer].([]jsonDiagnostic)
+		jd := jsonDiagnostic{Category: d.Category, Posn: fset.Position(d.Pos).String(), Message: d.Message}
+		if d.End.IsValid() {
+			jd.End = fset.Position(d.End).String()
+		}
+		units[d.analyzer] = append(list, jd)
+	}
+	tree := map[string]any{}
+	if len(units) > 0 {
+		tree[cmp.Or(cfg.ID, cfg.ImportPath)] = units
+	}
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		fmt.Fprintln(stderr, "vettool:", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s\n", data)
+	return 0
+}
+
+// printVersion prints the executable's content hash, which go vet uses as
+// part of its cache key.
+func printVersion(stdout, stderr io.Writer, mode string) int {
+	if mode != "full" {
+		fmt.Fprintf(stderr, "vettool: unsupported flag value: -V=%s (use -V=full)\n", mode)
+		return 1
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(stderr, "vettool:", err)
+		return 1
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil {
+		fmt.Fprintln(stderr, "vettool:", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s version devel comments-go-here buildID=%02x\n", exe, sha256.Sum256(data))
+	return 0
+}
+
+// describeFlags prints the flags go vet may pass through to the tool.
+func describeFlags(fs *flag.FlagSet, stdout io.Writer) int {
+	type jsonFlag struct {
+		Name  string
+		Bool  bool
+		Usage string
+	}
+	var flags []jsonFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "V" || f.Name == "flags" {
+			return
+		}
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, jsonFlag{f.Name, ok && b.IsBoolFlag(), f.Usage})
+	})
+	data, err := json.MarshalIndent(flags, "", "\t")
+	if err != nil {
+		return 1
+	}
+	stdout.Write(data)
+	return 0
+}
+
+func readConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot decode JSON config file %s: %w", file, err)
+	}
+	if len(cfg.GoFiles) == 0 {
+		return nil, fmt.Errorf("package has no files: %s", cfg.ImportPath)
+	}
+	return cfg, nil
+}
+
+// run type-checks the package against the export data of its dependencies
+// and applies the analyzers.
+func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]namedDiagnostic, error) {
+	var files []*ast.File
+	for _, name := range cfg.GoFiles {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	compiler := importer.ForCompiler(fset, cfg.Compiler, func(path string) (io.ReadCloser, error) {
+		file, ok := cfg.PackageFile[path]
+		if !ok {
+			return nil, fmt.Errorf("no package file for %q", path)
+		}
+		return os.Open(file)
+	})
+	conf := types.Config{
+		Importer: importerFunc(func(importPath string) (*types.Package, error) {
+			path, ok := cfg.ImportMap[importPath]
+			if !ok {
+				return nil, fmt.Errorf("can't resolve import %q", importPath)
+			}
+			return compiler.Import(path)
+		}),
+		Sizes:     types.SizesFor("gc", build.Default.GOARCH),
+		GoVersion: cfg.GoVersion,
+	}
+	info := analysis.NewInfo()
+	pkg, err := conf.Check(cfg.ImportPath, fset, files, info)
+	if err != nil {
+		return nil, err
+	}
+
+	var diags []namedDiagnostic
+	for _, a := range analyzers {
+		pass := &analysis.Pass{
+			Analyzer: a, Fset: fset, Files: files, Pkg: pkg, TypesInfo: info, TypesSizes: conf.Sizes,
+			Report: func(d analysis.Diagnostic) { diags = append(diags, namedDiagnostic{d, a.Name}) },
+		}
+		if _, err := a.Run(pass); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+	}
+	return diags, nil
+}
+
+// namedDiagnostic is a diagnostic with the name of the analyzer reporting it.
+type namedDiagnostic struct {
+	analysis.Diagnostic
+	analyzer string
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
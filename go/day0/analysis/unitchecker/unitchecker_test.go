@@ -0,0 +1,58 @@
+package unitchecker
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"day0/analysis"
+)
+
+func TestIsInvocation(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"run", "pool*"}, false},
+		{[]string{"vet", "./..."}, false},
+		{[]string{"-V=full"}, true},
+		{[]string{"-flags"}, true},
+		{[]string{"-json", "/tmp/b001/vet.cfg"}, true},
+	} {
+		if got := IsInvocation(tc.args); got != tc.want {
+			t.Errorf("IsInvocation(%q) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	a := &analysis.Analyzer{Name: "demo"}
+	a.Flags.Init("demo", flag.ContinueOnError)
+	a.Flags.Int("limit", 1, "a limit")
+
+	var out strings.Builder
+	if code := Main([]string{"-flags"}, &out, io.Discard, a); code != 0 {
+		t.Fatalf("exit status %d", code)
+	}
+	var flags []struct {
+		Name string
+		Bool bool
+	}
+	if err := json.Unmarshal([]byte(out.String()), &flags); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range flags {
+		names = append(names, f.Name)
+	}
+	// go vet passes -json; the analyzer flags are prefixed with its name.
+	for _, want := range []string{"json", "demo.limit"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("flags %v lack %s", names, want)
+		}
+	}
+}
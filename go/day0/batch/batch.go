@@ -0,0 +1,390 @@
+// Package batch provides Batcher[T], which groups items added one at a time
+// into batches for a flush function: when a batch holds enough items, enough
+// bytes, or has waited long enough, whichever comes first.
+//
+// Batching trades latency for throughput, and the triggers are where that
+// trade is made. The counters record why each batch was sent and how large
+// batches turned out, so the trade can be checked instead of guessed: a
+// batcher that mostly flushes on its timer is sending small batches late.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrClosed is the error of Add and Flush after Close.
+var ErrClosed = errors.New("batch: batcher closed")
+
+// FlushFunc sends one batch. The batcher reuses items once it returns, so a
+// FlushFunc that keeps them must copy the slice.
+type FlushFunc[T any] func(ctx context.Context, items []T) error
+
+// Options are the triggers and limits of a Batcher.
+type Options[T any] struct {
+	// MaxItems flushes a batch once it holds this many items. It defaults
+	// to 100 when neither MaxItems nor MaxBytes is set.
+	MaxItems int
+	// MaxBytes flushes a batch before the item that would take it past this
+	// many bytes, as measured by Size. An item larger than MaxBytes is sent
+	// in a batch of its own.
+	MaxBytes int
+	// Size returns the size of an item in bytes; MaxBytes requires it.
+	Size func(T) int
+	// MaxLinger flushes a batch once its oldest item has waited this long.
+	// Zero disables the timer: partial batches wait for Flush or Close.
+	MaxLinger time.Duration
+	// QueueSize is how many added items may wait for the batcher while it
+	// flushes before Add blocks. It defaults to MaxItems, or 100.
+	QueueSize int
+}
+
+// Reason is why a batch was flushed.
+type Reason int
+
+const (
+	ReasonItems  Reason = iota // the batch reached MaxItems
+	ReasonBytes                // the batch reached MaxBytes
+	ReasonLinger               // the oldest item waited MaxLinger
+	ReasonFlush                // Flush was called
+	ReasonClose                // Close was called
+	numReasons
+)
+
+var reasonNames = [numReasons]string{"items", "bytes", "linger", "flush", "close"}
+
+func (r Reason) String() string {
+	if r < 0 || r >= numReasons {
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+	return reasonNames[r]
+}
+
+// Batcher groups items into batches and flushes them from one goroutine, so
+// at most one batch is in flight. While it flushes, added items wait in a
+// bounded queue, and once that is full Add blocks: a slow flush slows down
+// the producers instead of growing memory.
+//
+// A Batcher is safe for concurrent use. Its FlushFunc must not call Add,
+// Flush or Close, which wait for the flushing goroutine.
+type Batcher[T any] struct {
+	flush     FlushFunc[T]
+	maxItems  int
+	maxBytes  int
+	size      func(T) int
+	maxLinger time.Duration
+
+	queue   chan T
+	flushes chan request
+	closing chan request
+	stopped chan struct{}
+
+	mu     sync.RWMutex // held for reading by Add, for writing by Close
+	closed bool
+
+	// Owned by the flushing goroutine.
+	pending []T
+	bytes   int
+	timer   *time.Timer
+	err     error // first error since the last Flush or Close request
+
+	statsMu sync.Mutex
+	stats   Stats
+}
+
+// request asks the flushing goroutine to flush now.
+type request struct {
+	ctx  context.Context
+	done chan error
+}
+
+// New starts a batcher that sends its batches to flush.
+func New[T any](flush FlushFunc[T], opts Options[T]) *Batcher[T] {
+	if flush == nil {
+		panic("batch: New with nil flush function")
+	}
+	if opts.MaxBytes > 0 && opts.Size == nil {
+		panic("batch: MaxBytes without Size")
+	}
+	if opts.MaxItems <= 0 && opts.MaxBytes <= 0 {
+		opts.MaxItems = 100
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = opts.MaxItems
+		if opts.QueueSize <= 0 {
+			opts.QueueSize = 100
+		}
+	}
+	b := &Batcher[T]{
+		flush:     flush,
+		maxItems:  opts.MaxItems,
+		maxBytes:  opts.MaxBytes,
+		size:      opts.Size,
+		maxLinger: opts.MaxLinger,
+		queue:     make(chan T, opts.QueueSize),
+		flushes:   make(chan request),
+		closing:   make(chan request),
+		stopped:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add queues an item for the next batch. It blocks while the queue is full,
+// until ctx is done.
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	select {
+	case b.queue <- item:
+		return nil
+	default:
+	}
+
+	b.statsMu.Lock()
+	b.stats.Blocked++
+	b.statsMu.Unlock()
+	select {
+	case b.queue <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush sends every item added before it and returns once they are sent,
+// with the first error of the batches sent since the previous Flush. The
+// last batch is sent with ctx.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	return b.ask(ctx, b.flushes)
+}
+
+// Close stops accepting items, sends the ones already added and stops the
+// batcher. If ctx is done first, Close returns its error and the batcher
+// finishes in the background. Closing twice waits for the first Close.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	// Waits for the Adds in progress, so none can queue after the drain.
+	b.mu.Lock()
+	closed := b.closed
+	b.closed = true
+	b.mu.Unlock()
+	if !closed {
+		return b.ask(ctx, b.closing)
+	}
+	select {
+	case <-b.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ask sends a request to the flushing goroutine and waits for its answer.
+func (b *Batcher[T]) ask(ctx context.Context, ch chan request) error {
+	req := request{ctx: ctx, done: make(chan error, 1)}
+	select {
+	case ch <- req:
+	case <-b.stopped:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run is the flushing goroutine.
+func (b *Batcher[T]) run() {
+	defer close(b.stopped)
+	b.timer = time.NewTimer(time.Hour)
+	b.timer.Stop()
+	var linger <-chan time.Time
+	for {
+		if len(b.pending) > 0 && b.maxLinger > 0 {
+			linger = b.timer.C
+		} else {
+			linger = nil
+		}
+		select {
+		case item := <-b.queue:
+			b.add(item)
+		case <-linger:
+			b.send(context.Background(), ReasonLinger)
+		case req := <-b.flushes:
+			req.done <- b.sendAll(req.ctx, ReasonFlush)
+		case req := <-b.closing:
+			req.done <- b.sendAll(req.ctx, ReasonClose)
+			return
+		}
+	}
+}
+
+// sendAll adds the items waiting in the queue, sends the pending batch and
+// returns the first error since the previous call.
+func (b *Batcher[T]) sendAll(ctx context.Context, reason Reason) error {
+	for len(b.queue) > 0 {
+		b.add(<-b.queue)
+	}
+	b.send(ctx, reason)
+	err := b.err
+	b.err = nil
+	return err
+}
+
+// add appends an item to the pending batch and sends the batch when it is
+// full.
+func (b *Batcher[T]) add(item T) {
+	size := 0
+	if b.maxBytes > 0 {
+		size = b.size(item)
+		if len(b.pending) > 0 && b.bytes+size > b.maxBytes {
+			b.send(context.Background(), ReasonBytes)
+		}
+	}
+	if len(b.pending) == 0 && b.maxLinger > 0 {
+		b.timer.Reset(b.maxLinger)
+	}
+	b.pending = append(b.pending, item)
+	b.bytes += size
+
+	switch {
+	case b.maxItems > 0 && len(b.pending) >= b.maxItems:
+		b.send(context.Background(), ReasonItems)
+	case b.maxBytes > 0 && b.bytes >= b.maxBytes:
+		b.send(context.Background(), ReasonBytes)
+	}
+}
+
+// send flushes the pending batch, if any, and records it.
+func (b *Batcher[T]) send(ctx context.Context, reason Reason) {
+	if len(b.pending) == 0 {
+		return
+	}
+	b.timer.Stop()
+	err := b.flush(ctx, b.pending)
+
+	b.statsMu.Lock()
+	b.stats.Items += uint64(len(b.pending))
+	b.stats.Bytes += uint64(b.bytes)
+	b.stats.Batches++
+	b.stats.Reasons[reason]++
+	b.stats.Sizes.add(len(b.pending))
+	if b.maxBytes > 0 {
+		b.stats.ByteSizes.add(b.bytes)
+	}
+	if err != nil {
+		b.stats.Errors++
+	}
+	b.statsMu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+
+	clear(b.pending)
+	b.pending = b.pending[:0]
+	b.bytes = 0
+}
+
+// Stats returns the counters of the batcher so far.
+func (b *Batcher[T]) Stats() Stats {
+	b.statsMu.Lock()
+	defer b.statsMu.Unlock()
+	return b.stats
+}
+
+// Stats are the counters of a Batcher.
+type Stats struct {
+	Items     uint64             // items flushed
+	Bytes     uint64             // bytes flushed, as measured by Size
+	Batches   uint64             // batches flushed
+	Errors    uint64             // batches whose flush failed
+	Blocked   uint64             // Adds that waited for room in the queue
+	Reasons   [numReasons]uint64 // batches by Reason
+	Sizes     Histogram          // batches by number of items
+	ByteSizes Histogram          // batches by bytes, when MaxBytes is set
+}
+
+// MeanSize is the average number of items per batch.
+func (s Stats) MeanSize() float64 {
+	if s.Batches == 0 {
+		return 0
+	}
+	return float64(s.Items) / float64(s.Batches)
+}
+
+// Sub returns the difference between s and an earlier snapshot.
+func (s Stats) Sub(earlier Stats) Stats {
+	d := Stats{
+		Items:   s.Items - earlier.Items,
+		Bytes:   s.Bytes - earlier.Bytes,
+		Batches: s.Batches - earlier.Batches,
+		Errors:  s.Errors - earlier.Errors,
+		Blocked: s.Blocked - earlier.Blocked,
+	}
+	for r := range s.Reasons {
+		d.Reasons[r] = s.Reasons[r] - earlier.Reasons[r]
+	}
+	for i := range s.Sizes {
+		d.Sizes[i] = s.Sizes[i] - earlier.Sizes[i]
+		d.ByteSizes[i] = s.ByteSizes[i] - earlier.ByteSizes[i]
+	}
+	return d
+}
+
+func (s Stats) String() string {
+	var reasons []string
+	for r, n := range s.Reasons {
+		if n > 0 {
+			reasons = append(reasons, fmt.Sprintf("%v %d", Reason(r), n))
+		}
+	}
+	return fmt.Sprintf("%d items in %d batches (mean %.1f, flushed on %s), %d errors, %d blocked adds",
+		s.Items, s.Batches, s.MeanSize(), strings.Join(reasons, ", "), s.Errors, s.Blocked)
+}
+
+// HistogramBuckets is the number of buckets of a Histogram.
+const HistogramBuckets = 32
+
+// Histogram counts batches by size in power-of-two buckets: bucket i holds
+// the sizes up to Bound(i) and above Bound(i-1), and the last bucket
+// everything larger.
+type Histogram [HistogramBuckets]uint64
+
+// Bound is the largest size bucket i holds.
+func Bound(i int) int {
+	return 1 << i
+}
+
+func (h *Histogram) add(size int) {
+	i := 0
+	if size > 1 {
+		i = min(bits.Len(uint(size-1)), HistogramBuckets-1)
+	}
+	h[i]++
+}
+
+// String lists the non-empty buckets, e.g. "≤8: 3, ≤16: 12".
+func (h Histogram) String() string {
+	var buckets []string
+	for i, n := range h {
+		if n > 0 {
+			buckets = append(buckets, fmt.Sprintf("≤%d: %d", Bound(i), n))
+		}
+	}
+	if buckets == nil {
+		return "empty"
+	}
+	return strings.Join(buckets, ", ")
+}
@@ -0,0 +1,161 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a FlushFunc that records the batches.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+	block   chan struct{} // if set, flushes wait for it
+}
+
+func (r *recorder) flush(_ context.Context, items []string) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, slices.Clone(items))
+	return r.err
+}
+
+func (r *recorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sizes []int
+	for _, b := range r.batches {
+		sizes = append(sizes, len(b))
+	}
+	return sizes
+}
+
+func addAll(t *testing.T, b *Batcher[string], items ...string) {
+	t.Helper()
+	for _, item := range items {
+		if err := b.Add(context.Background(), item); err != nil {
+			t.Fatalf("Add(%q) = %v", item, err)
+		}
+	}
+}
+
+func TestBatcherTriggers(t *testing.T) {
+	r := &recorder{}
+	b := New(r.flush, Options[string]{MaxItems: 3, MaxBytes: 8, Size: func(s string) int { return len(s) }})
+	addAll(t, b, "a", "b", "c", "d", "eeeee", "ffff", "gggggggggg", "h")
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+
+	// abc is full at 3 items, d+eeeee at 6 bytes cannot take ffff, the
+	// oversized item goes alone, and h is left for Close.
+	want := [][]string{{"a", "b", "c"}, {"d", "eeeee"}, {"ffff"}, {"gggggggggg"}, {"h"}}
+	if !slices.EqualFunc(r.batches, want, slices.Equal) {
+		t.Errorf("batches = %q, want %q", r.batches, want)
+	}
+	s := b.Stats()
+	if s.Items != 8 || s.Batches != 5 || s.Bytes != 24 {
+		t.Errorf("Stats = %+v, want 8 items, 24 bytes in 5 batches", s)
+	}
+	if s.Reasons[ReasonItems] != 1 || s.Reasons[ReasonBytes] != 3 || s.Reasons[ReasonClose] != 1 {
+		t.Errorf("Reasons = %v, want items 1, bytes 3, close 1", s.Reasons)
+	}
+	if got := s.Sizes.String(); got != "≤1: 3, ≤2: 1, ≤4: 1" {
+		t.Errorf("Sizes = %s", got)
+	}
+	if got := s.ByteSizes.String(); got != "≤1: 1, ≤4: 2, ≤8: 1, ≤16: 1" {
+		t.Errorf("ByteSizes = %s", got)
+	}
+}
+
+func TestBatcherLinger(t *testing.T) {
+	r := &recorder{}
+	b := New(r.flush, Options[string]{MaxItems: 10, MaxLinger: time.Millisecond})
+	defer b.Close(context.Background())
+	addAll(t, b, "a", "b")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.sizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sizes := r.sizes(); !slices.Equal(sizes, []int{2}) {
+		t.Fatalf("batch sizes = %v, want one batch of 2 sent by the timer", sizes)
+	}
+	if s := b.Stats(); s.Reasons[ReasonLinger] != 1 {
+		t.Errorf("Reasons = %v, want linger 1", s.Reasons)
+	}
+}
+
+func TestBatcherFlush(t *testing.T) {
+	failure := errors.New("disk full")
+	r := &recorder{err: failure}
+	b := New(r.flush, Options[string]{MaxItems: 2})
+	addAll(t, b, "a", "b", "c")
+	if err := b.Flush(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("Flush = %v, want the error of the full batch", err)
+	}
+	if sizes := r.sizes(); !slices.Equal(sizes, []int{2, 1}) {
+		t.Errorf("batch sizes = %v, want 2 then 1", sizes)
+	}
+
+	r.mu.Lock()
+	r.err = nil
+	r.mu.Unlock()
+	if err := b.Flush(context.Background()); err != nil {
+		t.Errorf("Flush with nothing pending = %v", err)
+	}
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if err := b.Add(context.Background(), "late"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Add after Close = %v, want ErrClosed", err)
+	}
+	if err := b.Flush(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close = %v, want ErrClosed", err)
+	}
+	if s := b.Stats(); s.Errors != 2 || s.Reasons[ReasonFlush] != 1 {
+		t.Errorf("Stats = %v, want 2 errors and 1 batch sent by Flush", s)
+	}
+}
+
+func TestBatcherBackpressure(t *testing.T) {
+	r := &recorder{block: make(chan struct{})}
+	b := New(r.flush, Options[string]{MaxItems: 1, QueueSize: 2})
+
+	// The first item is stuck in its flush and two more fill the queue.
+	addAll(t, b, "a", "b", "c")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Add(ctx, "d"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Add to a full queue = %v, want the context error", err)
+	}
+	// c may have waited too, for the flushing goroutine to take a.
+	if s := b.Stats(); s.Blocked < 1 {
+		t.Errorf("Blocked = %d, want the blocked Add counted", s.Blocked)
+	}
+
+	close(r.block)
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if sizes := r.sizes(); !slices.Equal(sizes, []int{1, 1, 1}) {
+		t.Errorf("batch sizes = %v, want the three accepted items", sizes)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var h Histogram
+	for _, size := range []int{1, 2, 3, 4, 5, 100, 1 << 40} {
+		h.add(size)
+	}
+	if got := h.String(); got != "≤1: 1, ≤2: 1, ≤4: 2, ≤8: 1, ≤128: 1, ≤2147483648: 1" {
+		t.Errorf("String = %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Baseline is a saved benchmark run, stored as JSON so a later run - typically
+// on a new Go version - can be compared against it.
+type Baseline struct {
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	CPUs      int       `json:"cpus"`
+	BenchTime string    `json:"benchtime"`
+	Created   time.Time `json:"created"`
+	Results   []Result  `json:"results"`
+}
+
+// NewBaseline wraps results with a description of the current toolchain and machine.
+func NewBaseline(benchTime time.Duration, results []Result) *Baseline {
+	if benchTime <= 0 {
+		benchTime = time.Second
+	}
+	return &Baseline{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		BenchTime: benchTime.String(),
+		Created:   time.Now().UTC().Truncate(time.Second),
+		Results:   results,
+	}
+}
+
+// Environment describes where the baseline was recorded, e.g.
+// "go1.22.0 linux/amd64, 8 CPUs, benchtime 1s".
+func (b *Baseline) Environment() string {
+	return fmt.Sprintf("%s %s/%s, %d CPUs, benchtime %s", b.GoVersion, b.GOOS, b.GOARCH, b.CPUs, b.BenchTime)
+}
+
+// Save writes the baseline to path as indented JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadBaseline reads a baseline written by Save.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
@@ -0,0 +1,172 @@
+// Package bench runs benchmark functions in-process through testing.Benchmark.
+//
+// The demo binary used to shell out to `go test -bench` for every table row,
+// which recompiled the benchmarks package each time and only worked inside the
+// module directory. This package runs the very same Benchmark* functions inside
+// the running process, so the demo ships as a single binary with no Go toolchain.
+package bench
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"day0/stats"
+)
+
+// Benchmark is a named benchmark function that can be run in-process.
+type Benchmark struct {
+	Name string
+	F    func(*testing.B)
+}
+
+// Result is the structured outcome of one benchmark run.
+type Result struct {
+	Name        string             `json:"name"`
+	N           int                `json:"n"`                 // iterations executed
+	NsPerOp     float64            `json:"ns_per_op"`         // wall-clock nanoseconds per iteration
+	BytesPerOp  int64              `json:"bytes_per_op"`      // heap bytes allocated per iteration
+	AllocsPerOp int64              `json:"allocs_per_op"`     // heap allocations per iteration
+	Metrics     map[string]float64 `json:"metrics,omitempty"` // custom metrics reported via b.ReportMetric
+	Samples     []float64          `json:"samples,omitempty"` // ns/op of every run when run more than once
+	// AllocSamples are the allocs/op of every run when run more than once.
+	AllocSamples []int64 `json:"alloc_samples,omitempty"`
+}
+
+// Failed reports whether the benchmark failed or was skipped.
+// testing.Benchmark returns a zero result in that case.
+func (r Result) Failed() bool {
+	return r.N == 0
+}
+
+// String formats the result the same way `go test -bench -benchmem` does.
+func (r Result) String() string {
+	if r.Failed() {
+		return fmt.Sprintf("%s\tFAILED", r.Name)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\t%d\t%.2f ns/op\t%d B/op\t%d allocs/op",
+		r.Name, r.N, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+	for _, unit := range r.MetricUnits() {
+		fmt.Fprintf(&sb, "\t%.4g %s", r.Metrics[unit], unit)
+	}
+	return sb.String()
+}
+
+// Time summarizes the ns/op samples. A result from a single run has one sample.
+func (r Result) Time() stats.Summary {
+	if len(r.Samples) == 0 {
+		return stats.Summarize([]float64{r.NsPerOp})
+	}
+	return stats.Summarize(r.Samples)
+}
+
+// MinAllocs returns the fewest allocs/op of any run. Allocation counts vary
+// between runs too, e.g. when a GC empties a sync.Pool, and the fewest is the
+// cost the code cannot avoid.
+func (r Result) MinAllocs() int64 {
+	if len(r.AllocSamples) == 0 {
+		return r.AllocsPerOp
+	}
+	return slices.Min(r.AllocSamples)
+}
+
+// MetricUnits returns the custom metric units in a stable order.
+func (r Result) MetricUnits() []string {
+	return slices.Sorted(maps.Keys(r.Metrics))
+}
+
+// Engine runs benchmarks in-process. The zero value is ready to use.
+type Engine struct {
+	// BenchTime is the target run time per benchmark.
+	// Zero keeps the testing package default of one second.
+	BenchTime time.Duration
+
+	// Count is the number of times each benchmark is run, like `go test -count`.
+	// With more than one run the result reports the median and keeps every
+	// run's ns/op as samples. Zero means one run.
+	Count int
+}
+
+// initOnce registers the testing flags so that -test.benchtime can be tuned.
+var initOnce sync.Once
+
+// Run executes a benchmark function Count times and returns its result.
+// If any run fails the whole result is failed.
+func (e *Engine) Run(name string, fn func(*testing.B)) Result {
+	e.configure()
+
+	if e.Count <= 1 {
+		return runOnce(name, fn)
+	}
+
+	runs := make([]Result, 0, e.Count)
+	for range e.Count {
+		r := runOnce(name, fn)
+		if r.Failed() {
+			return r
+		}
+		runs = append(runs, r)
+	}
+
+	// Report the run with the median time, so that time, bytes and allocs
+	// come from the same run.
+	slices.SortFunc(runs, func(a, b Result) int { return cmp.Compare(a.NsPerOp, b.NsPerOp) })
+	r := runs[len(runs)/2]
+	r.N = 0
+	for _, run := range runs {
+		r.N += run.N
+		r.Samples = append(r.Samples, run.NsPerOp)
+		r.AllocSamples = append(r.AllocSamples, run.AllocsPerOp)
+	}
+	return r
+}
+
+// runOnce executes a benchmark function once through testing.Benchmark.
+func runOnce(name string, fn func(*testing.B)) Result {
+	br := testing.Benchmark(fn)
+	if br.N == 0 {
+		return Result{Name: name}
+	}
+
+	r := Result{
+		Name:        name,
+		N:           br.N,
+		NsPerOp:     float64(br.T.Nanoseconds()) / float64(br.N),
+		BytesPerOp:  br.AllocedBytesPerOp(),
+		AllocsPerOp: br.AllocsPerOp(),
+	}
+	if len(br.Extra) > 0 {
+		r.Metrics = maps.Clone(br.Extra)
+	}
+	return r
+}
+
+// RunAll executes benchmarks in order and returns one result per benchmark.
+func (e *Engine) RunAll(benchmarks []Benchmark) []Result {
+	results := make([]Result, 0, len(benchmarks))
+	for _, bm := range benchmarks {
+		results = append(results, e.Run(bm.Name, bm.F))
+	}
+	return results
+}
+
+// configure applies the engine settings to the testing package.
+// testing.Benchmark only reads its configuration from the test flags.
+func (e *Engine) configure() {
+	initOnce.Do(testing.Init)
+
+	benchTime := "1s"
+	if e.BenchTime > 0 {
+		benchTime = e.BenchTime.String()
+	}
+	if err := flag.Set("test.benchtime", benchTime); err != nil {
+		panic(fmt.Sprintf("bench: setting benchtime: %v", err))
+	}
+}
@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"math"
+
+	"day0/stats"
+)
+
+// Verdict summarizes how a benchmark changed between two runs.
+type Verdict string
+
+const (
+	Unchanged Verdict = "~" // within the threshold or not significant, as benchstat prints it
+	Faster    Verdict = "faster"
+	Slower    Verdict = "slower"
+	Added     Verdict = "new"     // only in the new run
+	Removed   Verdict = "missing" // only in the old run
+	Broken    Verdict = "failed"  // failed in the new run
+)
+
+// Comparison is the change of one benchmark between an old and a new run.
+type Comparison struct {
+	Name        string
+	Old, New    Result
+	Delta       float64 // change in ns/op
+	Percent     float64 // change in ns/op relative to the old run
+	AllocsDelta int64   // change in the fewest allocs/op of any run
+	MoreAllocs  bool    // AllocsDelta is above the allocation threshold
+	Verdict     Verdict
+
+	// Tested is set when both runs have at least stats.MinSamples samples,
+	// and P is then the p-value of the Mann-Whitney U test on their ns/op.
+	Tested bool
+	P      float64
+}
+
+// Regressed reports whether the benchmark got slower or allocated more than
+// the thresholds allowed, or stopped working.
+func (c Comparison) Regressed() bool {
+	return c.Verdict == Slower || c.Verdict == Broken || c.MoreAllocs
+}
+
+// Compare matches old and new results by name. A time change within threshold
+// percent is reported as Unchanged, and so is any change that is not
+// statistically significant when both runs have enough samples to tell.
+// Allocations count as more when the fewest allocs/op of any run grew by more
+// than allocThreshold, so that one run that missed a pool does not count.
+// The comparisons follow the order of the new run, with benchmarks that
+// disappeared appended at the end.
+func Compare(old, new []Result, threshold float64, allocThreshold int64) []Comparison {
+	oldByName := make(map[string]Result, len(old))
+	for _, r := range old {
+		oldByName[r.Name] = r
+	}
+
+	comparisons := make([]Comparison, 0, len(new))
+	seen := make(map[string]bool, len(new))
+	for _, n := range new {
+		seen[n.Name] = true
+		o, ok := oldByName[n.Name]
+		if !ok {
+			comparisons = append(comparisons, Comparison{Name: n.Name, New: n, Verdict: Added})
+			continue
+		}
+		comparisons = append(comparisons, compareResults(o, n, threshold, allocThreshold))
+	}
+	for _, o := range old {
+		if !seen[o.Name] {
+			comparisons = append(comparisons, Comparison{Name: o.Name, Old: o, Verdict: Removed})
+		}
+	}
+	return comparisons
+}
+
+func compareResults(o, n Result, threshold float64, allocThreshold int64) Comparison {
+	c := Comparison{Name: n.Name, Old: o, New: n}
+	switch {
+	case n.Failed():
+		c.Verdict = Broken
+		return c
+	case o.Failed():
+		c.Verdict = Added
+		return c
+	}
+
+	c.Delta = n.NsPerOp - o.NsPerOp
+	c.Percent = c.Delta / o.NsPerOp * 100
+	c.AllocsDelta = n.MinAllocs() - o.MinAllocs()
+	c.MoreAllocs = c.AllocsDelta > allocThreshold
+	if len(o.Samples) >= stats.MinSamples && len(n.Samples) >= stats.MinSamples {
+		c.Tested = true
+		c.P = stats.MannWhitneyU(o.Samples, n.Samples)
+	}
+	switch {
+	case c.Tested && !stats.Significant(c.P):
+		c.Verdict = Unchanged
+	case math.Abs(c.Percent) <= threshold:
+		c.Verdict = Unchanged
+	case c.Percent > 0:
+		c.Verdict = Slower
+	default:
+		c.Verdict = Faster
+	}
+	return c
+}
@@ -0,0 +1,92 @@
+package bench
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	old := []Result{
+		{Name: "BenchmarkSame", N: 100, NsPerOp: 100},
+		{Name: "BenchmarkSlower", N: 100, NsPerOp: 100},
+		{Name: "BenchmarkFaster", N: 100, NsPerOp: 100},
+		{Name: "BenchmarkMoreAllocs", N: 100, NsPerOp: 100, AllocsPerOp: 1},
+		{Name: "BenchmarkBroken", N: 100, NsPerOp: 100},
+		{Name: "BenchmarkGone", N: 100, NsPerOp: 100},
+	}
+	new := []Result{
+		{Name: "BenchmarkSame", N: 100, NsPerOp: 105},
+		{Name: "BenchmarkSlower", N: 100, NsPerOp: 150},
+		{Name: "BenchmarkFaster", N: 100, NsPerOp: 50},
+		{Name: "BenchmarkMoreAllocs", N: 100, NsPerOp: 100, AllocsPerOp: 2},
+		{Name: "BenchmarkBroken"},
+		{Name: "BenchmarkAdded", N: 100, NsPerOp: 100},
+	}
+
+	want := []struct {
+		name      string
+		verdict   Verdict
+		percent   float64
+		regressed bool
+	}{
+		{"BenchmarkSame", Unchanged, 5, false},
+		{"BenchmarkSlower", Slower, 50, true},
+		{"BenchmarkFaster", Faster, -50, false},
+		{"BenchmarkMoreAllocs", Unchanged, 0, true},
+		{"BenchmarkBroken", Broken, 0, true},
+		{"BenchmarkAdded", Added, 0, false},
+		{"BenchmarkGone", Removed, 0, false},
+	}
+
+	got := Compare(old, new, 10, 0)
+	if len(got) != len(want) {
+		t.Fatalf("got %d comparisons, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Name != w.name || c.Verdict != w.verdict || c.Percent != w.percent || c.Regressed() != w.regressed {
+			t.Errorf("comparison %d = %s %s %+.1f%% regressed=%t, want %s %s %+.1f%% regressed=%t",
+				i, c.Name, c.Verdict, c.Percent, c.Regressed(), w.name, w.verdict, w.percent, w.regressed)
+		}
+	}
+}
+
+func TestCompareSignificance(t *testing.T) {
+	old := []Result{
+		{Name: "BenchmarkNoisy", N: 100, NsPerOp: 100, Samples: []float64{60, 80, 100, 120, 140}},
+		{Name: "BenchmarkShifted", N: 100, NsPerOp: 100, Samples: []float64{98, 99, 100, 101, 102}},
+	}
+	new := []Result{
+		{Name: "BenchmarkNoisy", N: 100, NsPerOp: 130, Samples: []float64{70, 110, 130, 150, 160}},
+		{Name: "BenchmarkShifted", N: 100, NsPerOp: 130, Samples: []float64{128, 129, 130, 131, 132}},
+	}
+
+	got := Compare(old, new, 10, 0)
+	if c := got[0]; !c.Tested || c.Verdict != Unchanged {
+		t.Errorf("noisy: tested=%t verdict=%s p=%.3f, want tested and ~", c.Tested, c.Verdict, c.P)
+	}
+	if c := got[1]; !c.Tested || c.Verdict != Slower {
+		t.Errorf("shifted: tested=%t verdict=%s p=%.3f, want tested and slower", c.Tested, c.Verdict, c.P)
+	}
+}
+
+func TestCompareAllocs(t *testing.T) {
+	old := []Result{
+		{Name: "BenchmarkPoolMiss", N: 100, NsPerOp: 100, AllocsPerOp: 0, AllocSamples: []int64{0, 0, 0, 0, 0}},
+		{Name: "BenchmarkOneMore", N: 100, NsPerOp: 100, AllocsPerOp: 2},
+		{Name: "BenchmarkManyMore", N: 100, NsPerOp: 100, AllocsPerOp: 2},
+	}
+	new := []Result{
+		// The median run missed the pool, but the others did not allocate.
+		{Name: "BenchmarkPoolMiss", N: 100, NsPerOp: 100, AllocsPerOp: 1, AllocSamples: []int64{0, 1, 1, 0, 2}},
+		{Name: "BenchmarkOneMore", N: 100, NsPerOp: 100, AllocsPerOp: 3},
+		{Name: "BenchmarkManyMore", N: 100, NsPerOp: 100, AllocsPerOp: 5},
+	}
+
+	got := Compare(old, new, 10, 1)
+	for i, want := range []struct {
+		delta     int64
+		regressed bool
+	}{{0, false}, {1, false}, {3, true}} {
+		if c := got[i]; c.AllocsDelta != want.delta || c.Regressed() != want.regressed {
+			t.Errorf("%s: AllocsDelta = %d, regressed = %t; want %d, %t", c.Name, c.AllocsDelta, c.Regressed(), want.delta, want.regressed)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"testing"
+
+	"day0/gcstats"
+)
+
+// GC tuning changes what an allocation costs: with a higher GOGC the heap
+// grows further between cycles and each allocation pays for less GC work;
+// with GOMEMLIMIT the GC runs more often as the heap nears the limit, whatever
+// GOGC says. A sweep reruns benchmarks under a grid of both.
+
+// GCOff is the GOGC percent that disables the GC, like GOGC=off.
+const GCOff = -1
+
+// NoMemoryLimit is the memory limit meaning no limit, like GOMEMLIMIT=off.
+const NoMemoryLimit = math.MaxInt64
+
+// GCSetting is one point of a sweep: the values debug.SetGCPercent and
+// debug.SetMemoryLimit take.
+type GCSetting struct {
+	Percent     int
+	MemoryLimit int64
+}
+
+func (s GCSetting) String() string {
+	return "GOGC=" + FormatPercent(s.Percent) + " GOMEMLIMIT=" + FormatMemoryLimit(s.MemoryLimit)
+}
+
+// Apply sets the GC percent and memory limit of the process and returns a
+// function that restores the previous ones.
+func (s GCSetting) Apply() (restore func()) {
+	percent := debug.SetGCPercent(s.Percent)
+	limit := debug.SetMemoryLimit(s.MemoryLimit)
+	return func() {
+		debug.SetGCPercent(percent)
+		debug.SetMemoryLimit(limit)
+	}
+}
+
+// Grid returns every combination of the percents and limits, percents major.
+func Grid(percents []int, limits []int64) []GCSetting {
+	grid := make([]GCSetting, 0, len(percents)*len(limits))
+	for _, p := range percents {
+		for _, l := range limits {
+			grid = append(grid, GCSetting{Percent: p, MemoryLimit: l})
+		}
+	}
+	return grid
+}
+
+// ParsePercents parses a comma-separated list of GOGC values such as
+// "50,100,off".
+func ParsePercents(list string) ([]int, error) {
+	var percents []int
+	for field := range strings.SplitSeq(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "off" {
+			percents = append(percents, GCOff)
+			continue
+		}
+		p, err := strconv.Atoi(field)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("bench: invalid GOGC value %q: want a non-negative integer or off", field)
+		}
+		percents = append(percents, p)
+	}
+	return percents, nil
+}
+
+// memoryUnits are the suffixes GOMEMLIMIT accepts, largest first.
+var memoryUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1},
+}
+
+// ParseMemoryLimits parses a comma-separated list of GOMEMLIMIT values in
+// its syntax, such as "off,64MiB,1GiB".
+func ParseMemoryLimits(list string) ([]int64, error) {
+	var limits []int64
+	for field := range strings.SplitSeq(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "off" {
+			limits = append(limits, NoMemoryLimit)
+			continue
+		}
+		number, unit := field, int64(1)
+		for _, u := range memoryUnits {
+			if n, ok := strings.CutSuffix(field, u.suffix); ok {
+				number, unit = n, u.bytes
+				break
+			}
+		}
+		n, err := strconv.ParseInt(number, 10, 64)
+		if err != nil || n <= 0 || n > math.MaxInt64/unit {
+			return nil, fmt.Errorf("bench: invalid GOMEMLIMIT value %q: want e.g. 512MiB or off", field)
+		}
+		limits = append(limits, n*unit)
+	}
+	return limits, nil
+}
+
+// FormatPercent formats a GOGC value.
+func FormatPercent(p int) string {
+	if p < 0 {
+		return "off"
+	}
+	return strconv.Itoa(p)
+}
+
+// FormatMemoryLimit formats a memory limit in the largest GOMEMLIMIT unit
+// that divides it.
+func FormatMemoryLimit(limit int64) string {
+	if limit == NoMemoryLimit {
+		return "off"
+	}
+	for _, u := range memoryUnits {
+		if limit%u.bytes == 0 {
+			return strconv.FormatInt(limit/u.bytes, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(limit, 10) + "B"
+}
+
+// GCCyclesUnit is the metric CountGC reports.
+const GCCyclesUnit = "GCs/1000op"
+
+// CountGC wraps a benchmark function so that it also reports the GC cycles
+// per thousand iterations as GCCyclesUnit.
+func CountGC(fn func(*testing.B)) func(*testing.B) {
+	return func(b *testing.B) {
+		before := gcstats.Read()
+		fn(b)
+		if b.N > 0 {
+			d := gcstats.Read().Sub(before)
+			b.ReportMetric(1000*float64(d.Cycles)/float64(b.N), GCCyclesUnit)
+		}
+	}
+}
+
+// Sweep runs every benchmark under every setting, counting GC cycles, and
+// returns the results indexed by benchmark, then setting. Each run starts
+// after a forced GC, and the process settings are restored at the end.
+func (e *Engine) Sweep(benchmarks []Benchmark, settings []GCSetting) [][]Result {
+	results := make([][]Result, len(benchmarks))
+	for i, bm := range benchmarks {
+		results[i] = make([]Result, len(settings))
+		for j, s := range settings {
+			restore := s.Apply()
+			runtime.GC()
+			results[i][j] = e.Run(bm.Name, CountGC(bm.F))
+			restore()
+		}
+	}
+	return results
+}
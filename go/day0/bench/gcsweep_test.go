@@ -0,0 +1,58 @@
+package bench
+
+import (
+	"runtime/debug"
+	"slices"
+	"testing"
+)
+
+func TestParseGCSettings(t *testing.T) {
+	percents, err := ParsePercents("50, 100,off")
+	if err != nil || !slices.Equal(percents, []int{50, 100, GCOff}) {
+		t.Errorf("ParsePercents = %v, %v", percents, err)
+	}
+	limits, err := ParseMemoryLimits("off,64MiB,1GiB,4096")
+	if err != nil || !slices.Equal(limits, []int64{NoMemoryLimit, 64 << 20, 1 << 30, 4096}) {
+		t.Errorf("ParseMemoryLimits = %v, %v", limits, err)
+	}
+	for _, bad := range []string{"-5", "lots", ""} {
+		if _, err := ParsePercents(bad); err == nil {
+			t.Errorf("ParsePercents(%q) succeeded", bad)
+		}
+	}
+	for _, bad := range []string{"0", "64MB", "9999999TiB"} {
+		if _, err := ParseMemoryLimits(bad); err == nil {
+			t.Errorf("ParseMemoryLimits(%q) succeeded", bad)
+		}
+	}
+
+	for _, limit := range limits {
+		if got, _ := ParseMemoryLimits(FormatMemoryLimit(limit)); got[0] != limit {
+			t.Errorf("FormatMemoryLimit(%d) = %q does not parse back", limit, FormatMemoryLimit(limit))
+		}
+	}
+	if got := (GCSetting{Percent: GCOff, MemoryLimit: 64 << 20}).String(); got != "GOGC=off GOMEMLIMIT=64MiB" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestGCSettingApply(t *testing.T) {
+	grid := Grid([]int{50, 200}, []int64{NoMemoryLimit, 1 << 30})
+	if len(grid) != 4 || grid[1] != (GCSetting{50, 1 << 30}) {
+		t.Fatalf("Grid = %v", grid)
+	}
+
+	percent, limit := debug.SetGCPercent(-1), debug.SetMemoryLimit(-1)
+	debug.SetGCPercent(percent)
+	restore := grid[3].Apply()
+	if got := debug.SetMemoryLimit(-1); got != 1<<30 {
+		t.Errorf("memory limit = %d after Apply", got)
+	}
+	restore()
+	if got := debug.SetGCPercent(percent); got != percent {
+		t.Errorf("GC percent = %d after restore, want %d", got, percent)
+	}
+	if got := debug.SetMemoryLimit(-1); got != limit {
+		t.Errorf("memory limit = %d after restore, want %d", got, limit)
+	}
+}
@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GoTest runs the benchmarks of pkg matching the regular expression pattern
+// with `go test -bench`, in a test binary built with buildFlags. It is for
+// what an in-process run cannot vary, such as compiler flags or a PGO
+// profile, and needs the go command and the module sources.
+//
+// BenchTime and Count apply as in Run; results with several runs report the
+// median like Run does.
+func (e *Engine) GoTest(pkg, pattern string, buildFlags ...string) ([]Result, error) {
+	args := []string{"test", "-run=^$", "-bench=" + pattern, "-benchmem",
+		"-count=" + strconv.Itoa(max(e.Count, 1))}
+	if e.BenchTime > 0 {
+		args = append(args, "-benchtime="+e.BenchTime.String())
+	}
+	args = append(append(args, buildFlags...), pkg)
+
+	cmd := exec.Command("go", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go %s: %w\n%s", strings.Join(args, " "), err,
+			strings.TrimSpace(stderr.String()+stdout.String()))
+	}
+	return ParseGoTest(&stdout)
+}
+
+// benchLine matches "BenchmarkName-8   1000000   12.5 ns/op   0 B/op   0 allocs/op".
+var benchLine = regexp.MustCompile(`^(Benchmark\S*?)(?:-\d+)?\s+(\d+)\s+([\d.]+) ns/op(.*)$`)
+
+// ParseGoTest reads the output of `go test -bench -benchmem` and returns one
+// result per benchmark in order of first appearance, merging repeated runs
+// of -count like Engine.Run.
+func ParseGoTest(r io.Reader) ([]Result, error) {
+	runs := map[string][]Result{}
+	var order []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		m := benchLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		res := Result{Name: m[1]}
+		res.N, _ = strconv.Atoi(m[2])
+		res.NsPerOp, _ = strconv.ParseFloat(m[3], 64)
+		fields := strings.Fields(m[4])
+		for i := 0; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch unit := fields[i+1]; unit {
+			case "B/op":
+				res.BytesPerOp = int64(v)
+			case "allocs/op":
+				res.AllocsPerOp = int64(v)
+			default:
+				if res.Metrics == nil {
+					res.Metrics = map[string]float64{}
+				}
+				res.Metrics[unit] = v
+			}
+		}
+		if _, ok := runs[res.Name]; !ok {
+			order = append(order, res.Name)
+		}
+		runs[res.Name] = append(runs[res.Name], res)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(order))
+	for _, name := range order {
+		rs := runs[name]
+		if len(rs) == 1 {
+			results = append(results, rs[0])
+			continue
+		}
+		slices.SortFunc(rs, func(a, b Result) int { return cmp.Compare(a.NsPerOp, b.NsPerOp) })
+		r := rs[len(rs)/2]
+		r.N = 0
+		for _, run := range rs {
+			r.N += run.N
+			r.Samples = append(r.Samples, run.NsPerOp)
+			r.AllocSamples = append(r.AllocSamples, run.AllocsPerOp)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+const goTestOutput = `goos: linux
+goarch: amd64
+pkg: day0/benchmarks
+BenchmarkCallInterface-8   	100000000	        2.10 ns/op	       0 B/op	       0 allocs/op
+BenchmarkBoxValue-8        	 50000000	        24.0 ns/op	      24 B/op	       1 allocs/op
+BenchmarkCallInterface-8   	100000000	        2.50 ns/op	       0 B/op	       0 allocs/op
+BenchmarkCallInterface-8   	100000000	        2.30 ns/op	       0 B/op	       0 allocs/op
+BenchmarkSequentialAligned 	      50	  21000000 ns/op	         0.6250 ns/element	       0 B/op	       0 allocs/op
+PASS
+ok  	day0/benchmarks	12.345s
+`
+
+func TestParseGoTest(t *testing.T) {
+	results, err := ParseGoTest(strings.NewReader(goTestOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3: %+v", len(results), results)
+	}
+
+	call := results[0]
+	if call.Name != "BenchmarkCallInterface" || call.NsPerOp != 2.30 || call.N != 300000000 || len(call.Samples) != 3 {
+		t.Errorf("merged runs = %+v, want the median of 3 runs", call)
+	}
+	if box := results[1]; box.BytesPerOp != 24 || box.AllocsPerOp != 1 {
+		t.Errorf("BenchmarkBoxValue = %+v, want 24 B/op and 1 allocs/op", box)
+	}
+	if seq := results[2]; seq.Name != "BenchmarkSequentialAligned" || seq.Metrics["ns/element"] != 0.625 {
+		t.Errorf("BenchmarkSequentialAligned = %+v, want 0.625 ns/element", seq)
+	}
+}
@@ -0,0 +1,224 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+
+	"day0/batch"
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkDBWriteIndividual,
+		BenchmarkDBWriteBatch,
+		BenchmarkDBWriteBatchSize10,
+		BenchmarkDBWriteBatchSize100,
+		BenchmarkDBWriteBatcher,
+		BenchmarkHTTPSingleRequest,
+		BenchmarkHTTPSmallBatch,
+		BenchmarkHTTPLargeBatch,
+		BenchmarkBatchProcessorSmall,
+		BenchmarkBatchProcessorMedium,
+		BenchmarkBatchProcessorLarge,
+	)
+}
+
+// =============================================================================
+// BATCHING OPERATIONS BENCHMARKS
+// =============================================================================
+//
+// This file benchmarks the batching operations topic to demonstrate the
+// performance difference between individual operations vs batched operations.
+//
+// KEY INSIGHTS:
+// - Batching reduces syscall overhead
+// - Batching improves I/O efficiency
+// - Trade-off: latency vs throughput
+
+// =============================================================================
+// DATABASE BATCHING BENCHMARKS
+// =============================================================================
+
+// BenchmarkDBWriteIndividual benchmarks individual database writes.
+func BenchmarkDBWriteIndividual(b *testing.B) {
+	db := &topics.SimulatedDB{}
+	entries := map[string]string{
+		"key1": "value1",
+		"key2": "value2",
+		"key3": "value3",
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		for k, v := range entries {
+			db.Write(k, v)
+		}
+	}
+}
+
+// BenchmarkDBWriteBatch benchmarks batched database writes.
+func BenchmarkDBWriteBatch(b *testing.B) {
+	db := &topics.SimulatedDB{}
+	entries := map[string]string{
+		"key1": "value1",
+		"key2": "value2",
+		"key3": "value3",
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		db.BatchWrite(entries)
+	}
+}
+
+// BenchmarkDBWriteBatchSize10 benchmarks batch writes with 10 items.
+func BenchmarkDBWriteBatchSize10(b *testing.B) {
+	db := &topics.SimulatedDB{}
+	entries := make(map[string]string, 10)
+	for i := range 10 {
+		entries[string(rune('a'+i))] = string(rune('0' + i))
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		db.BatchWrite(entries)
+	}
+}
+
+// BenchmarkDBWriteBatchSize100 benchmarks batch writes with 100 items.
+func BenchmarkDBWriteBatchSize100(b *testing.B) {
+	db := &topics.SimulatedDB{}
+	entries := make(map[string]string, 100)
+	for i := range 100 {
+		entries[string(rune('a'+i%26))+string(rune('a'+(i/26)%26))] = string(rune('0' + i%10))
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		db.BatchWrite(entries)
+	}
+}
+
+// BenchmarkDBWriteBatcher benchmarks 100 single writes grouped by a
+// DBWriter into one batch write, against BenchmarkDBWriteBatchSize100.
+func BenchmarkDBWriteBatcher(b *testing.B) {
+	db := &topics.SimulatedDB{}
+	writer := topics.NewDBWriter(db, batch.Options[topics.DBEntry]{MaxItems: 100})
+	defer writer.Close(context.Background())
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		for i := range 100 {
+			writer.Write(ctx, string(rune('a'+i%26))+string(rune('a'+(i/26)%26)), string(rune('0'+i%10)))
+		}
+		writer.Flush(ctx)
+	}
+}
+
+// =============================================================================
+// HTTP BATCHING BENCHMARKS
+// =============================================================================
+
+// BenchmarkHTTPSingleRequest benchmarks sending single HTTP requests individually.
+func BenchmarkHTTPSingleRequest(b *testing.B) {
+	client := topics.NewBatchHTTPClient(1, 0) // Flush immediately
+	defer client.Close()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		client.Send(topics.HTTPRequest{
+			URL:    "/api/item/1",
+			Method: "POST",
+		}).Wait(ctx)
+	}
+}
+
+// BenchmarkHTTPSmallBatch benchmarks sending requests in small batches.
+func BenchmarkHTTPSmallBatch(b *testing.B) {
+	client := topics.NewBatchHTTPClient(10, 0) // The last Send of each op fills the batch
+	defer client.Close()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		var last *topics.ResponseFuture
+		for range 10 {
+			last = client.Send(topics.HTTPRequest{
+				URL:    "/api/item/1",
+				Method: "POST",
+			})
+		}
+		last.Wait(ctx)
+	}
+}
+
+// BenchmarkHTTPLargeBatch benchmarks sending requests in large batches.
+func BenchmarkHTTPLargeBatch(b *testing.B) {
+	client := topics.NewBatchHTTPClient(100, 0) // The last Send of each op fills the batch
+	defer client.Close()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		var last *topics.ResponseFuture
+		for range 100 {
+			last = client.Send(topics.HTTPRequest{
+				URL:    "/api/item/1",
+				Method: "POST",
+			})
+		}
+		last.Wait(ctx)
+	}
+}
+
+// =============================================================================
+// BATCH PROCESSING BENCHMARKS
+// =============================================================================
+
+// benchmarkBatchProcessor times the path of a task through a processor with
+// 4 workers: Submit, the batcher grouping tasks into batches of batchSize,
+// and a worker delivering the result. Close is timed too, since it sends the
+// last partial batch; the results are drained as they arrive.
+func benchmarkBatchProcessor(b *testing.B, batchSize int) {
+	processor := topics.NewBatchProcessor(4, batchSize)
+	received := make(chan int)
+	go func() {
+		n := 0
+		for range processor.Results() {
+			n++
+		}
+		received <- n
+	}()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := range b.N {
+		if err := processor.Submit(ctx, topics.Task{ID: i, Data: "test"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := processor.Close(ctx); err != nil {
+		b.Fatal(err)
+	}
+	if n := <-received; n != b.N {
+		b.Fatalf("got %d results for %d tasks", n, b.N)
+	}
+}
+
+// BenchmarkBatchProcessorSmall benchmarks a task through batches of 10.
+func BenchmarkBatchProcessorSmall(b *testing.B) {
+	benchmarkBatchProcessor(b, 10)
+}
+
+// BenchmarkBatchProcessorMedium benchmarks a task through batches of 100.
+func BenchmarkBatchProcessorMedium(b *testing.B) {
+	benchmarkBatchProcessor(b, 100)
+}
+
+// BenchmarkBatchProcessorLarge benchmarks a task through batches of 1000.
+func BenchmarkBatchProcessorLarge(b *testing.B) {
+	benchmarkBatchProcessor(b, 1000)
+}
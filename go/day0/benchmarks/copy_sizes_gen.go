@@ -0,0 +1,334 @@
+// Code generated by gen_copy_sizes.go; DO NOT EDIT.
+
+package benchmarks
+
+import (
+	"testing"
+
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkCopyByValue8,
+		BenchmarkCopyByPointer8,
+		BenchmarkCopyByValue8NoInline,
+		BenchmarkCopyByPointer8NoInline,
+		BenchmarkCopyByValue16,
+		BenchmarkCopyByPointer16,
+		BenchmarkCopyByValue16NoInline,
+		BenchmarkCopyByPointer16NoInline,
+		BenchmarkCopyByValue32,
+		BenchmarkCopyByPointer32,
+		BenchmarkCopyByValue32NoInline,
+		BenchmarkCopyByPointer32NoInline,
+		BenchmarkCopyByValue64,
+		BenchmarkCopyByPointer64,
+		BenchmarkCopyByValue64NoInline,
+		BenchmarkCopyByPointer64NoInline,
+		BenchmarkCopyByValue128,
+		BenchmarkCopyByPointer128,
+		BenchmarkCopyByValue128NoInline,
+		BenchmarkCopyByPointer128NoInline,
+		BenchmarkCopyByValue256,
+		BenchmarkCopyByPointer256,
+		BenchmarkCopyByValue256NoInline,
+		BenchmarkCopyByPointer256NoInline,
+		BenchmarkCopyByValue512,
+		BenchmarkCopyByPointer512,
+		BenchmarkCopyByValue512NoInline,
+		BenchmarkCopyByPointer512NoInline,
+		BenchmarkCopyByValue1024,
+		BenchmarkCopyByPointer1024,
+		BenchmarkCopyByValue1024NoInline,
+		BenchmarkCopyByPointer1024NoInline,
+		BenchmarkCopyByValue2048,
+		BenchmarkCopyByPointer2048,
+		BenchmarkCopyByValue2048NoInline,
+		BenchmarkCopyByPointer2048NoInline,
+		BenchmarkCopyByValue4096,
+		BenchmarkCopyByPointer4096,
+		BenchmarkCopyByValue4096NoInline,
+		BenchmarkCopyByPointer4096NoInline,
+	)
+}
+
+func BenchmarkCopyByValue8(b *testing.B) {
+	var x, y topics.Copy8
+	for b.Loop() {
+		_ = topics.AddByValue8(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer8(b *testing.B) {
+	x, y := &topics.Copy8{}, &topics.Copy8{}
+	for b.Loop() {
+		_ = topics.AddByPointer8(x, y)
+	}
+}
+
+func BenchmarkCopyByValue8NoInline(b *testing.B) {
+	var x, y topics.Copy8
+	for b.Loop() {
+		_ = topics.AddByValue8NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer8NoInline(b *testing.B) {
+	x, y := &topics.Copy8{}, &topics.Copy8{}
+	for b.Loop() {
+		_ = topics.AddByPointer8NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue16(b *testing.B) {
+	var x, y topics.Copy16
+	for b.Loop() {
+		_ = topics.AddByValue16(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer16(b *testing.B) {
+	x, y := &topics.Copy16{}, &topics.Copy16{}
+	for b.Loop() {
+		_ = topics.AddByPointer16(x, y)
+	}
+}
+
+func BenchmarkCopyByValue16NoInline(b *testing.B) {
+	var x, y topics.Copy16
+	for b.Loop() {
+		_ = topics.AddByValue16NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer16NoInline(b *testing.B) {
+	x, y := &topics.Copy16{}, &topics.Copy16{}
+	for b.Loop() {
+		_ = topics.AddByPointer16NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue32(b *testing.B) {
+	var x, y topics.Copy32
+	for b.Loop() {
+		_ = topics.AddByValue32(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer32(b *testing.B) {
+	x, y := &topics.Copy32{}, &topics.Copy32{}
+	for b.Loop() {
+		_ = topics.AddByPointer32(x, y)
+	}
+}
+
+func BenchmarkCopyByValue32NoInline(b *testing.B) {
+	var x, y topics.Copy32
+	for b.Loop() {
+		_ = topics.AddByValue32NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer32NoInline(b *testing.B) {
+	x, y := &topics.Copy32{}, &topics.Copy32{}
+	for b.Loop() {
+		_ = topics.AddByPointer32NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue64(b *testing.B) {
+	var x, y topics.Copy64
+	for b.Loop() {
+		_ = topics.AddByValue64(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer64(b *testing.B) {
+	x, y := &topics.Copy64{}, &topics.Copy64{}
+	for b.Loop() {
+		_ = topics.AddByPointer64(x, y)
+	}
+}
+
+func BenchmarkCopyByValue64NoInline(b *testing.B) {
+	var x, y topics.Copy64
+	for b.Loop() {
+		_ = topics.AddByValue64NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer64NoInline(b *testing.B) {
+	x, y := &topics.Copy64{}, &topics.Copy64{}
+	for b.Loop() {
+		_ = topics.AddByPointer64NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue128(b *testing.B) {
+	var x, y topics.Copy128
+	for b.Loop() {
+		_ = topics.AddByValue128(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer128(b *testing.B) {
+	x, y := &topics.Copy128{}, &topics.Copy128{}
+	for b.Loop() {
+		_ = topics.AddByPointer128(x, y)
+	}
+}
+
+func BenchmarkCopyByValue128NoInline(b *testing.B) {
+	var x, y topics.Copy128
+	for b.Loop() {
+		_ = topics.AddByValue128NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer128NoInline(b *testing.B) {
+	x, y := &topics.Copy128{}, &topics.Copy128{}
+	for b.Loop() {
+		_ = topics.AddByPointer128NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue256(b *testing.B) {
+	var x, y topics.Copy256
+	for b.Loop() {
+		_ = topics.AddByValue256(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer256(b *testing.B) {
+	x, y := &topics.Copy256{}, &topics.Copy256{}
+	for b.Loop() {
+		_ = topics.AddByPointer256(x, y)
+	}
+}
+
+func BenchmarkCopyByValue256NoInline(b *testing.B) {
+	var x, y topics.Copy256
+	for b.Loop() {
+		_ = topics.AddByValue256NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer256NoInline(b *testing.B) {
+	x, y := &topics.Copy256{}, &topics.Copy256{}
+	for b.Loop() {
+		_ = topics.AddByPointer256NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue512(b *testing.B) {
+	var x, y topics.Copy512
+	for b.Loop() {
+		_ = topics.AddByValue512(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer512(b *testing.B) {
+	x, y := &topics.Copy512{}, &topics.Copy512{}
+	for b.Loop() {
+		_ = topics.AddByPointer512(x, y)
+	}
+}
+
+func BenchmarkCopyByValue512NoInline(b *testing.B) {
+	var x, y topics.Copy512
+	for b.Loop() {
+		_ = topics.AddByValue512NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer512NoInline(b *testing.B) {
+	x, y := &topics.Copy512{}, &topics.Copy512{}
+	for b.Loop() {
+		_ = topics.AddByPointer512NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue1024(b *testing.B) {
+	var x, y topics.Copy1024
+	for b.Loop() {
+		_ = topics.AddByValue1024(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer1024(b *testing.B) {
+	x, y := &topics.Copy1024{}, &topics.Copy1024{}
+	for b.Loop() {
+		_ = topics.AddByPointer1024(x, y)
+	}
+}
+
+func BenchmarkCopyByValue1024NoInline(b *testing.B) {
+	var x, y topics.Copy1024
+	for b.Loop() {
+		_ = topics.AddByValue1024NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer1024NoInline(b *testing.B) {
+	x, y := &topics.Copy1024{}, &topics.Copy1024{}
+	for b.Loop() {
+		_ = topics.AddByPointer1024NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue2048(b *testing.B) {
+	var x, y topics.Copy2048
+	for b.Loop() {
+		_ = topics.AddByValue2048(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer2048(b *testing.B) {
+	x, y := &topics.Copy2048{}, &topics.Copy2048{}
+	for b.Loop() {
+		_ = topics.AddByPointer2048(x, y)
+	}
+}
+
+func BenchmarkCopyByValue2048NoInline(b *testing.B) {
+	var x, y topics.Copy2048
+	for b.Loop() {
+		_ = topics.AddByValue2048NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer2048NoInline(b *testing.B) {
+	x, y := &topics.Copy2048{}, &topics.Copy2048{}
+	for b.Loop() {
+		_ = topics.AddByPointer2048NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByValue4096(b *testing.B) {
+	var x, y topics.Copy4096
+	for b.Loop() {
+		_ = topics.AddByValue4096(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer4096(b *testing.B) {
+	x, y := &topics.Copy4096{}, &topics.Copy4096{}
+	for b.Loop() {
+		_ = topics.AddByPointer4096(x, y)
+	}
+}
+
+func BenchmarkCopyByValue4096NoInline(b *testing.B) {
+	var x, y topics.Copy4096
+	for b.Loop() {
+		_ = topics.AddByValue4096NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer4096NoInline(b *testing.B) {
+	x, y := &topics.Copy4096{}, &topics.Copy4096{}
+	for b.Loop() {
+		_ = topics.AddByPointer4096NoInline(x, y)
+	}
+}
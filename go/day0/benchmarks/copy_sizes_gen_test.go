@@ -0,0 +1,65 @@
+// Code generated by gen_copy_sizes.go; DO NOT EDIT.
+
+package benchmarks_test
+
+import (
+	"testing"
+
+	"day0/benchmarks"
+)
+
+func BenchmarkCopyByValue8(b *testing.B)           { benchmarks.BenchmarkCopyByValue8(b) }
+func BenchmarkCopyByPointer8(b *testing.B)         { benchmarks.BenchmarkCopyByPointer8(b) }
+func BenchmarkCopyByValue8NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue8NoInline(b) }
+func BenchmarkCopyByPointer8NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer8NoInline(b) }
+
+func BenchmarkCopyByValue16(b *testing.B)           { benchmarks.BenchmarkCopyByValue16(b) }
+func BenchmarkCopyByPointer16(b *testing.B)         { benchmarks.BenchmarkCopyByPointer16(b) }
+func BenchmarkCopyByValue16NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue16NoInline(b) }
+func BenchmarkCopyByPointer16NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer16NoInline(b) }
+
+func BenchmarkCopyByValue32(b *testing.B)           { benchmarks.BenchmarkCopyByValue32(b) }
+func BenchmarkCopyByPointer32(b *testing.B)         { benchmarks.BenchmarkCopyByPointer32(b) }
+func BenchmarkCopyByValue32NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue32NoInline(b) }
+func BenchmarkCopyByPointer32NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer32NoInline(b) }
+
+func BenchmarkCopyByValue64(b *testing.B)           { benchmarks.BenchmarkCopyByValue64(b) }
+func BenchmarkCopyByPointer64(b *testing.B)         { benchmarks.BenchmarkCopyByPointer64(b) }
+func BenchmarkCopyByValue64NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue64NoInline(b) }
+func BenchmarkCopyByPointer64NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer64NoInline(b) }
+
+func BenchmarkCopyByValue128(b *testing.B)           { benchmarks.BenchmarkCopyByValue128(b) }
+func BenchmarkCopyByPointer128(b *testing.B)         { benchmarks.BenchmarkCopyByPointer128(b) }
+func BenchmarkCopyByValue128NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue128NoInline(b) }
+func BenchmarkCopyByPointer128NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer128NoInline(b) }
+
+func BenchmarkCopyByValue256(b *testing.B)           { benchmarks.BenchmarkCopyByValue256(b) }
+func BenchmarkCopyByPointer256(b *testing.B)         { benchmarks.BenchmarkCopyByPointer256(b) }
+func BenchmarkCopyByValue256NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue256NoInline(b) }
+func BenchmarkCopyByPointer256NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer256NoInline(b) }
+
+func BenchmarkCopyByValue512(b *testing.B)           { benchmarks.BenchmarkCopyByValue512(b) }
+func BenchmarkCopyByPointer512(b *testing.B)         { benchmarks.BenchmarkCopyByPointer512(b) }
+func BenchmarkCopyByValue512NoInline(b *testing.B)   { benchmarks.BenchmarkCopyByValue512NoInline(b) }
+func BenchmarkCopyByPointer512NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer512NoInline(b) }
+
+func BenchmarkCopyByValue1024(b *testing.B)         { benchmarks.BenchmarkCopyByValue1024(b) }
+func BenchmarkCopyByPointer1024(b *testing.B)       { benchmarks.BenchmarkCopyByPointer1024(b) }
+func BenchmarkCopyByValue1024NoInline(b *testing.B) { benchmarks.BenchmarkCopyByValue1024NoInline(b) }
+func BenchmarkCopyByPointer1024NoInline(b *testing.B) {
+	benchmarks.BenchmarkCopyByPointer1024NoInline(b)
+}
+
+func BenchmarkCopyByValue2048(b *testing.B)         { benchmarks.BenchmarkCopyByValue2048(b) }
+func BenchmarkCopyByPointer2048(b *testing.B)       { benchmarks.BenchmarkCopyByPointer2048(b) }
+func BenchmarkCopyByValue2048NoInline(b *testing.B) { benchmarks.BenchmarkCopyByValue2048NoInline(b) }
+func BenchmarkCopyByPointer2048NoInline(b *testing.B) {
+	benchmarks.BenchmarkCopyByPointer2048NoInline(b)
+}
+
+func BenchmarkCopyByValue4096(b *testing.B)         { benchmarks.BenchmarkCopyByValue4096(b) }
+func BenchmarkCopyByPointer4096(b *testing.B)       { benchmarks.BenchmarkCopyByPointer4096(b) }
+func BenchmarkCopyByValue4096NoInline(b *testing.B) { benchmarks.BenchmarkCopyByValue4096NoInline(b) }
+func BenchmarkCopyByPointer4096NoInline(b *testing.B) {
+	benchmarks.BenchmarkCopyByPointer4096NoInline(b)
+}
@@ -0,0 +1,55 @@
+package benchmarks
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkCounterShared,
+		BenchmarkCounterAdjacent,
+		BenchmarkCounterPadded,
+	)
+}
+
+// =============================================================================
+// FALSE SHARING BENCHMARKS
+// =============================================================================
+//
+// Each goroutine of b.RunParallel increments a counter of its own. Run with
+// -cpu=1,2,4,8 (or `day0 run false-sharing` for the GOMAXPROCS table) to see
+// adjacent counters stop scaling while padded ones keep up.
+
+// benchmarkParallelCounters gives every RunParallel goroutine its own slot.
+func benchmarkParallelCounters(b *testing.B, newCounters func(slots int) topics.Counters) {
+	slots := runtime.GOMAXPROCS(0)
+	c := newCounters(slots)
+	var next atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		w := int(next.Add(1)-1) % slots
+		for pb.Next() {
+			c.Inc(w)
+		}
+	})
+}
+
+// BenchmarkCounterShared increments one counter from every goroutine.
+func BenchmarkCounterShared(b *testing.B) {
+	benchmarkParallelCounters(b, func(int) topics.Counters { return &topics.SharedCounter{} })
+}
+
+// BenchmarkCounterAdjacent increments per-goroutine counters that share cache lines.
+func BenchmarkCounterAdjacent(b *testing.B) {
+	benchmarkParallelCounters(b, func(n int) topics.Counters { return topics.NewAdjacentCounters(n) })
+}
+
+// BenchmarkCounterPadded increments per-goroutine counters on separate cache lines.
+func BenchmarkCounterPadded(b *testing.B) {
+	benchmarkParallelCounters(b, func(n int) topics.Counters { return topics.NewPaddedCounters(n) })
+}
@@ -0,0 +1,13 @@
+package benchmarks_test
+
+import (
+	"testing"
+
+	"day0/benchmarks"
+)
+
+// Forwarders so `go test -bench` keeps discovering the in-process benchmarks.
+
+func BenchmarkCounterShared(b *testing.B)   { benchmarks.BenchmarkCounterShared(b) }
+func BenchmarkCounterAdjacent(b *testing.B) { benchmarks.BenchmarkCounterAdjacent(b) }
+func BenchmarkCounterPadded(b *testing.B)   { benchmarks.BenchmarkCounterPadded(b) }
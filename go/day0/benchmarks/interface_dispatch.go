@@ -0,0 +1,104 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkCallDirect,
+		BenchmarkCallInterface,
+		BenchmarkCallGeneric,
+		BenchmarkCallDevirtualized,
+		BenchmarkBoxSmallCounter,
+		BenchmarkBoxCounter,
+		BenchmarkBoxVector,
+		BenchmarkBoxPointer,
+		BenchmarkAssertConcrete,
+		BenchmarkAssertInterface,
+		BenchmarkTypeSwitchConcrete,
+		BenchmarkTypeSwitchInterface,
+	)
+}
+
+// =============================================================================
+// INTERFACE DISPATCH BENCHMARKS
+// =============================================================================
+//
+// The call benchmarks hand b.N to the topics functions so the loop, and with
+// it the call, is compiled in package topics: `day0 run interfaces` builds
+// that package with a PGO profile and reruns BenchmarkCallInterface.
+
+func BenchmarkCallDirect(b *testing.B) {
+	_ = topics.IncrementDirect(&topics.Counter{}, b.N)
+}
+
+func BenchmarkCallInterface(b *testing.B) {
+	_ = topics.IncrementVia(&topics.Counter{}, b.N)
+}
+
+func BenchmarkCallGeneric(b *testing.B) {
+	_ = topics.IncrementGeneric(&topics.Counter{}, b.N)
+}
+
+func BenchmarkCallDevirtualized(b *testing.B) {
+	_ = topics.IncrementKnown(&topics.Counter{}, b.N)
+}
+
+// =============================================================================
+// BOXING BENCHMARKS
+// =============================================================================
+
+func BenchmarkBoxSmallCounter(b *testing.B) {
+	c := topics.Counter{}
+	for b.Loop() {
+		topics.BoxCounter(c)
+	}
+}
+
+func BenchmarkBoxCounter(b *testing.B) {
+	var c topics.Counter
+	for range 1000 {
+		c.Increment()
+	}
+	for b.Loop() {
+		topics.BoxCounter(c)
+	}
+}
+
+func BenchmarkBoxVector(b *testing.B) {
+	v := topics.Vector{X: 1, Y: 2, Z: 3}
+	for b.Loop() {
+		topics.BoxVector(v)
+	}
+}
+
+func BenchmarkBoxPointer(b *testing.B) {
+	c := &topics.Counter{}
+	for b.Loop() {
+		topics.BoxPointer(c)
+	}
+}
+
+// =============================================================================
+// TYPE ASSERTION BENCHMARKS
+// =============================================================================
+
+// assertValues is the number of values per iteration; the benchmarks report
+// the time per value as ns/value.
+const assertValues = 1024
+
+func benchmarkAssertions(b *testing.B, assert func([]any) int) {
+	values := topics.NewAssertValues(assertValues)
+	for b.Loop() {
+		_ = assert(values)
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/assertValues, "ns/value")
+}
+
+func BenchmarkAssertConcrete(b *testing.B)      { benchmarkAssertions(b, topics.AssertConcrete) }
+func BenchmarkAssertInterface(b *testing.B)     { benchmarkAssertions(b, topics.AssertInterface) }
+func BenchmarkTypeSwitchConcrete(b *testing.B)  { benchmarkAssertions(b, topics.TypeSwitchConcrete) }
+func BenchmarkTypeSwitchInterface(b *testing.B) { benchmarkAssertions(b, topics.TypeSwitchInterface) }
@@ -0,0 +1,20 @@
+package benchmarks_test
+
+import (
+	"testing"
+
+	"day0/benchmarks"
+)
+
+func BenchmarkCallDirect(b *testing.B)          { benchmarks.BenchmarkCallDirect(b) }
+func BenchmarkCallInterface(b *testing.B)       { benchmarks.BenchmarkCallInterface(b) }
+func BenchmarkCallGeneric(b *testing.B)         { benchmarks.BenchmarkCallGeneric(b) }
+func BenchmarkCallDevirtualized(b *testing.B)   { benchmarks.BenchmarkCallDevirtualized(b) }
+func BenchmarkBoxSmallCounter(b *testing.B)     { benchmarks.BenchmarkBoxSmallCounter(b) }
+func BenchmarkBoxCounter(b *testing.B)          { benchmarks.BenchmarkBoxCounter(b) }
+func BenchmarkBoxVector(b *testing.B)           { benchmarks.BenchmarkBoxVector(b) }
+func BenchmarkBoxPointer(b *testing.B)          { benchmarks.BenchmarkBoxPointer(b) }
+func BenchmarkAssertConcrete(b *testing.B)      { benchmarks.BenchmarkAssertConcrete(b) }
+func BenchmarkAssertInterface(b *testing.B)     { benchmarks.BenchmarkAssertInterface(b) }
+func BenchmarkTypeSwitchConcrete(b *testing.B)  { benchmarks.BenchmarkTypeSwitchConcrete(b) }
+func BenchmarkTypeSwitchInterface(b *testing.B) { benchmarks.BenchmarkTypeSwitchInterface(b) }
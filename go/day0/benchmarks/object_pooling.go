@@ -0,0 +1,469 @@
+package benchmarks
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"day0/pool"
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkWithoutPoolSmall,
+		BenchmarkWithPoolSmall,
+		BenchmarkWithoutPoolMedium,
+		BenchmarkWithPoolMedium,
+		BenchmarkWithoutPoolLarge,
+		BenchmarkWithPoolLarge,
+		BenchmarkPoolSmallIterations,
+		BenchmarkPoolMediumIterations,
+		BenchmarkPoolLargeIterations,
+		BenchmarkPoolConcurrentSmall,
+		BenchmarkPoolConcurrentMedium,
+		BenchmarkPoolConcurrentLarge,
+		BenchmarkBufferReuseSequential,
+		BenchmarkBufferReuseMultipleSizes,
+		BenchmarkBufferWithoutReset,
+		BenchmarkMixedSizesAlloc,
+		BenchmarkMixedSizesSinglePool,
+		BenchmarkMixedSizesSizeClasses,
+		BenchmarkBufferWriteSmall,
+		BenchmarkBytesBufferWriteSmall,
+		BenchmarkBufferWriteLarge,
+		BenchmarkBytesBufferWriteLarge,
+	)
+}
+
+// =============================================================================
+// OBJECT POOLING BENCHMARKS
+// =============================================================================
+//
+// This file benchmarks the object pooling topic to demonstrate the performance
+// benefits of reusing objects instead of creating new ones.
+//
+// KEY INSIGHTS:
+// - Object pooling reduces GC pressure
+// - Improves performance in high-frequency scenarios
+// - Trade-off: memory usage vs allocation overhead
+//
+// The pooled benchmarks report the pool's hit rate as hit%: the share of Gets
+// served without allocating.
+
+// Pools for the medium and large buffers; GetBuffer serves the small ones.
+var (
+	mediumPool = topics.NewBufferPool(topics.MediumBufferSize)
+	largePool  = topics.NewBufferPool(topics.LargeBufferSize)
+)
+
+// reportHitRate reports the hit rate of the pool traffic s.
+func reportHitRate(b *testing.B, s pool.Stats) {
+	b.ReportMetric(100*s.HitRate(), "hit%")
+}
+
+// =============================================================================
+// WITH/WITHOUT POOL COMPARISON BENCHMARKS
+// =============================================================================
+
+// BenchmarkWithoutPoolSmall benchmarks allocations without pooling (small).
+func BenchmarkWithoutPoolSmall(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		buf := &topics.Buffer{Data: make([]byte, topics.SmallBufferSize)}
+		buf.Write([]byte("hello"))
+		_ = buf.Length
+	}
+}
+
+// BenchmarkWithPoolSmall benchmarks allocations with pooling (small).
+func BenchmarkWithPoolSmall(b *testing.B) {
+	// Warm up the pool
+	for range 10 {
+		buf := topics.GetBuffer()
+		topics.PutBuffer(buf)
+	}
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	for b.Loop() {
+		buf := topics.GetBuffer()
+		buf.Write([]byte("hello"))
+		_ = buf.Length
+		topics.PutBuffer(buf)
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// BenchmarkWithoutPoolMedium benchmarks allocations without pooling (medium).
+func BenchmarkWithoutPoolMedium(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		buf := &topics.Buffer{Data: make([]byte, topics.MediumBufferSize)}
+		buf.Write([]byte("hello world this is a longer string"))
+		_ = buf.Length
+	}
+}
+
+// BenchmarkWithPoolMedium benchmarks allocations with pooling (medium).
+func BenchmarkWithPoolMedium(b *testing.B) {
+	// Warm up the pool
+	for range 10 {
+		buf := mediumPool.Get()
+		mediumPool.Put(buf)
+	}
+
+	before := mediumPool.Stats()
+	b.ResetTimer()
+	for b.Loop() {
+		buf := mediumPool.Get()
+		buf.Write([]byte("hello world this is a longer string"))
+		_ = buf.Length
+		mediumPool.Put(buf)
+	}
+	reportHitRate(b, mediumPool.Stats().Sub(before))
+}
+
+// BenchmarkWithoutPoolLarge benchmarks allocations without pooling (large).
+func BenchmarkWithoutPoolLarge(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		buf := &topics.Buffer{Data: make([]byte, topics.LargeBufferSize)}
+		buf.Write([]byte("hello world this is a much longer string for benchmarking"))
+		_ = buf.Length
+	}
+}
+
+// BenchmarkWithPoolLarge benchmarks allocations with pooling (large).
+func BenchmarkWithPoolLarge(b *testing.B) {
+	// Warm up the pool
+	for range 10 {
+		buf := largePool.Get()
+		largePool.Put(buf)
+	}
+
+	before := largePool.Stats()
+	b.ResetTimer()
+	for b.Loop() {
+		buf := largePool.Get()
+		buf.Write([]byte("hello world this is a much longer string for benchmarking"))
+		_ = buf.Length
+		largePool.Put(buf)
+	}
+	reportHitRate(b, largePool.Stats().Sub(before))
+}
+
+// =============================================================================
+// POOL SIZE BENCHMARKS
+// =============================================================================
+
+// BenchmarkPoolSmallIterations benchmarks pool with small iterations.
+func BenchmarkPoolSmallIterations(b *testing.B) {
+	// Warm up
+	buf := topics.GetBuffer()
+	topics.PutBuffer(buf)
+
+	const iterations = 100
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	for b.Loop() {
+		for range iterations {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test"))
+			_ = buf.Length
+			topics.PutBuffer(buf)
+		}
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// BenchmarkPoolMediumIterations benchmarks pool with medium iterations.
+func BenchmarkPoolMediumIterations(b *testing.B) {
+	// Warm up
+	buf := topics.GetBuffer()
+	topics.PutBuffer(buf)
+
+	const iterations = 1000
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	for b.Loop() {
+		for range iterations {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test"))
+			_ = buf.Length
+			topics.PutBuffer(buf)
+		}
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// BenchmarkPoolLargeIterations benchmarks pool with large iterations.
+func BenchmarkPoolLargeIterations(b *testing.B) {
+	// Warm up
+	buf := topics.GetBuffer()
+	topics.PutBuffer(buf)
+
+	const iterations = 10000
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	for b.Loop() {
+		for range iterations {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test"))
+			_ = buf.Length
+			topics.PutBuffer(buf)
+		}
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// =============================================================================
+// CONCURRENT POOL ACCESS BENCHMARKS
+// =============================================================================
+
+// BenchmarkPoolConcurrentSmall benchmarks concurrent pool access (small).
+func BenchmarkPoolConcurrentSmall(b *testing.B) {
+	// Warm up
+	buf := topics.GetBuffer()
+	topics.PutBuffer(buf)
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test"))
+			_ = buf.Length
+			topics.PutBuffer(buf)
+		}
+	})
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// BenchmarkPoolConcurrentMedium benchmarks concurrent pool access (medium).
+func BenchmarkPoolConcurrentMedium(b *testing.B) {
+	// Warm up
+	for range 100 {
+		buf := topics.GetBuffer()
+		topics.PutBuffer(buf)
+	}
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test data for concurrent access"))
+			_ = buf.Length
+			topics.PutBuffer(buf)
+		}
+	})
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// BenchmarkPoolConcurrentLarge benchmarks concurrent pool access (large).
+func BenchmarkPoolConcurrentLarge(b *testing.B) {
+	// Warm up with more buffers
+	for range 500 {
+		buf := topics.GetBuffer()
+		topics.PutBuffer(buf)
+	}
+
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		counter := 0
+		for pb.Next() {
+			buf := topics.GetBuffer()
+			buf.Write([]byte("test data for concurrent access benchmark"))
+			_ = buf.Length
+			_ = counter
+			counter++
+			topics.PutBuffer(buf)
+		}
+	})
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// =============================================================================
+// BUFFER REUSE PATTERN BENCHMARKS
+// =============================================================================
+
+// BenchmarkBufferReuseSequential benchmarks sequential buffer reuse.
+func BenchmarkBufferReuseSequential(b *testing.B) {
+	buf := topics.GetBuffer()
+	defer topics.PutBuffer(buf)
+
+	data := []byte("sequential test data")
+
+	b.ResetTimer()
+	for b.Loop() {
+		buf.Reset()
+		buf.Write(data)
+		_ = buf.Length
+	}
+}
+
+// BenchmarkBufferReuseMultipleSizes benchmarks reusing buffer with multiple sizes.
+func BenchmarkBufferReuseMultipleSizes(b *testing.B) {
+	buf := topics.GetBuffer()
+	defer topics.PutBuffer(buf)
+
+	smallData := []byte("small")
+	mediumData := []byte("medium size data")
+	largeData := []byte("this is a much larger data set for testing")
+
+	b.ResetTimer()
+	for b.Loop() {
+		buf.Reset()
+		buf.Write(smallData)
+		_ = buf.Length
+
+		buf.Reset()
+		buf.Write(mediumData)
+		_ = buf.Length
+
+		buf.Reset()
+		buf.Write(largeData)
+		_ = buf.Length
+	}
+}
+
+// BenchmarkBufferWithoutReset benchmarks buffer without proper reset.
+func BenchmarkBufferWithoutReset(b *testing.B) {
+	before := topics.BufferPoolStats()
+	b.ResetTimer()
+	for b.Loop() {
+		buf := topics.GetBuffer()
+		buf.Write([]byte("test without reset"))
+		_ = buf.Length
+		topics.PutBuffer(buf)
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+// =============================================================================
+// MIXED SIZE BENCHMARKS
+// =============================================================================
+//
+// One op serves one request of the mixed-size workload, cycling through
+// mixedSizes. slack% is the share of the buffer capacity the requests did not
+// use: the memory a strategy holds beyond what was asked for.
+
+// mixedSizes are the request sizes of the mixed-size benchmarks.
+var mixedSizes = topics.NewMixedSizes(1024)
+
+// runMixedSizes serves the next request with fill on every op and reports
+// the slack.
+func runMixedSizes(b *testing.B, fill func(sizes []int) int) {
+	held, requested, i := 0, 0, 0
+	for b.Loop() {
+		req := mixedSizes[i : i+1]
+		held += fill(req)
+		requested += req[0]
+		i = (i + 1) % len(mixedSizes)
+	}
+	b.ReportMetric(100*float64(held-requested)/float64(held), "slack%")
+}
+
+// BenchmarkMixedSizesAlloc allocates a buffer of the exact size per request.
+func BenchmarkMixedSizesAlloc(b *testing.B) {
+	runMixedSizes(b, topics.FillAllocated)
+}
+
+// BenchmarkMixedSizesSinglePool serves every request that fits from one pool
+// of 64 KB buffers.
+func BenchmarkMixedSizesSinglePool(b *testing.B) {
+	p := topics.NewBufferPool(topics.MaxPooledBuffer)
+	runMixedSizes(b, func(sizes []int) int { return topics.FillSinglePool(sizes, p) })
+	reportHitRate(b, p.Stats())
+}
+
+// BenchmarkMixedSizesSizeClasses serves every request that fits from its
+// power-of-two size class.
+func BenchmarkMixedSizesSizeClasses(b *testing.B) {
+	p := pool.NewBytePool(topics.MinPooledBuffer, topics.MaxPooledBuffer)
+	runMixedSizes(b, func(sizes []int) int { return topics.FillSizeClasses(sizes, p) })
+	reportHitRate(b, p.Stats().Total())
+}
+
+// =============================================================================
+// BUFFER VS BYTES.BUFFER BENCHMARKS
+// =============================================================================
+//
+// Each op takes a buffer from its pool, writes lines of a message through
+// io.Writer and io.ByteWriter, drains it with WriteTo and puts it back. The
+// small message fits the 1 KB Buffer; the large one makes it grow, so the
+// pool drops it and every op allocates, while a grown bytes.Buffer goes back
+// into its pool as it is.
+
+// bytesBufferPool is the usual sync.Pool of *bytes.Buffer.
+var bytesBufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// bufferLine is one line of the benchmark messages, without the newline.
+var bufferLine = []byte("2026-10-16T07:39:57Z INFO request served in 1.2ms")
+
+// Lines per message: 12 lines are 600 bytes, 160 lines 8000.
+const (
+	smallMessageLines = 12
+	largeMessageLines = 160
+)
+
+// writeMessage writes lines lines to w.
+func writeMessage(w interface {
+	io.Writer
+	io.ByteWriter
+}, lines int) {
+	for range lines {
+		w.Write(bufferLine)
+		w.WriteByte('\n')
+	}
+}
+
+func benchmarkBufferWrite(b *testing.B, lines int) {
+	before := topics.BufferPoolStats()
+	for b.Loop() {
+		buf := topics.GetBuffer()
+		writeMessage(buf, lines)
+		buf.WriteTo(io.Discard)
+		topics.PutBuffer(buf)
+	}
+	reportHitRate(b, topics.BufferPoolStats().Sub(before))
+}
+
+func benchmarkBytesBufferWrite(b *testing.B, lines int) {
+	for b.Loop() {
+		buf := bytesBufferPool.Get().(*bytes.Buffer)
+		writeMessage(buf, lines)
+		buf.WriteTo(io.Discard)
+		buf.Reset()
+		bytesBufferPool.Put(buf)
+	}
+}
+
+// BenchmarkBufferWriteSmall writes a 600-byte message to a pooled Buffer.
+func BenchmarkBufferWriteSmall(b *testing.B) {
+	benchmarkBufferWrite(b, smallMessageLines)
+}
+
+// BenchmarkBytesBufferWriteSmall writes a 600-byte message to a pooled
+// bytes.Buffer.
+func BenchmarkBytesBufferWriteSmall(b *testing.B) {
+	benchmarkBytesBufferWrite(b, smallMessageLines)
+}
+
+// BenchmarkBufferWriteLarge writes an 8000-byte message to a pooled 1 KB
+// Buffer, which grows and is dropped on Put.
+func BenchmarkBufferWriteLarge(b *testing.B) {
+	benchmarkBufferWrite(b, largeMessageLines)
+}
+
+// BenchmarkBytesBufferWriteLarge writes an 8000-byte message to a pooled
+// bytes.Buffer, which keeps its grown capacity in the pool.
+func BenchmarkBytesBufferWriteLarge(b *testing.B) {
+	benchmarkBytesBufferWrite(b, largeMessageLines)
+}
@@ -0,0 +1,356 @@
+package benchmarks
+
+import (
+	"sync"
+	"testing"
+
+	"day0/topics"
+)
+
+func init() {
+	register(
+		BenchmarkProcessUnaligned,
+		BenchmarkProcessAligned,
+		BenchmarkProcessUnalignedPtr,
+		BenchmarkProcessAlignedPtr,
+		BenchmarkMixedTypesAligned,
+		BenchmarkMixedTypesUnaligned,
+		BenchmarkSequentialUnaligned,
+		BenchmarkSequentialAligned,
+		BenchmarkStridedUnaligned,
+		BenchmarkStridedAligned,
+		BenchmarkRandomUnaligned,
+		BenchmarkRandomAligned,
+		BenchmarkAddByValue,
+		BenchmarkAddByPointer,
+		BenchmarkIncrementByValue,
+		BenchmarkIncrementByPointer,
+		BenchmarkProcessByValue,
+		BenchmarkProcessByPointer,
+		BenchmarkReturnAddByValue,
+		BenchmarkReturnAddByPointer,
+		BenchmarkProcessSliceWithEscape,
+		BenchmarkProcessSliceNoEscape,
+		BenchmarkCreateLargeStructOnStack,
+		BenchmarkCreateLargeStructOnHeap,
+	)
+}
+
+// =============================================================================
+// STRUCT ALIGNMENT BENCHMARKS
+// =============================================================================
+
+func BenchmarkProcessUnaligned(b *testing.B) {
+	s := topics.UnalignedStruct{
+		Field1: 1,
+		Field2: 2,
+		Field3: 3,
+		Field4: 4,
+		Field5: 5,
+		Field6: 6,
+	}
+
+	for b.Loop() {
+		_ = topics.ProcessUnaligned(s)
+	}
+}
+
+func BenchmarkProcessAligned(b *testing.B) {
+	s := topics.AlignedStruct{
+		Field1: 1,
+		Field2: 2,
+		Field3: 3,
+		Field4: 4,
+		Field5: 5,
+		Field6: 6,
+	}
+
+	for b.Loop() {
+		_ = topics.ProcessAligned(s)
+	}
+}
+
+func BenchmarkProcessUnalignedPtr(b *testing.B) {
+	s := &topics.UnalignedStruct{
+		Field1: 1,
+		Field2: 2,
+		Field3: 3,
+		Field4: 4,
+		Field5: 5,
+		Field6: 6,
+	}
+
+	for b.Loop() {
+		_ = topics.ProcessUnalignedPtr(s)
+	}
+}
+
+func BenchmarkProcessAlignedPtr(b *testing.B) {
+	s := &topics.AlignedStruct{
+		Field1: 1,
+		Field2: 2,
+		Field3: 3,
+		Field4: 4,
+		Field5: 5,
+		Field6: 6,
+	}
+
+	for b.Loop() {
+		_ = topics.ProcessAlignedPtr(s)
+	}
+}
+
+func BenchmarkMixedTypesAligned(b *testing.B) {
+	var i int64 = 42
+	s := topics.MixedTypesAligned{
+		Pointer: &i,
+		Float:   3.14,
+		Counter: 100,
+		Count:   50,
+		Flag:    2.5,
+		Short:   10,
+		Char:    5,
+		Byte:    1,
+		Bool:    true,
+	}
+
+	for b.Loop() {
+		_ = s.Counter + int64(s.Count)
+	}
+}
+
+func BenchmarkMixedTypesUnaligned(b *testing.B) {
+	var i int64 = 42
+	s := topics.MixedTypesUnaligned{
+		Bool:    true,
+		Byte:    1,
+		Char:    5,
+		Short:   10,
+		Flag:    2.5,
+		Count:   50,
+		Counter: 100,
+		Float:   3.14,
+		Pointer: &i,
+	}
+
+	for b.Loop() {
+		_ = s.Counter + int64(s.Count)
+	}
+}
+
+// =============================================================================
+// ACCESS PATTERN BENCHMARKS
+// =============================================================================
+//
+// One op walks every element of a slice far larger than the last-level cache
+// once; ns/element is the cost of one element access.
+
+// accessElements is the number of elements of the access pattern benchmarks:
+// 96 MB of UnalignedStruct, 64 MB of AlignedStruct.
+const accessElements = 1 << 21
+
+// accessData is built once and shared by the access pattern benchmarks.
+var accessData = sync.OnceValues(func() ([]topics.UnalignedStruct, []topics.AlignedStruct) {
+	return topics.NewAccessData(accessElements)
+})
+
+func reportPerElement(b *testing.B) {
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/accessElements, "ns/element")
+}
+
+func BenchmarkSequentialUnaligned(b *testing.B) {
+	data, _ := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.SumSequentialUnaligned(data)
+	}
+	reportPerElement(b)
+}
+
+func BenchmarkSequentialAligned(b *testing.B) {
+	_, data := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.SumSequentialAligned(data)
+	}
+	reportPerElement(b)
+}
+
+func BenchmarkStridedUnaligned(b *testing.B) {
+	data, _ := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.SumStridedUnaligned(data, topics.AccessStride)
+	}
+	reportPerElement(b)
+}
+
+func BenchmarkStridedAligned(b *testing.B) {
+	_, data := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.SumStridedAligned(data, topics.AccessStride)
+	}
+	reportPerElement(b)
+}
+
+func BenchmarkRandomUnaligned(b *testing.B) {
+	data, _ := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.ChaseUnaligned(data, accessElements)
+	}
+	reportPerElement(b)
+}
+
+func BenchmarkRandomAligned(b *testing.B) {
+	_, data := accessData()
+	b.ResetTimer()
+	for b.Loop() {
+		_ = topics.ChaseAligned(data, accessElements)
+	}
+	reportPerElement(b)
+}
+
+// =============================================================================
+// PASS BY VALUE VS POINTER BENCHMARKS
+// =============================================================================
+
+func BenchmarkAddByValue(b *testing.B) {
+	a := topics.LargeStruct{Field1: 1, Field2: 2}
+	c := topics.LargeStruct{Field1: 3, Field2: 4}
+
+	for b.Loop() {
+		_ = topics.AddByValue(a, c)
+	}
+}
+
+func BenchmarkAddByPointer(b *testing.B) {
+	a := &topics.LargeStruct{Field1: 1, Field2: 2}
+	c := &topics.LargeStruct{Field1: 3, Field2: 4}
+
+	for b.Loop() {
+		_ = topics.AddByPointer(a, c)
+	}
+}
+
+// =============================================================================
+// RECEIVER TYPES BENCHMARKS
+// =============================================================================
+
+func BenchmarkIncrementByValue(b *testing.B) {
+	c := topics.Counter{}
+
+	for b.Loop() {
+		_ = c.IncrementByValue()
+	}
+}
+
+func BenchmarkIncrementByPointer(b *testing.B) {
+	c := &topics.Counter{}
+
+	for b.Loop() {
+		_ = c.IncrementByPointer()
+	}
+}
+
+func BenchmarkProcessByValue(b *testing.B) {
+	dp := topics.DataProcessor{}
+	dp.Field1 = 1
+	dp.Field2 = 2
+	dp.Field3 = 3
+	dp.Field4 = 4
+	dp.Field5 = 5
+	dp.Field6 = 6
+	dp.Field7 = 7
+	dp.Field8 = 8
+	for i := range dp.Data {
+		dp.Data[i] = int64(i)
+	}
+
+	for b.Loop() {
+		_ = dp.ProcessByValue()
+	}
+}
+
+func BenchmarkProcessByPointer(b *testing.B) {
+	dp := &topics.DataProcessor{}
+	dp.Field1 = 1
+	dp.Field2 = 2
+	dp.Field3 = 3
+	dp.Field4 = 4
+	dp.Field5 = 5
+	dp.Field6 = 6
+	dp.Field7 = 7
+	dp.Field8 = 8
+	for i := range dp.Data {
+		dp.Data[i] = int64(i)
+	}
+
+	for b.Loop() {
+		_ = dp.ProcessByPointer()
+	}
+}
+
+// =============================================================================
+// RETURN VALUE OPTIMIZATION BENCHMARKS
+// =============================================================================
+
+func BenchmarkReturnAddByValue(b *testing.B) {
+	a := topics.LargeStruct{Field1: 1, Field2: 2}
+	c := topics.LargeStruct{Field1: 3, Field2: 4}
+
+	for b.Loop() {
+		_ = topics.ReturnAddByValue(a, c)
+	}
+}
+
+func BenchmarkReturnAddByPointer(b *testing.B) {
+	a := topics.LargeStruct{Field1: 1, Field2: 2}
+	c := topics.LargeStruct{Field1: 3, Field2: 4}
+
+	for b.Loop() {
+		_ = topics.ReturnAddByPointer(a, c)
+	}
+}
+
+// =============================================================================
+// SLICE ESCAPE ANALYSIS BENCHMARKS
+// =============================================================================
+
+func BenchmarkProcessSliceWithEscape(b *testing.B) {
+
+	for b.Loop() {
+		_ = topics.ProcessSliceWithEscape(1000)
+	}
+}
+
+func BenchmarkProcessSliceNoEscape(b *testing.B) {
+
+	for b.Loop() {
+		_ = topics.ProcessSliceNoEscape(1000)
+	}
+}
+
+// =============================================================================
+// STACK VS HEAP ALLOCATION BENCHMARKS
+// =============================================================================
+
+func BenchmarkCreateLargeStructOnStack(b *testing.B) {
+
+	for b.Loop() {
+		_ = topics.CreateLargeStructOnStack()
+	}
+}
+
+// largeStructSink keeps the result of BenchmarkCreateLargeStructOnHeap
+// reachable: discarded, the inlined call's struct does not escape and is
+// allocated on the stack after all.
+var largeStructSink *topics.LargeStruct
+
+func BenchmarkCreateLargeStructOnHeap(b *testing.B) {
+
+	for b.Loop() {
+		largeStructSink = topics.CreateLargeStructOnHeap()
+	}
+}
@@ -0,0 +1,66 @@
+// Package benchmarks holds the benchmark functions for every optimization topic.
+//
+// The functions live in regular (non-test) files so the demo binary can run them
+// in-process through the bench package. The *_test.go files only forward to them,
+// which keeps `go test -bench=. ./benchmarks` working as before.
+//
+// Each topic in package topics lists its benchmarks by name; this package maps
+// those names to functions. registry_test.go checks both sides against the
+// Benchmark* functions declared in the source, so the lists cannot drift.
+package benchmarks
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"day0/bench"
+)
+
+// registry holds every benchmark function in registration order.
+var registry []bench.Benchmark
+
+// register adds benchmark functions to the registry.
+// The name is taken from the function symbol so it can never drift.
+func register(fns ...func(*testing.B)) {
+	for _, fn := range fns {
+		registry = append(registry, bench.Benchmark{Name: funcName(fn), F: fn})
+	}
+}
+
+// funcName returns the unqualified name of a top-level function.
+func funcName(fn func(*testing.B)) string {
+	full := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return full[strings.LastIndexByte(full, '.')+1:]
+}
+
+// All returns every registered benchmark.
+func All() []bench.Benchmark {
+	return slices.Clone(registry)
+}
+
+// Lookup returns the benchmark with the given name.
+func Lookup(name string) (bench.Benchmark, bool) {
+	for _, bm := range registry {
+		if bm.Name == name {
+			return bm, true
+		}
+	}
+	return bench.Benchmark{}, false
+}
+
+// Resolve returns the benchmarks with the given names, in the same order.
+func Resolve(names []string) ([]bench.Benchmark, error) {
+	result := make([]bench.Benchmark, 0, len(names))
+	for _, name := range names {
+		bm, ok := Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("benchmark %s is not registered", name)
+		}
+		result = append(result, bm)
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package benchmarks_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"day0/benchmarks"
+	"day0/topics"
+)
+
+// declaredBenchmarks parses the files matched by pattern and returns the names
+// of all top-level Benchmark* functions declared in them.
+func declaredBenchmarks(t *testing.T, pattern string, tests bool) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") != tests {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Benchmark") {
+				names = append(names, fn.Name.Name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func registeredBenchmarks() []string {
+	var names []string
+	for _, bm := range benchmarks.All() {
+		names = append(names, bm.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// TestRegistryMatchesSource fails when a Benchmark* function is added to the
+// package without being registered under a topic, or registered twice.
+func TestRegistryMatchesSource(t *testing.T) {
+	declared := declaredBenchmarks(t, "*.go", false)
+	registered := registeredBenchmarks()
+
+	if !slices.Equal(declared, registered) {
+		for _, name := range declared {
+			if !slices.Contains(registered, name) {
+				t.Errorf("%s is declared but not registered", name)
+			}
+		}
+		for i, name := range registered {
+			if !slices.Contains(declared, name) {
+				t.Errorf("%s is registered but not declared", name)
+			}
+			if i > 0 && registered[i-1] == name {
+				t.Errorf("%s is registered more than once", name)
+			}
+		}
+	}
+}
+
+// TestForwardersMatchRegistry fails when a registered benchmark has no
+// forwarder in a *_test.go file, so `go test -bench` would miss it.
+func TestForwardersMatchRegistry(t *testing.T) {
+	forwarded := declaredBenchmarks(t, "*_test.go", true)
+	registered := registeredBenchmarks()
+
+	for _, name := range registered {
+		if !slices.Contains(forwarded, name) {
+			t.Errorf("%s has no forwarder in a *_test.go file", name)
+		}
+	}
+	for _, name := range forwarded {
+		if !slices.Contains(registered, name) {
+			t.Errorf("forwarder %s has no registered benchmark", name)
+		}
+	}
+}
+
+// TestTopicsMatchRegistry fails when a topic lists a benchmark that does not
+// exist, or a registered benchmark is not listed by exactly one topic.
+func TestTopicsMatchRegistry(t *testing.T) {
+	owner := map[string]string{}
+	for _, topic := range topics.All() {
+		if len(topic.Benchmarks()) == 0 {
+			t.Errorf("topic %q has no benchmarks", topic.ID())
+		}
+		for _, g := range topic.Benchmarks() {
+			if len(g.Names) == 0 {
+				t.Errorf("topic %q group %q is empty", topic.ID(), g.Title)
+			}
+			for _, name := range g.Names {
+				if _, ok := benchmarks.Lookup(name); !ok {
+					t.Errorf("topic %q lists unknown benchmark %s", topic.ID(), name)
+				}
+				if prev, ok := owner[name]; ok {
+					t.Errorf("%s is listed by both %q and %q", name, prev, topic.ID())
+				}
+				owner[name] = topic.ID()
+			}
+		}
+	}
+
+	for _, name := range registeredBenchmarks() {
+		if _, ok := owner[name]; !ok {
+			t.Errorf("%s is not listed by any topic", name)
+		}
+	}
+}
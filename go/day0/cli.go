@@ -0,0 +1,534 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"day0/align"
+	"day0/analysis/checker"
+	"day0/bench"
+	"day0/benchmarks"
+	"day0/copysize"
+	"day0/profiling"
+	"day0/report"
+	"day0/topics"
+)
+
+// =============================================================================
+// COMMAND LINE INTERFACE
+// =============================================================================
+
+const usage = `Usage: day0 [command] [flags] [topic...]
+
+Commands:
+  list      [topic...]  list topics with a short description
+  run       [topic...]  run the demo and benchmarks of the topics (default: all)
+  describe  topic...    print only the explanation of the topics
+  bench     [topic...]  run only the benchmarks of the topics (default: all)
+  bench save FILE       run every benchmark and save the results as a baseline
+  bench compare FILE    run every benchmark and compare with a saved baseline;
+                        exits with status 1 when a benchmark regressed
+  align     [dir...]    suggest field orders that shrink structs, e.g. ./...;
+                        -w rewrites the declarations in place
+  vet       [dir...]    report values too large to copy: parameters, value
+                        receivers, range variables and the calls copying them
+  gcsweep   [benchmark...]
+                        rerun allocation-heavy benchmarks under a grid of GOGC
+                        and GOMEMLIMIT values; names may be globs
+
+Under "go vet -vettool=$(which day0) ./..." day0 runs the vet analyzers as
+part of go vet instead.
+
+Without a command every topic is run, like "day0 run".
+run and bench accept --format=text|json|csv|markdown; the machine formats
+print only the measured values as records, without the explanations.
+They also accept --profile=cpu|heap|mutex|block to write a pprof file per
+benchmark and show its top 10 functions below each table.
+Topics are IDs from "list" or glob patterns such as 'pool*' or '*-value'.
+Use "day0 <command> -h" for the flags of a command.
+The exit status is 2 for command line mistakes and 1 for other errors.
+`
+
+// errUsage signals a command line mistake; the usage text has already been printed.
+var errUsage = errors.New("usage")
+
+// usageError is a command line mistake, such as a pattern that matches
+// nothing, whose message runCLI still has to print.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+// usageErrorf formats a usageError.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// isUsageError reports whether err is a usageError.
+func isUsageError(err error) bool {
+	_, ok := errors.AsType[*usageError](err)
+	return ok
+}
+
+// runCLI dispatches the command line and returns the process exit code.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	cmd := "run"
+	if len(args) > 0 {
+		switch {
+		case args[0] == "-h" || args[0] == "-help" || args[0] == "--help":
+			cmd = "help"
+		case !strings.HasPrefix(args[0], "-"):
+			cmd, args = args[0], args[1:]
+		}
+	}
+
+	var err error
+	switch cmd {
+	case "list":
+		err = cmdList(args, stdout, stderr)
+	case "run":
+		err = cmdRun(args, stdout, stderr)
+	case "describe":
+		err = cmdDescribe(args, stdout, stderr)
+	case "bench":
+		err = cmdBench(args, stdout, stderr)
+	case "align":
+		err = cmdAlign(args, stdout, stderr)
+	case "vet":
+		err = cmdVet(args, stdout, stderr)
+	case "gcsweep":
+		err = cmdGCSweep(args, stdout, stderr)
+	case "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", cmd, usage)
+		return 2
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case isUsageError(err):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	default:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+}
+
+// newFlagSet creates a flag set for a command.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: day0 %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// topicFlagSet creates a flag set for a command that selects topics, with the
+// shared --tag filter.
+func topicFlagSet(name, args string, stderr io.Writer) (*flag.FlagSet, *string) {
+	fs := newFlagSet(name, args, stderr)
+	tag := fs.String("tag", "", "only topics with this tag, as shown by list")
+	return fs, tag
+}
+
+// formatFlag adds the --format flag shared by the commands that produce results.
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", string(report.Text),
+		"output format: text, json, csv or markdown")
+}
+
+// profileFlags adds the --profile and --profiledir flags and returns a function
+// that sets up the profiler from them once the flags are parsed.
+func profileFlags(fs *flag.FlagSet) func() error {
+	kind := fs.String("profile", "", "capture a cpu, heap, mutex or block profile of every benchmark")
+	dir := fs.String("profiledir", "profiles", "directory for the pprof files written by --profile")
+	return func() error {
+		if *kind == "" {
+			return nil
+		}
+		k, err := profiling.ParseKind(*kind)
+		if err != nil {
+			return err
+		}
+		profiler = &profiling.Profiler{Kind: k, Dir: *dir}
+		return nil
+	}
+}
+
+// newReport returns the report for a run in the given format. The machine
+// formats discard the narrative so stdout holds only the rendered records.
+func newReport(format string, stdout io.Writer) (*report.Report, report.Format, error) {
+	f, err := report.ParseFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	if f.Machine() {
+		return report.New(io.Discard), f, nil
+	}
+	return report.New(stdout), f, nil
+}
+
+// parseFlags parses args and maps flag errors to errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+func cmdList(args []string, stdout, stderr io.Writer) error {
+	fs, tag := topicFlagSet("list", "[topic...]", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	selected, err := selectTopics(fs.Args(), *tag)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, t := range selected {
+		fmt.Fprintf(tw, "%s\t%s\t[%s]\n", t.ID(), t.Summary(), strings.Join(t.Tags(), ", "))
+	}
+	return tw.Flush()
+}
+
+func cmdRun(args []string, stdout, stderr io.Writer) error {
+	fs, tag := topicFlagSet("run", "[topic...]", stderr)
+	noBench := fs.Bool("no-bench", false, "skip the benchmark tables")
+	benchTime := fs.Duration("benchtime", time.Second, "target run time per benchmark")
+	count := fs.Int("count", 1, "run each benchmark this many times and report the median ± 95% CI")
+	format := formatFlag(fs)
+	setupProfiler := profileFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+	if err := setupProfiler(); err != nil {
+		return err
+	}
+	selected, err := selectTopics(fs.Args(), *tag)
+	if err != nil {
+		return err
+	}
+
+	benchEngine.BenchTime, benchEngine.Count = *benchTime, *count
+	runTopics(out, selected, !*noBench)
+	return report.Render(stdout, f, out.Records())
+}
+
+func cmdDescribe(args []string, stdout, stderr io.Writer) error {
+	fs, tag := topicFlagSet("describe", "topic...", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 && *tag == "" {
+		fs.Usage()
+		return errUsage
+	}
+	selected, err := selectTopics(fs.Args(), *tag)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range selected {
+		describeTopic(stdout, t)
+	}
+	return nil
+}
+
+func cmdBench(args []string, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "save":
+			return cmdBenchSave(args[1:], stdout, stderr)
+		case "compare":
+			return cmdBenchCompare(args[1:], stdout, stderr)
+		}
+	}
+
+	fs, tag := topicFlagSet("bench", "[topic...]", stderr)
+	benchTime := fs.Duration("benchtime", time.Second, "target run time per benchmark")
+	count := fs.Int("count", 1, "run each benchmark this many times and report the median ± 95% CI")
+	format := formatFlag(fs)
+	setupProfiler := profileFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+	if err := setupProfiler(); err != nil {
+		return err
+	}
+	selected, err := selectTopics(fs.Args(), *tag)
+	if err != nil {
+		return err
+	}
+
+	benchEngine.BenchTime, benchEngine.Count = *benchTime, *count
+	for _, t := range selected {
+		out.SetTopic(t.ID())
+		printHeader(out, strings.ToUpper(t.Title()))
+		runBenchmarks(out, t)
+		fmt.Fprintln(out)
+	}
+	return report.Render(stdout, f, out.Records())
+}
+
+func cmdBenchSave(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("bench save", "FILE", stderr)
+	benchTime := fs.Duration("benchtime", time.Second, "target run time per benchmark")
+	count := fs.Int("count", 5, "run each benchmark this many times; at least 4 enables the significance test")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	benchEngine.BenchTime, benchEngine.Count = *benchTime, *count
+	return saveBaseline(stdout, fs.Arg(0))
+}
+
+func cmdBenchCompare(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("bench compare", "FILE", stderr)
+	benchTime := fs.Duration("benchtime", time.Second, "target run time per benchmark")
+	count := fs.Int("count", 5, "run each benchmark this many times; at least 4 enables the significance test")
+	threshold := fs.Float64("threshold", 10, "time change in percent tolerated before a benchmark counts as regressed")
+	allocThreshold := fs.Int64("allocs", 0, "increase in allocs/op, the fewest of any run, tolerated before a benchmark counts as regressed")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+
+	benchEngine.BenchTime, benchEngine.Count = *benchTime, *count
+	regressed, err := compareBaseline(out, fs.Arg(0), *threshold, *allocThreshold)
+	if err != nil {
+		return err
+	}
+	if err := report.Render(stdout, f, out.Records()); err != nil {
+		return err
+	}
+	if len(regressed) > 0 {
+		return fmt.Errorf("%d benchmarks regressed against %s", len(regressed), fs.Arg(0))
+	}
+	return nil
+}
+
+func cmdAlign(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("align", "[dir | dir/...]...", stderr)
+	arch := fs.String("arch", runtime.GOARCH, "GOARCH whose sizes and alignments to use")
+	hot := fs.String("hot", "", "comma-separated hot fields as Type.Field, in addition to fields commented "+align.HotMarker)
+	archs := fs.String("archs", "", "comma-separated GOARCH values to also show the sizes for, e.g. amd64,arm64,arm,386")
+	all := fs.Bool("all", false, "also list the structs that are already optimal")
+	write := fs.Bool("w", false, "rewrite the struct declarations in the source files")
+	order := fs.String("order", string(align.BySize), "ordering -w applies: size or cache")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	o, err := align.ParseOrder(*order)
+	if err != nil {
+		return err
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+
+	opts := align.Options{Arch: *arch}
+	if *hot != "" {
+		opts.Hot = strings.Split(*hot, ",")
+	}
+	if *archs != "" {
+		opts.Archs = strings.Split(*archs, ",")
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	var sugs []align.Suggestion
+	for _, p := range patterns {
+		s, err := align.Analyze(p, opts)
+		if err != nil {
+			return err
+		}
+		sugs = append(sugs, s...)
+	}
+
+	out.SetTopic("align")
+	printAlignment(out, sugs, *arch, *all)
+	if len(opts.Archs) > 0 {
+		printArchSizes(out, sugs, opts.Archs, *all)
+	}
+	if *write {
+		changed, err := align.Rewrite(sugs, o)
+		for _, file := range changed {
+			fmt.Fprintf(out, "rewrote %s\n", file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return report.Render(stdout, f, out.Records())
+}
+
+func cmdVet(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("vet", "[dir | dir/...]...", stderr)
+	copysize.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	findings, err := checker.Run(patterns, copysize.Analyzer)
+	if err != nil {
+		return err
+	}
+
+	out.SetTopic("vet")
+	printCopies(out, findings)
+	if err := report.Render(stdout, f, out.Records()); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d oversized copies", len(findings))
+	}
+	return nil
+}
+
+func cmdGCSweep(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("gcsweep", "[benchmark...]", stderr)
+	gogc := fs.String("gogc", "50,100,200,400,off", "comma-separated GOGC values")
+	memLimit := fs.String("memlimit", "off,16MiB", "comma-separated GOMEMLIMIT values, e.g. off,16MiB,1GiB")
+	benchTime := fs.Duration("benchtime", 200*time.Millisecond, "target run time per benchmark and setting")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	percents, err := bench.ParsePercents(*gogc)
+	if err != nil {
+		return err
+	}
+	limits, err := bench.ParseMemoryLimits(*memLimit)
+	if err != nil {
+		return err
+	}
+	out, f, err := newReport(*format, stdout)
+	if err != nil {
+		return err
+	}
+	names := fs.Args()
+	if len(names) == 0 {
+		names = gcSweepDefaults
+	}
+	selected, err := selectBenchmarks(names)
+	if err != nil {
+		return err
+	}
+
+	benchEngine.BenchTime, benchEngine.Count = *benchTime, 1
+	out.SetTopic("gcsweep")
+	printGCSweep(out, selected, percents, limits)
+	return report.Render(stdout, f, out.Records())
+}
+
+// selectBenchmarks returns the registered benchmarks matching any of the
+// patterns, which are names or path.Match globs such as "*PoolLarge".
+func selectBenchmarks(patterns []string) ([]bench.Benchmark, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, usageErrorf("invalid benchmark pattern %q: %v", p, err)
+		}
+	}
+	var selected []bench.Benchmark
+	for _, bm := range benchmarks.All() {
+		if slices.ContainsFunc(patterns, func(p string) bool {
+			ok, _ := path.Match(p, bm.Name)
+			return ok
+		}) {
+			selected = append(selected, bm)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, usageErrorf("no benchmark matches %q", patterns)
+	}
+	return selected, nil
+}
+
+// selectTopics returns the registered topics matching any of the patterns and
+// carrying the tag. Patterns are topic IDs or path.Match globs such as "pool*";
+// no patterns selects every topic, an empty tag disables tag filtering.
+func selectTopics(patterns []string, tag string) ([]topics.Topic, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, usageErrorf("invalid topic pattern %q: %v", p, err)
+		}
+	}
+
+	var selected []topics.Topic
+	for _, t := range topics.All() {
+		if tag != "" && !slices.Contains(t.Tags(), tag) {
+			continue
+		}
+		if len(patterns) > 0 && !slices.ContainsFunc(patterns, func(p string) bool {
+			ok, _ := path.Match(p, t.ID())
+			return ok
+		}) {
+			continue
+		}
+		selected = append(selected, t)
+	}
+
+	if len(selected) == 0 {
+		if tag != "" {
+			return nil, usageErrorf("no topic matches %q with tag %q; run \"list\" to see topics", patterns, tag)
+		}
+		return nil, usageErrorf("no topic matches %q; run \"list\" to see topics", patterns)
+	}
+	return selected, nil
+}
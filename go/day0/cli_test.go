@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunCLI(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		code      int
+		stdout    []string // must appear on stdout
+		notStdout []string // must not
+		stderr    []string // must appear on stderr
+	}{
+		{"help", []string{"-h"}, 0, []string{"Usage: day0", "Commands:"}, nil, nil},
+		{"command help", []string{"list", "-h"}, 0, nil, nil, []string{"Usage: day0 list", "-tag"}},
+		{"unknown command", []string{"frobnicate"}, 2, nil, nil, []string{`unknown command "frobnicate"`, "Usage: day0"}},
+		{"unknown flag", []string{"list", "--nosuch"}, 2, nil, nil, []string{"flag provided but not defined: -nosuch"}},
+
+		{"list", []string{"list"}, 0, []string{"alignment", "pooling", "interfaces"}, nil, nil},
+		{"list glob", []string{"list", "pool*"}, 0, []string{"pooling"}, []string{"alignment", "stack-vs-heap"}, nil},
+		{"list tag", []string{"list", "--tag", "gc"}, 0, []string{"pooling", "stack-vs-heap"}, []string{"alignment", "batching"}, nil},
+		{"list tag and glob", []string{"list", "--tag", "gc", "stack*", "batching"}, 0, []string{"stack-vs-heap"}, []string{"pooling", "batching"}, nil},
+		{"unknown topic", []string{"describe", "nosuch"}, 2, nil, nil, []string{`error: no topic matches ["nosuch"]`}},
+		{"invalid glob", []string{"list", "[pool"}, 2, nil, nil, []string{`error: invalid topic pattern "[pool"`}},
+		{"empty selection", []string{"run", "--no-bench", "--tag", "gc", "alignment"}, 2, nil, nil, []string{`no topic matches ["alignment"] with tag "gc"`}},
+
+		{"describe", []string{"describe", "pooling"}, 0, []string{"OBJECT POOLING", "sync.Pool"}, []string{"ALIGNMENT"}, nil},
+		{"describe without topics", []string{"describe"}, 2, nil, nil, []string{"Usage: day0 describe"}},
+		{"run", []string{"run", "--no-bench", "immutable"}, 0, []string{"This run covers 1 of"}, []string{"Benchmark"}, nil},
+		{"bench", []string{"bench", "--benchtime", "1ms", "receiver-types"}, 0, []string{"BenchmarkIncrementByValue"}, []string{"OBJECT POOLING"}, nil},
+		{"bench json", []string{"bench", "--benchtime", "1ms", "--format", "json", "receiver-types"}, 0, []string{`"topic": "receiver-types"`}, []string{"==="}, nil},
+		{"bench compare without baseline", []string{"bench", "compare", "no-such-baseline.json"}, 1, nil, nil, []string{"error:", "no-such-baseline.json"}},
+		{"gcsweep unknown benchmark", []string{"gcsweep", "BenchmarkNoSuch*"}, 2, nil, nil, []string{`error: no benchmark matches ["BenchmarkNoSuch*"]`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runCLI(tc.args, &stdout, &stderr); code != tc.code {
+				t.Errorf("exit code = %d, want %d\nstderr:\n%s", code, tc.code, stderr.String())
+			}
+			for _, s := range tc.stdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout lacks %q:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tc.notStdout {
+				if strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout has %q:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tc.stderr {
+				if !strings.Contains(stderr.String(), s) {
+					t.Errorf("stderr lacks %q:\n%s", s, stderr.String())
+				}
+			}
+		})
+	}
+}
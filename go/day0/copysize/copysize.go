@@ -0,0 +1,206 @@
+// Package copysize defines an Analyzer that reports values too large to copy
+// implicitly: parameters, value receivers and range variables whose type is
+// bigger than a threshold, and the calls that pay for those copies.
+//
+// A value receiver or parameter is copied on every call, a range value
+// variable on every iteration. Below a few cache lines the copy is cheap and
+// often cheaper than the indirection; above it the copy dominates, as the
+// pass-by-value and receiver-types topics measure. The default threshold of
+// 128 bytes is two cache lines.
+package copysize
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"day0/analysis"
+)
+
+const doc = `report parameters, receivers and range variables copied by value that exceed a size threshold
+
+Every call copies its value receiver and value parameters, every iteration
+its range value variable. For types larger than -threshold bytes the copy
+usually costs more than the pointer it replaces. Declarations are reported
+once with the size of the type; each call is reported with the total number
+of bytes it copies.`
+
+// Analyzer reports oversized value copies.
+var Analyzer = &analysis.Analyzer{
+	Name: "copysize",
+	Doc:  doc,
+	Run:  run,
+}
+
+// threshold is the largest size in bytes that is copied without a report.
+var threshold int64
+
+func init() {
+	Analyzer.Flags.Int64Var(&threshold, "threshold", 128, "largest value size in bytes copied without a report")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Recv != nil {
+					checkFields(pass, n.Recv, "receiver", "value receiver", "use a pointer receiver")
+				}
+				checkFields(pass, n.Type.Params, "parameter", "parameter", "pass a pointer")
+			case *ast.FuncLit:
+				checkFields(pass, n.Type.Params, "parameter", "parameter", "pass a pointer")
+			case *ast.RangeStmt:
+				checkRange(pass, n)
+			case *ast.CallExpr:
+				checkCall(pass, n)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// sizeof returns the size of t if it is known and above the threshold.
+func sizeof(pass *analysis.Pass, t types.Type) (int64, bool) {
+	if t == nil || hasTypeParam(t) {
+		return 0, false // the size depends on the instantiation
+	}
+	size := pass.TypesSizes.Sizeof(t)
+	return size, size > threshold
+}
+
+// hasTypeParam reports whether the layout of t depends on a type parameter.
+// Pointers, slices, maps and the like are one or a few words whatever their
+// element type, so only struct fields, array elements and type arguments are
+// followed.
+func hasTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		for arg := range t.TypeArgs().Types() {
+			if hasTypeParam(arg) {
+				return true
+			}
+		}
+		return false
+	case *types.Alias:
+		return hasTypeParam(types.Unalias(t))
+	case *types.Struct:
+		for field := range t.Fields() {
+			if hasTypeParam(field.Type()) {
+				return true
+			}
+		}
+	case *types.Array:
+		return hasTypeParam(t.Elem())
+	}
+	return false
+}
+
+// checkFields reports the named parameters or receiver of a declaration whose
+// type is too large.
+func checkFields(pass *analysis.Pass, fields *ast.FieldList, category, what, fix string) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		t := pass.TypesInfo.TypeOf(field.Type)
+		if _, ok := t.(*types.Pointer); ok {
+			continue
+		}
+		size, ok := sizeof(pass, t)
+		if !ok {
+			continue
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("_")}
+			names[0].NamePos = field.Type.Pos()
+		}
+		for _, name := range names {
+			report(pass, name.Pos(), category, "%s %s copies %s (%d bytes) on every call; %s",
+				what, name.Name, typeString(pass, t), size, fix)
+		}
+	}
+}
+
+// checkRange reports a range value variable of a too large type.
+func checkRange(pass *analysis.Pass, rs *ast.RangeStmt) {
+	if rs.Value == nil {
+		return
+	}
+	if id, ok := rs.Value.(*ast.Ident); ok && id.Name == "_" {
+		return
+	}
+	t := pass.TypesInfo.TypeOf(rs.Value)
+	if size, ok := sizeof(pass, t); ok {
+		report(pass, rs.Value.Pos(), "range", "range variable %s copies %s (%d bytes) per iteration; range over the index and take the address",
+			types.ExprString(rs.Value), typeString(pass, t), size)
+	}
+}
+
+// checkCall reports a call that copies a too large receiver or argument, with
+// the total number of bytes copied.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	fun := ast.Unparen(call.Fun)
+	if tv, ok := pass.TypesInfo.Types[fun]; !ok || tv.IsType() || tv.IsBuiltin() {
+		return // conversion or builtin
+	}
+	sig, ok := pass.TypesInfo.TypeOf(fun).Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+
+	var total int64
+	var parts []string
+	add := func(name string, t types.Type) {
+		if size, ok := sizeof(pass, t); ok {
+			total += size
+			parts = append(parts, fmt.Sprintf("%s %s (%d bytes)", name, typeString(pass, t), size))
+		}
+	}
+	name := types.ExprString(fun)
+	if sel, ok := fun.(*ast.SelectorExpr); ok {
+		if s := pass.TypesInfo.Selections[sel]; s != nil && s.Kind() == types.MethodVal {
+			m := s.Obj().(*types.Func)
+			recv := m.Signature().Recv().Type()
+			name = strings.TrimPrefix(typeString(pass, s.Recv()), "*") + "." + m.Name()
+			if _, isPtr := recv.(*types.Pointer); !isPtr && !types.IsInterface(recv) {
+				add("receiver", recv)
+			}
+		}
+	}
+	params := sig.Params()
+	for i := range params.Len() {
+		if sig.Variadic() && i == params.Len()-1 {
+			break // the arguments are collected in a slice
+		}
+		add(paramName(params.At(i).Name(), i), params.At(i).Type())
+	}
+
+	if total > 0 {
+		report(pass, call.Lparen, "call", "call to %s copies %d bytes: %s", name, total, strings.Join(parts, ", "))
+	}
+}
+
+// report reports a diagnostic in one of the categories receiver, parameter,
+// range and call.
+func report(pass *analysis.Pass, pos token.Pos, category, format string, args ...any) {
+	pass.Report(analysis.Diagnostic{Pos: pos, Category: category, Message: fmt.Sprintf(format, args...)})
+}
+
+// paramName names a parameter in a message, by position if it is unnamed.
+func paramName(name string, i int) string {
+	if name == "" || name == "_" {
+		return fmt.Sprintf("argument %d", i+1)
+	}
+	return name
+}
+
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
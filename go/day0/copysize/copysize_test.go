@@ -0,0 +1,160 @@
+package copysize_test
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"day0/analysis/checker"
+	"day0/copysize"
+)
+
+// wants returns the expectations of the `// want "regexp"` comments in the
+// files of dir, by line, in the style of x/tools' analysistest.
+func wants(t *testing.T, dir string) map[int]*regexp.Regexp {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	want := map[int]*regexp.Regexp{}
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				text, ok := strings.CutPrefix(c.Text, "// want ")
+				if !ok {
+					continue
+				}
+				want[fset.Position(c.Pos()).Line] = regexp.MustCompile(strings.Trim(text, "`\""))
+			}
+		}
+	}
+	return want
+}
+
+func TestAnalyzer(t *testing.T) {
+	dir := filepath.Join("testdata", "src", "copies")
+	findings, err := checker.Run([]string{dir}, copysize.Analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := wants(t, dir)
+	for _, f := range findings {
+		re, ok := want[f.Pos.Line]
+		switch {
+		case !ok:
+			t.Errorf("unexpected diagnostic: %s", f)
+		case !re.MatchString(f.Message):
+			t.Errorf("%s: diagnostic %q does not match %q", f.Pos, f.Message, re)
+		}
+		delete(want, f.Pos.Line)
+	}
+	for line, re := range want {
+		t.Errorf("line %d: no diagnostic matching %q", line, re)
+	}
+}
+
+// TestTopics checks the receiver-types topic: ProcessByValue copies a
+// LargeStruct on every call, IncrementByValue only an int.
+func TestTopics(t *testing.T) {
+	findings, err := checker.Run([]string{"../topics"}, copysize.Analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var processByValue bool
+	for _, f := range findings {
+		if strings.Contains(f.Message, "value receiver dp copies DataProcessor") {
+			processByValue = true
+		}
+		if strings.Contains(f.Message, "Counter") {
+			t.Errorf("Counter reported: %s", f)
+		}
+	}
+	if !processByValue {
+		t.Error("DataProcessor.ProcessByValue not reported")
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	flag := copysize.Analyzer.Flags.Lookup("threshold")
+	defer flag.Value.Set(flag.DefValue)
+	if err := flag.Value.Set("256"); err != nil {
+		t.Fatal(err)
+	}
+	findings, err := checker.Run([]string{filepath.Join("testdata", "src", "copies")}, copysize.Analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		if !strings.Contains(f.Message, "[64]Small") {
+			t.Errorf("256-byte Big reported with -threshold=256: %s", f)
+		}
+	}
+}
+
+// TestGoVet runs the day0 binary under go vet -vettool, so that the private
+// copy of the vet protocol in analysis/unitchecker is checked against the go
+// command it has to keep up with.
+func TestGoVet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds day0 and runs go vet")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	vettool := filepath.Join(t.TempDir(), "day0")
+	if out, err := exec.Command(goBin, "build", "-o", vettool, "..").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	dir := filepath.Join("testdata", "src", "copies")
+	vet := func(flags ...string) map[string][]struct{ Posn, Message string } {
+		t.Helper()
+		args := append([]string{"vet", "-vettool=" + vettool, "-json"}, flags...)
+		out, err := exec.Command(goBin, append(args, "./"+filepath.ToSlash(dir))...).Output()
+		if err != nil {
+			t.Fatalf("go vet: %v\n%s", err, out)
+		}
+		var diags map[string]map[string][]struct{ Posn, Message string }
+		if err := json.Unmarshal(out, &diags); err != nil {
+			t.Fatalf("go vet -json output: %v\n%s", err, out)
+		}
+		return diags["day0/copysize/testdata/src/copies"]
+	}
+
+	want := wants(t, dir)
+	for _, d := range vet()["copysize"] {
+		fields := strings.Split(d.Posn, ":") // file:line:column
+		line, _ := strconv.Atoi(fields[len(fields)-2])
+		re, ok := want[line]
+		switch {
+		case !ok:
+			t.Errorf("unexpected diagnostic: %s: %s", d.Posn, d.Message)
+		case !re.MatchString(d.Message):
+			t.Errorf("%s: diagnostic %q does not match %q", d.Posn, d.Message, re)
+		}
+		delete(want, line)
+	}
+	for line, re := range want {
+		t.Errorf("line %d: no diagnostic matching %q", line, re)
+	}
+
+	// go vet passes the analyzer's flags through.
+	for _, d := range vet("-copysize.threshold=256")["copysize"] {
+		if !strings.Contains(d.Message, "[64]Small") {
+			t.Errorf("256-byte Big reported with -copysize.threshold=256: %s", d.Message)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package copies
+
+type Big struct {
+	Data [32]int64 // 256 bytes
+}
+
+type Small struct {
+	A, B int64
+}
+
+func (b Big) Value() int64 { // want `value receiver b copies Big \(256 bytes\) on every call; use a pointer receiver`
+	return b.Data[0]
+}
+
+func (b *Big) Pointer() int64 { return b.Data[0] }
+
+func (s Small) Sum() int64 { return s.A + s.B }
+
+func Both(a Big, s Small, b *Big) int64 { // want `parameter a copies Big \(256 bytes\) on every call; pass a pointer`
+	return a.Data[0] + s.A + b.Data[0]
+}
+
+func Unnamed(Big) {} // want `parameter _ copies Big \(256 bytes\)`
+
+func Variadic(bs ...Big) int { return len(bs) }
+
+func Generic[T any](v T, arr [64]T) T { return v }
+
+type Valuer interface{ Value() int64 }
+
+func Calls(b Big, p *Big, s Small, v Valuer, bs []Big) int64 { // want `parameter b copies Big`
+	n := b.Value() // want `call to Big.Value copies 256 bytes: receiver Big \(256 bytes\)`
+	n += p.Value() // want `call to Big.Value copies 256 bytes`
+	n += p.Pointer()
+	n += s.Sum()
+	n += v.Value()
+	n += Both(b, s, p)             // want `call to Both copies 256 bytes: a Big \(256 bytes\)`
+	Unnamed(b)                     // want `call to Unnamed copies 256 bytes: argument 1 Big \(256 bytes\)`
+	n += int64(Variadic(b))        // the slice is passed, not the values
+	n += Generic(s, [64]Small{}).A // want `call to Generic copies 1024 bytes: arr \[64\]Small \(1024 bytes\)`
+	f := func(x Big) {}            // want `parameter x copies Big`
+	f(b)                           // want `call to f copies 256 bytes: x Big \(256 bytes\)`
+	for _, x := range bs {         // want `range variable x copies Big \(256 bytes\) per iteration`
+		n += x.Data[1]
+	}
+	for i := range bs {
+		n += bs[i].Data[1]
+	}
+	for _, x := range []Small{s} {
+		n += x.A
+	}
+	return n
+}
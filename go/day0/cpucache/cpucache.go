@@ -0,0 +1,157 @@
+// Package cpucache reports the CPU cache hierarchy, so the demos can size
+// their working sets against the real L1, L2 and L3 instead of assuming them.
+//
+// On Linux the sizes come from /sys/devices/system/cpu; elsewhere, or when
+// sysfs is not readable, typical desktop values are used and Info says so.
+package cpucache
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Cache is one cache level as seen by CPU 0.
+type Cache struct {
+	Level    int
+	Type     string // "Data", "Instruction" or "Unified"
+	Size     int64  // bytes
+	LineSize int64  // bytes
+}
+
+// Info is the cache hierarchy and where it was read from.
+type Info struct {
+	Caches []Cache
+	Source string // "/sys/devices/system/cpu" or a note that defaults are used
+}
+
+// Defaults are used where the hierarchy cannot be read.
+var Defaults = []Cache{
+	{Level: 1, Type: "Data", Size: 32 << 10, LineSize: 64},
+	{Level: 2, Type: "Unified", Size: 1 << 20, LineSize: 64},
+	{Level: 3, Type: "Unified", Size: 16 << 20, LineSize: 64},
+}
+
+// sysfs is the root of the CPU topology on Linux.
+const sysfs = "/sys/devices/system/cpu"
+
+// Detect returns the cache hierarchy of this machine, or Defaults.
+func Detect() Info {
+	caches, err := Read(os.DirFS(sysfs))
+	if err != nil || len(caches) == 0 {
+		return Info{Caches: Defaults, Source: "typical sizes (" + sysfs + " not available)"}
+	}
+	return Info{Caches: caches, Source: sysfs}
+}
+
+// Read parses cpu0/cache/index*/ of a sysfs CPU directory. Instruction
+// caches are included; use DataSize for the sizes relevant to data.
+func Read(fsys fs.FS) ([]Cache, error) {
+	dirs, err := fs.Glob(fsys, "cpu0/cache/index*")
+	if err != nil {
+		return nil, err
+	}
+	var caches []Cache
+	for _, dir := range dirs {
+		attr := func(name string) (string, error) {
+			b, err := fs.ReadFile(fsys, path.Join(dir, name))
+			return strings.TrimSpace(string(b)), err
+		}
+		level, err := attr("level")
+		if err != nil {
+			return nil, err
+		}
+		typ, err := attr("type")
+		if err != nil {
+			return nil, err
+		}
+		size, err := attr("size")
+		if err != nil {
+			return nil, err
+		}
+		line, err := attr("coherency_line_size")
+		if err != nil {
+			return nil, err
+		}
+
+		c := Cache{Type: typ}
+		if c.Level, err = strconv.Atoi(level); err != nil {
+			return nil, fmt.Errorf("cpucache: %s/level: %w", dir, err)
+		}
+		if c.Size, err = ParseSize(size); err != nil {
+			return nil, fmt.Errorf("cpucache: %s/size: %w", dir, err)
+		}
+		if c.LineSize, err = strconv.ParseInt(line, 10, 64); err != nil {
+			return nil, fmt.Errorf("cpucache: %s/coherency_line_size: %w", dir, err)
+		}
+		caches = append(caches, c)
+	}
+	slices.SortStableFunc(caches, func(a, b Cache) int { return cmp.Compare(a.Level, b.Level) })
+	return caches, nil
+}
+
+// ParseSize parses a sysfs size such as "48K", "1024K" or "32M".
+func ParseSize(s string) (int64, error) {
+	shift := 0
+	switch {
+	case strings.HasSuffix(s, "K"):
+		shift = 10
+	case strings.HasSuffix(s, "M"):
+		shift = 20
+	case strings.HasSuffix(s, "G"):
+		shift = 30
+	}
+	n, err := strconv.ParseInt(strings.TrimRight(s, "KMG"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n << shift, nil
+}
+
+// DataSize returns the size of the data or unified cache at level, or 0 if
+// there is none.
+func (i Info) DataSize(level int) int64 {
+	for _, c := range i.Caches {
+		if c.Level == level && c.Type != "Instruction" {
+			return c.Size
+		}
+	}
+	return 0
+}
+
+// Levels returns the data cache levels, lowest first.
+func (i Info) Levels() []int {
+	var levels []int
+	for _, c := range i.Caches {
+		if c.Type != "Instruction" && !slices.Contains(levels, c.Level) {
+			levels = append(levels, c.Level)
+		}
+	}
+	return levels
+}
+
+// LineSize returns the cache line size of the L1 data cache, or 64.
+func (i Info) LineSize() int64 {
+	for _, c := range i.Caches {
+		if c.Type != "Instruction" && c.LineSize > 0 {
+			return c.LineSize
+		}
+	}
+	return 64
+}
+
+// Fits returns the name of the smallest data cache that holds n bytes, like
+// "L2", or "DRAM".
+func (i Info) Fits(n int64) string {
+	for _, level := range i.Levels() {
+		if n <= i.DataSize(level) {
+			return "L" + strconv.Itoa(level)
+		}
+	}
+	return "DRAM"
+}
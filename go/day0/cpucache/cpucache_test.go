@@ -0,0 +1,44 @@
+package cpucache
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestRead(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for dir, attrs := range map[string][4]string{
+		"index0": {"1", "Data", "48K", "64"},
+		"index1": {"1", "Instruction", "32K", "64"},
+		"index2": {"2", "Unified", "2048K", "64"},
+		"index3": {"3", "Unified", "32M", "64"},
+	} {
+		for i, name := range []string{"level", "type", "size", "coherency_line_size"} {
+			fsys["cpu0/cache/"+dir+"/"+name] = &fstest.MapFile{Data: []byte(attrs[i] + "\n")}
+		}
+	}
+
+	caches, err := Read(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{Caches: caches}
+	if len(caches) != 4 || info.DataSize(1) != 48<<10 || info.DataSize(2) != 2<<20 || info.DataSize(3) != 32<<20 {
+		t.Fatalf("caches = %+v", caches)
+	}
+	if info.LineSize() != 64 {
+		t.Errorf("LineSize = %d", info.LineSize())
+	}
+	for n, want := range map[int64]string{1 << 10: "L1", 48 << 10: "L1", 64 << 10: "L2", 8 << 20: "L3", 64 << 20: "DRAM"} {
+		if got := info.Fits(n); got != want {
+			t.Errorf("Fits(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestReadMissing(t *testing.T) {
+	caches, err := Read(fstest.MapFS{})
+	if err != nil || len(caches) != 0 {
+		t.Errorf("Read(empty) = %v, %v", caches, err)
+	}
+}
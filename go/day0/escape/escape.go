@@ -0,0 +1,222 @@
+// Package escape runs the compiler's escape analysis on a package and turns its
+// diagnostics into structured records, so the demos can show what the compiler
+// actually decided instead of asserting it.
+//
+// It needs the go command and the package sources, which the rest of the demo
+// binary does not; callers should treat an error as "not available here".
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind classifies a diagnostic.
+type Kind string
+
+const (
+	MovedToHeap   Kind = "moved to heap"
+	EscapesToHeap Kind = "escapes to heap"
+	DoesNotEscape Kind = "does not escape"
+	LeakingParam  Kind = "leaking param"
+	InliningCall  Kind = "inlining call"
+	CanInline     Kind = "can inline"
+	CannotInline  Kind = "cannot inline"
+	Devirtualized Kind = "devirtualizing"
+	Other         Kind = "other"
+)
+
+// Diagnostic is one line of `go build -gcflags=-m=2` output.
+type Diagnostic struct {
+	File     string // path as printed by the compiler
+	Line     int
+	Column   int
+	Function string // enclosing function, e.g. "ProcessSliceNoEscape" or "(*Buffer).Write"
+	Variable string // the variable, expression, parameter or callee the verdict is about
+	Kind     Kind
+	Message  string // the compiler's text
+}
+
+// Analyze compiles pkg with -gcflags=-m=2 from the current directory and
+// returns its diagnostics. Build results are cached by the go command, which
+// replays the diagnostics, so repeated calls are cheap.
+func Analyze(pkg string) ([]Diagnostic, error) {
+	return analyze(pkg, "-gcflags=-m=2")
+}
+
+// AnalyzeProfile is Analyze with the CPU profile in the pprof file profile
+// applied to pkg, so the diagnostics include the decisions of profile-guided
+// optimization. Only pkg is compiled with the profile: -pgo would rebuild
+// every dependency, the standard library included, for each new profile.
+func AnalyzeProfile(pkg, profile string) ([]Diagnostic, error) {
+	return analyze(pkg, ProfileGcflags(pkg, profile, "-m=2"))
+}
+
+// ProfileGcflags returns a -gcflags argument that compiles pkg with the CPU
+// profile in the pprof file profile and the other compiler flags.
+func ProfileGcflags(pkg, profile string, flags ...string) string {
+	return fmt.Sprintf("-gcflags=%s=%s", pkg, strings.Join(append(flags, "-pgoprofile="+profile), " "))
+}
+
+func analyze(pkg, gcflags string) ([]Diagnostic, error) {
+	cmd := exec.Command("go", "build", gcflags, pkg)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go build %s %s: %w\n%s", gcflags, pkg, err, strings.TrimSpace(stderr.String()))
+	}
+
+	diags, err := Parse(&stderr)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveFunctions(diags); err != nil {
+		return nil, err
+	}
+	return diags, nil
+}
+
+// diagLine matches "file.go:line:col: message".
+var diagLine = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+
+// Parse reads compiler output. The indented flow explanations of -m=2 and the
+// "... in F:" headers that introduce them are skipped, since the compiler also
+// prints each verdict on a line of its own; duplicates are dropped.
+func Parse(r io.Reader) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		m := diagLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue // "# package" headers
+		}
+		msg := m[4]
+		if strings.HasPrefix(msg, " ") || strings.HasSuffix(msg, ":") {
+			continue
+		}
+		key := m[1] + ":" + m[2] + ":" + m[3] + ":" + msg
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		line, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		d := Diagnostic{File: m[1], Line: line, Column: col, Message: msg}
+		d.Kind, d.Variable = classify(msg)
+		diags = append(diags, d)
+	}
+	return diags, sc.Err()
+}
+
+// classify extracts the kind and the subject of a message.
+func classify(msg string) (Kind, string) {
+	switch {
+	case strings.HasPrefix(msg, "moved to heap: "):
+		return MovedToHeap, strings.TrimPrefix(msg, "moved to heap: ")
+	case strings.HasSuffix(msg, " escapes to heap"):
+		return EscapesToHeap, strings.TrimSuffix(msg, " escapes to heap")
+	case strings.HasSuffix(msg, " does not escape"):
+		return DoesNotEscape, strings.TrimSuffix(msg, " does not escape")
+	case strings.HasPrefix(msg, "leaking param"):
+		// "leaking param: p" or "leaking param content: p"
+		_, param, _ := strings.Cut(msg, ": ")
+		return LeakingParam, param
+	case strings.HasPrefix(msg, "inlining call to "):
+		return InliningCall, strings.TrimPrefix(msg, "inlining call to ")
+	case strings.HasPrefix(msg, "can inline "):
+		name, _, _ := strings.Cut(strings.TrimPrefix(msg, "can inline "), " ")
+		return CanInline, name
+	case strings.Contains(msg, "devirtualizing "):
+		// "devirtualizing inc.Increment to *Counter" or, with a profile,
+		// "PGO devirtualizing interface call inc.Increment to (*Counter).Increment"
+		_, call, _ := strings.Cut(msg, "devirtualizing ")
+		call = strings.TrimPrefix(call, "interface call ")
+		call, _, _ = strings.Cut(call, " to ")
+		return Devirtualized, call
+	case strings.HasPrefix(msg, "cannot inline "):
+		name, _, _ := strings.Cut(strings.TrimPrefix(msg, "cannot inline "), ":")
+		return CannotInline, name
+	}
+	return Other, ""
+}
+
+// resolveFunctions fills in the enclosing function of every diagnostic by
+// parsing the source files it refers to.
+func resolveFunctions(diags []Diagnostic) error {
+	funcs := map[string][]*ast.FuncDecl{}
+	fset := token.NewFileSet()
+	for i := range diags {
+		d := &diags[i]
+		decls, ok := funcs[d.File]
+		if !ok {
+			f, err := parser.ParseFile(fset, d.File, nil, parser.SkipObjectResolution)
+			if err != nil {
+				return err
+			}
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					decls = append(decls, fn)
+				}
+			}
+			funcs[d.File] = decls
+		}
+		for _, fn := range decls {
+			start, end := fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line
+			if d.Line >= start && d.Line <= end {
+				d.Function = funcName(fn)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// funcName formats a declaration the way the compiler names it, e.g. "(*T).M".
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if idx, ok := recv.(*ast.IndexExpr); ok { // generic receiver T[P]
+		recv = idx.X
+	}
+	switch t := recv.(type) {
+	case *ast.StarExpr:
+		if idx, ok := t.X.(*ast.IndexExpr); ok {
+			return fmt.Sprintf("(*%s).%s", idx.X, fn.Name.Name)
+		}
+		return fmt.Sprintf("(*%s).%s", t.X, fn.Name.Name)
+	case *ast.Ident:
+		return t.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// ForFunction returns the diagnostics inside the named function, including
+// the inlining verdict reported at its declaration.
+func ForFunction(diags []Diagnostic, name string) []Diagnostic {
+	var result []Diagnostic
+	for _, d := range diags {
+		if d.Function == name {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Pos formats the position as "file.go:line".
+func (d Diagnostic) Pos() string {
+	return filepath.Base(d.File) + ":" + strconv.Itoa(d.Line)
+}
@@ -0,0 +1,54 @@
+package escape
+
+import (
+	"strings"
+	"testing"
+)
+
+const output = `# day0/topics
+topics/slice_escape.go:64:6: can inline ProcessSliceWithEscape with cost 22 as: func(int) int { s := make([]int, n); globalSlice = s; return len(s) }
+topics/slice_escape.go:65:11: make([]int, n) escapes to heap in ProcessSliceWithEscape:
+topics/slice_escape.go:65:11:   flow: {heap} ← s:
+topics/slice_escape.go:65:11:     from globalSlice = s (assign) at topics/slice_escape.go:71:14
+topics/slice_escape.go:65:11: make([]int, n) escapes to heap
+topics/slice_escape.go:89:11: make([]int, n) does not escape
+topics/return_optimization.go:84:2: moved to heap: c
+topics/return_optimization.go:98:32: leaking param: out
+topics/slice_escape.go:152:48: inlining call to strconv.Itoa
+topics/slice_escape.go:152:48: inlining call to strconv.Itoa
+topics/stack_vs_heap.go:113:6: cannot inline RunStackVsHeapDemo: function too complex: cost 2398 exceeds budget 80
+topics/interface_dispatch.go:96:22: devirtualizing inc.Increment to *Counter
+topics/interface_dispatch.go:71:23: PGO devirtualizing interface call inc.Increment to (*Counter).Increment
+`
+
+func TestParse(t *testing.T) {
+	diags, err := Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		line     int
+		kind     Kind
+		variable string
+	}{
+		{64, CanInline, "ProcessSliceWithEscape"},
+		{65, EscapesToHeap, "make([]int, n)"},
+		{89, DoesNotEscape, "make([]int, n)"},
+		{84, MovedToHeap, "c"},
+		{98, LeakingParam, "out"},
+		{152, InliningCall, "strconv.Itoa"},
+		{113, CannotInline, "RunStackVsHeapDemo"},
+		{96, Devirtualized, "inc.Increment"},
+		{71, Devirtualized, "inc.Increment"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %+v", len(diags), len(want), diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Line != w.line || d.Kind != w.kind || d.Variable != w.variable {
+			t.Errorf("diagnostic %d = %d %q %q, want %d %q %q", i, d.Line, d.Kind, d.Variable, w.line, w.kind, w.variable)
+		}
+	}
+}
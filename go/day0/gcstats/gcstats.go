@@ -0,0 +1,112 @@
+// Package gcstats measures what a piece of code costs the garbage collector,
+// from runtime/metrics read before and after it runs.
+//
+// Timings only show GC pressure indirectly: a workload that allocates heavily
+// may run fast in a demo and still make every other goroutine of a service
+// pay for the collections it triggers. The counters here make the pressure
+// itself visible: how many cycles ran, how long the world was stopped, and
+// how many bytes and objects were allocated.
+package gcstats
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// The metrics a Snapshot reads, in the order of the samples.
+var names = []string{
+	"/gc/cycles/total:gc-cycles",
+	"/cpu/classes/gc/pause:cpu-seconds",
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/gc/heap/allocs:bytes",
+	"/gc/heap/allocs:objects",
+	"/gc/heap/objects:objects",
+}
+
+// Snapshot is the state of the GC at one point in time.
+type Snapshot struct {
+	Cycles       uint64        // completed GC cycles
+	Pause        time.Duration // total stop-the-world time of the GC
+	CPU          time.Duration // total CPU time spent on GC work, including pauses
+	AllocBytes   uint64        // bytes allocated on the heap since the program started
+	AllocObjects uint64        // objects allocated on the heap since the program started
+	Objects      uint64        // live heap objects, as of the last GC
+}
+
+// Read returns the current snapshot.
+//
+// The runtime only reports the pause time as CPU time of all Ps, so Pause is
+// that divided by the current GOMAXPROCS; it is exact while GOMAXPROCS does
+// not change. Allocations are counted per span, not per object, so deltas of
+// a few small allocations are rounded.
+func Read() Snapshot {
+	samples := make([]metrics.Sample, len(names))
+	for i, name := range names {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	seconds := func(v metrics.Value) time.Duration {
+		return time.Duration(v.Float64() * float64(time.Second))
+	}
+	return Snapshot{
+		Cycles:       samples[0].Value.Uint64(),
+		Pause:        seconds(samples[1].Value) / time.Duration(runtime.GOMAXPROCS(0)),
+		CPU:          seconds(samples[2].Value),
+		AllocBytes:   samples[3].Value.Uint64(),
+		AllocObjects: samples[4].Value.Uint64(),
+		Objects:      samples[5].Value.Uint64(),
+	}
+}
+
+// Sub returns what happened between an earlier snapshot and s.
+func (s Snapshot) Sub(earlier Snapshot) Delta {
+	return Delta{
+		Cycles:       s.Cycles - earlier.Cycles,
+		Pause:        s.Pause - earlier.Pause,
+		CPU:          s.CPU - earlier.CPU,
+		AllocBytes:   s.AllocBytes - earlier.AllocBytes,
+		AllocObjects: s.AllocObjects - earlier.AllocObjects,
+		Objects:      int64(s.Objects) - int64(earlier.Objects),
+	}
+}
+
+// Delta is the GC activity between two snapshots.
+type Delta struct {
+	Cycles       uint64        // GC cycles that completed
+	Pause        time.Duration // stop-the-world time of the GC
+	CPU          time.Duration // CPU time spent on GC work
+	AllocBytes   uint64        // bytes allocated on the heap
+	AllocObjects uint64        // objects allocated on the heap
+	Objects      int64         // change in live heap objects
+}
+
+// Measure runs f and returns the GC activity during it. Other goroutines
+// that allocate meanwhile are counted too.
+func Measure(f func()) Delta {
+	before := Read()
+	f()
+	return Read().Sub(before)
+}
+
+func (d Delta) String() string {
+	return fmt.Sprintf("%d GC cycles, %v paused, %v GC CPU, %s allocated in %d objects",
+		d.Cycles, d.Pause.Round(time.Microsecond), d.CPU.Round(time.Microsecond), formatBytes(d.AllocBytes), d.AllocObjects)
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "1.5 MB".
+func formatBytes(n uint64) string {
+	v, unit := float64(n), "B"
+	for _, u := range []string{"KB", "MB", "GB", "TB"} {
+		if v < 1024 {
+			break
+		}
+		v, unit = v/1024, u
+	}
+	if unit == "B" {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", v, unit)
+}
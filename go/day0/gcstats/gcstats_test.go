@@ -0,0 +1,40 @@
+package gcstats
+
+import (
+	"runtime"
+	"testing"
+)
+
+var sink []byte
+
+func TestMeasure(t *testing.T) {
+	const allocs, size = 1000, 4096
+	d := Measure(func() {
+		for range allocs {
+			sink = make([]byte, size)
+		}
+		runtime.GC()
+	})
+	if d.Cycles < 1 {
+		t.Errorf("Cycles = %d after runtime.GC", d.Cycles)
+	}
+	if d.AllocBytes < allocs*size || d.AllocObjects < allocs {
+		t.Errorf("allocated %d bytes in %d objects, want at least %d in %d", d.AllocBytes, d.AllocObjects, allocs*size, allocs)
+	}
+	if d.Pause <= 0 || d.CPU < d.Pause {
+		t.Errorf("Pause = %v, CPU = %v; want 0 < Pause <= CPU", d.Pause, d.CPU)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for _, tc := range []struct {
+		n    uint64
+		want string
+	}{
+		{0, "0 B"}, {1023, "1023 B"}, {1536, "1.5 KB"}, {100 << 20, "100.0 MB"},
+	} {
+		if got := formatBytes(tc.n); got != tc.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package layout
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+)
+
+// Archs are the architectures the cross-architecture sizes default to: the
+// 64-bit targets we deploy to and the 32-bit ones, where int64 and float64 are
+// only 4-byte aligned and pointers are half the size.
+var Archs = []string{"amd64", "arm64", "riscv64", "arm", "386"}
+
+// ArchSize is the size of a type as the gc compiler lays it out for one GOARCH.
+type ArchSize struct {
+	Arch    string
+	Size    int64
+	Align   int64
+	Padding int64 // bytes not covered by any field, at any depth
+}
+
+// SizesFor computes the size of t for each architecture with the sizes
+// go/types uses for the gc compiler, so no cross-compilation is needed.
+// t must be a struct or a pointer to one.
+func SizesFor(t reflect.Type, archs []string) ([]ArchSize, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("layout: %s is not a struct", t)
+	}
+
+	typ := typeOf(t)
+	result := make([]ArchSize, 0, len(archs))
+	for _, arch := range archs {
+		sizes := types.SizesFor("gc", arch)
+		if sizes == nil {
+			return nil, fmt.Errorf("layout: unknown architecture %q", arch)
+		}
+		size := sizes.Sizeof(typ)
+		result = append(result, ArchSize{
+			Arch:    arch,
+			Size:    size,
+			Align:   sizes.Alignof(typ),
+			Padding: size - dataSize(sizes, typ),
+		})
+	}
+	return result, nil
+}
+
+// dataSize is the number of bytes of t occupied by fields rather than padding.
+func dataSize(sizes types.Sizes, t types.Type) int64 {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		var n int64
+		for i := range u.NumFields() {
+			n += dataSize(sizes, u.Field(i).Type())
+		}
+		return n
+	case *types.Array:
+		return u.Len() * dataSize(sizes, u.Elem())
+	}
+	return sizes.Sizeof(t)
+}
+
+// typeOf mirrors the shape of a reflect.Type as a go/types type. Only what
+// decides the layout is kept: the elements of pointers, slices, maps,
+// channels, funcs and interfaces do not affect their size, which also breaks
+// cycles through recursive types. Named structs keep their name so that
+// sync/atomic.align64 still forces 8-byte alignment on 32-bit targets.
+func typeOf(t reflect.Type) types.Type {
+	switch t.Kind() {
+	case reflect.Bool:
+		return types.Typ[types.Bool]
+	case reflect.Int:
+		return types.Typ[types.Int]
+	case reflect.Int8:
+		return types.Typ[types.Int8]
+	case reflect.Int16:
+		return types.Typ[types.Int16]
+	case reflect.Int32:
+		return types.Typ[types.Int32]
+	case reflect.Int64:
+		return types.Typ[types.Int64]
+	case reflect.Uint:
+		return types.Typ[types.Uint]
+	case reflect.Uint8:
+		return types.Typ[types.Uint8]
+	case reflect.Uint16:
+		return types.Typ[types.Uint16]
+	case reflect.Uint32:
+		return types.Typ[types.Uint32]
+	case reflect.Uint64:
+		return types.Typ[types.Uint64]
+	case reflect.Uintptr:
+		return types.Typ[types.Uintptr]
+	case reflect.Float32:
+		return types.Typ[types.Float32]
+	case reflect.Float64:
+		return types.Typ[types.Float64]
+	case reflect.Complex64:
+		return types.Typ[types.Complex64]
+	case reflect.Complex128:
+		return types.Typ[types.Complex128]
+	case reflect.String:
+		return types.Typ[types.String]
+	case reflect.UnsafePointer, reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func:
+		return types.Typ[types.UnsafePointer] // one word on every architecture
+	case reflect.Slice:
+		return types.NewSlice(types.Typ[types.Byte])
+	case reflect.Interface:
+		return types.NewInterfaceType(nil, nil).Complete()
+	case reflect.Array:
+		return types.NewArray(typeOf(t.Elem()), int64(t.Len()))
+	case reflect.Struct:
+		fields := make([]*types.Var, t.NumField())
+		for i := range t.NumField() {
+			f := t.Field(i)
+			fields[i] = types.NewField(token.NoPos, nil, f.Name, typeOf(f.Type), f.Anonymous)
+		}
+		st := types.NewStruct(fields, nil)
+		if t.Name() == "" {
+			return st
+		}
+		pkg := types.NewPackage(t.PkgPath(), "")
+		return types.NewNamed(types.NewTypeName(token.NoPos, pkg, t.Name(), nil), st, nil)
+	}
+	panic(fmt.Sprintf("layout: unexpected kind %s", t.Kind()))
+}
@@ -0,0 +1,77 @@
+package layout
+
+import (
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type node struct {
+	Flag  bool
+	Next  *node
+	Seen  map[string]bool
+	Name  string
+	Tags  []string
+	Err   error
+	When  time.Time
+	Parts [3]point
+	Done  chan struct{}
+}
+
+type counter struct {
+	Flag bool
+	N    atomic.Int64
+}
+
+func TestSizesForHost(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[order](), reflect.TypeFor[node](), reflect.TypeFor[counter](),
+	} {
+		sizes, err := SizesFor(typ, []string{runtime.GOARCH})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := sizes[0]; s.Size != int64(typ.Size()) || s.Align != int64(typ.Align()) {
+			t.Errorf("%s: SizesFor = %d (align %d), reflect = %d (align %d)",
+				typ, s.Size, s.Align, typ.Size(), typ.Align())
+		}
+	}
+}
+
+func TestSizesForArchs(t *testing.T) {
+	type unaligned struct {
+		A int8
+		B int64
+		C int8
+		D int64
+	}
+	want := map[string]ArchSize{
+		"amd64": {"amd64", 32, 8, 14},
+		"386":   {"386", 24, 4, 6},
+		"arm":   {"arm", 24, 4, 6}, // like every 32-bit target in gc
+	}
+	got, err := SizesFor(reflect.TypeFor[unaligned](), []string{"amd64", "386", "arm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range got {
+		if s != want[s.Arch] {
+			t.Errorf("%s: %+v, want %+v", s.Arch, s, want[s.Arch])
+		}
+	}
+
+	// atomic.Int64 is 8-byte aligned even on 386.
+	got, err = SizesFor(reflect.TypeFor[counter](), []string{"386"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Size != 16 {
+		t.Errorf("counter on 386 = %d bytes, want 16", got[0].Size)
+	}
+
+	if _, err := SizesFor(reflect.TypeFor[unaligned](), []string{"z80"}); err == nil {
+		t.Error("SizesFor(z80) succeeded")
+	}
+}
@@ -0,0 +1,227 @@
+// Package layout describes the memory layout of any struct type: where each
+// field sits, how much space it takes and how much padding the compiler had to
+// insert around it to satisfy alignment.
+//
+// It works on reflect.Type, so it can inspect the demo structs as well as any
+// type compiled into a program:
+//
+//	l, err := layout.Analyze(reflect.TypeFor[Order]())
+//	fmt.Print(l.Table(), l.ByteMap())
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Field is one field of a layout. Fields of nested structs, embedded structs
+// and the first element of arrays of structs follow their parent with a
+// greater Depth; offsets are always relative to the start of the outer struct.
+type Field struct {
+	Path          string // dotted path from the outer struct, e.g. "Header.ID" or "Items[0].Price"
+	Type          string
+	Offset        uintptr
+	Size          uintptr
+	Align         uintptr
+	PaddingBefore uintptr // gap between the previous sibling (or parent start) and this field
+	PaddingAfter  uintptr // gap between this field and the next sibling (or parent end)
+	Depth         int     // 0 for fields of the outer struct
+	Embedded      bool
+	Leaf          bool // not a struct and not an array of structs
+	Count         int  // number of struct elements of an array of structs, nested arrays flattened
+}
+
+// Layout is the analyzed layout of a struct type.
+type Layout struct {
+	Type    string
+	Size    uintptr
+	Align   uintptr
+	Fields  []Field
+	Padding uintptr // bytes not covered by any leaf field, at any depth
+}
+
+// Analyze returns the layout of t, which must be a struct or a pointer to one.
+func Analyze(t reflect.Type) (*Layout, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("layout: %s is not a struct", t)
+	}
+
+	l := &Layout{Type: t.String(), Size: t.Size(), Align: uintptr(t.Align())}
+	l.addFields(t, 0, "", 0)
+
+	owner, _ := l.owners()
+	for _, o := range owner {
+		if o == '.' {
+			l.Padding++
+		}
+	}
+	return l, nil
+}
+
+// addFields appends the fields of struct t located at base.
+func (l *Layout) addFields(t reflect.Type, base uintptr, prefix string, depth int) {
+	end := base // end of the previous sibling
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		f := Field{
+			Path:          prefix + sf.Name,
+			Type:          sf.Type.String(),
+			Offset:        base + sf.Offset,
+			Size:          sf.Type.Size(),
+			Align:         uintptr(sf.Type.Align()),
+			PaddingBefore: base + sf.Offset - end,
+			Depth:         depth,
+			Embedded:      sf.Anonymous,
+		}
+		next := base + t.Size()
+		if i+1 < t.NumField() {
+			next = base + t.Field(i+1).Offset
+		}
+		f.PaddingAfter = next - (f.Offset + f.Size)
+		end = f.Offset + f.Size
+
+		inner, innerPath := elemStruct(sf.Type)
+		f.Leaf = inner == nil
+		if inner != nil && inner.Size() > 0 && sf.Type.Kind() == reflect.Array {
+			f.Count = int(f.Size / inner.Size())
+		}
+		l.Fields = append(l.Fields, f)
+		if inner != nil {
+			l.addFields(inner, f.Offset, f.Path+innerPath+".", depth+1)
+		}
+	}
+}
+
+// elemStruct returns the struct type to descend into for a field type: the
+// type itself for structs, the element type for (nested) arrays of structs.
+// The path suffix selects the first element, e.g. "[0]".
+func elemStruct(t reflect.Type) (reflect.Type, string) {
+	suffix := ""
+	for t.Kind() == reflect.Array && t.Len() > 0 {
+		t = t.Elem()
+		suffix += "[0]"
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, ""
+	}
+	return t, suffix
+}
+
+// Table renders the fields as a table with offsets, sizes, alignment and padding.
+func (l *Layout) Table() string {
+	names := make([]string, len(l.Fields))
+	nameWidth, typeWidth := len("Field"), len("Type")
+	for i, f := range l.Fields {
+		names[i] = strings.Repeat("  ", f.Depth) + f.Path[strings.LastIndex(f.Path, ".")+1:]
+		if f.Embedded {
+			names[i] += " (embedded)"
+		}
+		nameWidth, typeWidth = max(nameWidth, len(names[i])), max(typeWidth, len(f.Type))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d bytes, align %d, %d bytes padding\n", l.Type, l.Size, l.Align, l.Padding)
+	fmt.Fprintf(&sb, "%-*s  %-*s %6s %5s %5s %5s %5s\n",
+		nameWidth, "Field", typeWidth, "Type", "Offset", "Size", "Align", "Pad<", "Pad>")
+	for i, f := range l.Fields {
+		fmt.Fprintf(&sb, "%-*s  %-*s %6d %5d %5d %5s %5s\n",
+			nameWidth, names[i], typeWidth, f.Type, f.Offset, f.Size, f.Align,
+			padding(f.PaddingBefore), padding(f.PaddingAfter))
+	}
+	return sb.String()
+}
+
+func padding(n uintptr) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprint(n)
+}
+
+// mapRow is the number of bytes per line of the byte map: one 64-bit word.
+const mapRow = 8
+
+// symbols label the leaf fields in the byte map.
+const symbols = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// owners returns the symbol of the leaf field occupying each byte, '.' for
+// padding, and the legend of the symbols.
+func (l *Layout) owners() ([]byte, []string) {
+	owner := make([]byte, l.Size)
+	for i := range owner {
+		owner[i] = '.'
+	}
+	var legend []string
+	n := 0
+	for _, f := range l.Fields {
+		if !f.Leaf || f.Size == 0 {
+			continue
+		}
+		sym := byte('#')
+		if n < len(symbols) {
+			sym = symbols[n]
+			legend = append(legend, fmt.Sprintf("%c=%s", sym, f.Path))
+		}
+		n++
+		for i := f.Offset; i < f.Offset+f.Size; i++ {
+			owner[i] = sym
+		}
+	}
+
+	// Only the first element of an array of structs is listed; repeat its
+	// bytes over the other elements, innermost arrays first.
+	for i := len(l.Fields) - 1; i >= 0; i-- {
+		f := l.Fields[i]
+		if f.Count < 2 {
+			continue
+		}
+		elem := f.Size / uintptr(f.Count)
+		first := owner[f.Offset : f.Offset+elem]
+		for off := f.Offset + elem; off < f.Offset+f.Size; off += elem {
+			copy(owner[off:off+elem], first)
+		}
+	}
+	return owner, legend
+}
+
+// ByteMap renders the layout one 8-byte word per line, with a letter for each
+// byte a leaf field occupies and '.' for padding, e.g.
+//
+//	0000  a . . . . . . .
+//	0008  b b b b b b b b
+//
+// Runs of identical lines are collapsed. Leaf fields beyond the available
+// symbols are shown as '#'.
+func (l *Layout) ByteMap() string {
+	owner, legend := l.owners()
+
+	var sb strings.Builder
+	var prev string
+	repeats := 0
+	flush := func() {
+		if repeats > 0 {
+			fmt.Fprintf(&sb, "      ... %d more identical words\n", repeats)
+			repeats = 0
+		}
+	}
+	for off := uintptr(0); off < l.Size; off += mapRow {
+		row := owner[off:min(off+mapRow, l.Size)]
+		line := strings.Join(strings.Split(string(row), ""), " ")
+		if line == prev {
+			repeats++
+			continue
+		}
+		flush()
+		fmt.Fprintf(&sb, "%04x  %s\n", off, line)
+		prev = line
+	}
+	flush()
+	if len(legend) > 0 {
+		fmt.Fprintf(&sb, "      %s, .=padding\n", strings.Join(legend, " "))
+	}
+	return sb.String()
+}
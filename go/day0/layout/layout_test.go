@@ -0,0 +1,83 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type point struct {
+	X int32
+	Y int8
+}
+
+type base struct {
+	ID int64
+}
+
+type order struct {
+	Flag  bool
+	base  // embedded
+	Items [2]point
+	Codes [3]byte
+	Where point
+}
+
+func TestAnalyze(t *testing.T) {
+	l, err := Analyze(reflect.TypeFor[*order]())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		path                    string
+		offset, size, pre, post uintptr
+		depth                   int
+	}{
+		{"Flag", 0, 1, 0, 7, 0},
+		{"base", 8, 8, 7, 0, 0},
+		{"base.ID", 8, 8, 0, 0, 1},
+		{"Items", 16, 16, 0, 0, 0},
+		{"Items[0].X", 16, 4, 0, 0, 1},
+		{"Items[0].Y", 20, 1, 0, 3, 1},
+		{"Codes", 32, 3, 0, 1, 0},
+		{"Where", 36, 8, 1, 4, 0},
+		{"Where.X", 36, 4, 0, 0, 1},
+		{"Where.Y", 40, 1, 0, 3, 1},
+	}
+	if len(l.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d:\n%s", len(l.Fields), len(want), l.Table())
+	}
+	for i, w := range want {
+		f := l.Fields[i]
+		if f.Path != w.path || f.Offset != w.offset || f.Size != w.size ||
+			f.PaddingBefore != w.pre || f.PaddingAfter != w.post || f.Depth != w.depth {
+			t.Errorf("field %d = %+v, want %+v", i, f, w)
+		}
+	}
+	if !l.Fields[1].Embedded || l.Fields[3].Count != 2 {
+		t.Errorf("embedded = %t, count = %d", l.Fields[1].Embedded, l.Fields[3].Count)
+	}
+
+	// 7 after Flag, 3 in each of the three points, 1 after Codes, 4 at the end.
+	if l.Size != 48 || l.Padding != 21 {
+		t.Errorf("size, padding = %d, %d, want 48, 21", l.Size, l.Padding)
+	}
+
+	m := l.ByteMap()
+	for _, line := range []string{
+		"0000  a . . . . . . .",
+		"0010  c c c c d . . .",      // Items[0]
+		"... 1 more identical words", // Items[1] repeats the first element
+	} {
+		if !strings.Contains(m, line) {
+			t.Errorf("byte map lacks %q:\n%s", line, m)
+		}
+	}
+}
+
+func TestAnalyzeNotStruct(t *testing.T) {
+	if _, err := Analyze(reflect.TypeFor[int]()); err == nil {
+		t.Error("Analyze(int) succeeded")
+	}
+}
@@ -2,80 +2,73 @@ package main
 
 import (
 	"fmt"
-	"os/exec"
+	"io"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
-	"unsafe"
 
+	"day0/align"
+	"day0/analysis/checker"
+	"day0/analysis/unitchecker"
+	"day0/bench"
+	"day0/benchmarks"
+	"day0/copysize"
+	"day0/pool"
+	"day0/profiling"
+	"day0/report"
+	"day0/stats"
 	"day0/topics"
 )
 
 // =============================================================================
 // COMPREHENSIVE GO OPTIMIZATION DEMO
 // =============================================================================
-// This program demonstrates 11 key Go optimization topics:
-// (From goperf.dev/01-common-patterns/)
+// This program demonstrates Go optimization topics, most of them from
+// goperf.dev/01-common-patterns/: struct alignment, copying and escape
+// analysis, pooling, batching, caches and more. Run `day0 list` for the
+// current set; the registry, not this comment, is the source of truth.
 //
-// ORIGINAL 6 TOPICS:
-// 1. Struct Alignment - How field ordering affects memory usage and cache efficiency
-// 2. Pass by Value vs Pointer - Copy cost vs indirection overhead
-// 3. Receiver Types - Value vs pointer receivers for methods
-// 4. Return Value Optimization (RVO) - How Go optimizes return-by-value
-// 5. Slice Escape Analysis - When slices escape to heap vs stay on stack
-// 6. Stack vs Heap - Where Go allocates data and performance implications
-// 7. Object Pooling - Reusing objects to reduce GC pressure
-// 8. Batching Operations - Reducing overhead by grouping operations
-// 9. Immutable Data Sharing - Safe concurrent access without locks
-// 10. Lazy Initialization - Deferring expensive operations until needed
-// 11. Memory Preallocation - Preallocating slices and maps for performance
+// Topics register themselves in package topics (see topics/topic.go) and can
+// be listed, described, run or benchmarked one at a time; see cli.go.
 // =============================================================================
 
 func main() {
-	printHeader("GO PERFORMANCE OPTIMIZATION DEMONSTRATION")
-	fmt.Println()
-	fmt.Println("This demo covers 11 key optimization topics in Go:")
-	fmt.Println()
-	fmt.Println("ORIGINAL TOPICS:")
-	fmt.Println("  1. Struct Alignment & Memory Padding")
-	fmt.Println("  2. Pass by Value vs Pointer")
-	fmt.Println("  3. Receiver Types (Value vs Pointer)")
-	fmt.Println("  4. Return Value Optimization (RVO)")
-	fmt.Println("  5. Slice Escape Analysis")
-	fmt.Println("  6. Stack vs Heap Allocation")
-	fmt.Println("  7. Object Pooling")
-	fmt.Println("  8. Batching Operations")
-	fmt.Println("  9. Immutable Data Sharing")
-	fmt.Println(" 10. Lazy Initialization")
-	fmt.Println(" 11. Memory Preallocation")
-	fmt.Println()
-
-	// Run all demos
-	runAllDemos()
+	if unitchecker.IsInvocation(os.Args[1:]) {
+		os.Exit(unitchecker.Main(os.Args[1:], os.Stdout, os.Stderr, copysize.Analyzer))
+	}
+	code := runCLI(os.Args[1:], os.Stdout, os.Stderr)
+	if pool.Debug() {
+		// Every buffer the demos and benchmarks took should be back by now.
+		pool.WriteLeaks(os.Stderr)
+	}
+	os.Exit(code)
 }
 
 // =============================================================================
 // HELPER FUNCTIONS
 // =============================================================================
 
-func printHeader(title string) {
+func printHeader(w io.Writer, title string) {
 	const width = 80
 	padding := (width - len(title)) / 2
-	fmt.Println(strings.Repeat("=", width))
-	fmt.Printf("%s%s\n", strings.Repeat(" ", padding), title)
-	fmt.Println(strings.Repeat("=", width))
+	fmt.Fprintln(w, strings.Repeat("=", width))
+	fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", padding), title)
+	fmt.Fprintln(w, strings.Repeat("=", width))
 }
 
-func printSection(title string) {
+func printSection(w io.Writer, title string) {
 	const width = 80
 	padding := (width - len(title)) / 2
-	fmt.Println()
-	fmt.Println(strings.Repeat("-", width))
-	fmt.Printf("%s%s\n", strings.Repeat(" ", padding), title)
-	fmt.Println(strings.Repeat("-", width))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("-", width))
+	fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", padding), title)
+	fmt.Fprintln(w, strings.Repeat("-", width))
 }
 
-func printSubsection(title string) {
-	fmt.Println()
-	fmt.Println("### " + title)
+func printSubsection(w io.Writer, title string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "### "+title)
 }
 
 func formatBytes(bytes int64) string {
@@ -88,327 +81,611 @@ func formatBytes(bytes int64) string {
 	return fmt.Sprintf("%.2f MB", float64(bytes)/1024/1024)
 }
 
-func runAllDemos() {
-	// Demo 1: Struct Alignment
-	demoStructAlignment()
-
-	// Demo 2: Pass by Value vs Pointer
-	demoPassByValue()
-
-	// Demo 3: Receiver Types
-	demoReceiverTypes()
-
-	// Demo 4: Return Value Optimization
-	demoReturnOptimization()
-
-	// Demo 5: Slice Escape Analysis
-	demoSliceEscape()
-
-	// Demo 6: Stack vs Heap
-	demoStackVsHeap()
+func formatNs(ns float64) string {
+	if ns < 1e3 {
+		return fmt.Sprintf("%.2f ns", ns)
+	}
+	if ns < 1e6 {
+		return fmt.Sprintf("%.2f µs", ns/1e3)
+	}
+	return fmt.Sprintf("%.2f ms", ns/1e6)
+}
 
-	// Demo 7: Object Pooling
-	demoObjectPooling()
+// runTopics runs the demo of each topic, optionally followed by its benchmark
+// tables, and finishes with the headline takeaway of every topic that ran.
+func runTopics(out *report.Report, selected []topics.Topic, withBench bool) {
+	printHeader(out, "GO PERFORMANCE OPTIMIZATION DEMONSTRATION")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "This run covers %d of %d optimization topics in Go:\n", len(selected), len(topics.All()))
+	fmt.Fprintln(out)
+	for i, t := range selected {
+		fmt.Fprintf(out, "%3d. %s\n", i+1, t.Title())
+	}
+	fmt.Fprintln(out)
 
-	// Demo 8: Batching Operations
-	demoBatchingOperations()
+	for _, t := range selected {
+		out.SetTopic(t.ID())
+		t.Demo(out)
+		if withBench {
+			runBenchmarks(out, t)
+		}
+	}
 
-	// Demo 9: Immutable Data Sharing
-	demoImmutableDataSharing()
+	printHeader(out, "DEMONSTRATION COMPLETE")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Key Takeaways:")
+	for i, t := range selected {
+		fmt.Fprintf(out, "%3d. %s\n", i+1, t.Takeaways()[0])
+	}
+}
 
-	// Demo 10: Lazy Initialization
-	demoLazyInitialization()
+// describeTopic prints only the explanation of a topic, without running anything.
+func describeTopic(w io.Writer, t topics.Topic) {
+	printHeader(w, strings.ToUpper(t.Title()))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, t.Summary())
+	fmt.Fprintf(w, "ID: %s   Tags: %s\n", t.ID(), strings.Join(t.Tags(), ", "))
 
-	// Demo 11: Memory Preallocation
-	demoMemoryPreallocation()
+	printSubsection(w, "Key Points")
+	for _, point := range t.Takeaways() {
+		fmt.Fprintln(w, "  - "+point)
+	}
 
-	printHeader("DEMONSTRATION COMPLETE")
-	fmt.Println()
-	fmt.Println("Key Takeaways:")
-	fmt.Println("  1. Order struct fields largest to smallest to minimize padding")
-	fmt.Println("  2. Pass small structs by value, large structs by pointer")
-	fmt.Println("  3. Use pointer receivers for large types or when mutation is needed")
-	fmt.Println("  4. Return by value when possible - let RVO handle optimization")
-	fmt.Println("  5. Keep data local to avoid heap escape and GC pressure")
-	fmt.Println("  6. Stack allocation is faster but data must not outlive function")
-	fmt.Println("  7. Object pooling reduces GC pressure for high-frequency allocations")
-	fmt.Println("  8. Batching reduces overhead for I/O-bound operations")
-	fmt.Println("  9. Immutable data enables safe concurrent access without locks")
-	fmt.Println(" 10. Lazy initialization defers expensive operations until needed")
-	fmt.Println(" 11. Preallocate slices and maps when size is known")
+	printSubsection(w, "Benchmarks")
+	for _, g := range t.Benchmarks() {
+		fmt.Fprintf(w, "  %s:\n", g.Title)
+		for _, name := range g.Names {
+			fmt.Fprintln(w, "    "+name)
+		}
+	}
+	fmt.Fprintln(w)
 }
 
 // =============================================================================
-// DEMO 1: STRUCT ALIGNMENT
+// BENCHMARK RUNNER
 // =============================================================================
 
-func demoStructAlignment() {
-	topics.RunAlignmentDemo()
-}
+// benchEngine runs the benchmark functions in-process, so the demo works as a
+// single binary without a Go toolchain or the module sources on disk.
+var benchEngine bench.Engine
+
+// profiler, when set by --profile, captures a profile of every benchmark run
+// from the benchmark tables.
+var profiler *profiling.Profiler
+
+// runBenchmarks prints one results table per benchmark group of a topic and
+// adds a record per benchmark metric to out.
+func runBenchmarks(out *report.Report, t topics.Topic) {
+	groups := t.Benchmarks()
+	if len(groups) == 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "No benchmarks available for this topic")
+		return
+	}
 
-// =============================================================================
-// DEMO 2: PASS BY VALUE VS POINTER
-// =============================================================================
+	for _, g := range groups {
+		printSubsection(out, "Performance Benchmarks - "+g.Title)
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "%-45s | %20s | %10s | %10s\n", "Benchmark", "Time/op", "Bytes/op", "Allocs/op")
+		fmt.Fprintln(out, strings.Repeat("-", 94))
+
+		var profiles []*profiling.Summary
+		for _, name := range g.Names {
+			bm, ok := benchmarks.Lookup(name)
+			if !ok {
+				fmt.Fprintf(out, "%-45s | (not registered)\n", name)
+				continue
+			}
+			r, prof, err := runBenchmark(bm)
+			fmt.Fprintln(out, formatBenchmarkResult(r))
+			addBenchmarkRecords(out, g.Title, r)
+			if err != nil {
+				fmt.Fprintf(out, "%-45s | (profile failed: %v)\n", "", err)
+			}
+			if prof != nil {
+				profiles = append(profiles, prof)
+			}
+		}
 
-func demoPassByValue() {
-	printHeader("PASS BY VALUE VS POINTER")
-
-	fmt.Println()
-	fmt.Println("PASS BY VALUE:")
-	fmt.Println("  - Copies the entire struct onto the stack")
-	fmt.Println("  - No heap allocation needed (stack-to-stack copy is fast)")
-	fmt.Println("  - Good for small structs (< 2 words)")
-	fmt.Println()
-	fmt.Println("PASS BY POINTER:")
-	fmt.Println("  - Only copies the 8-byte pointer")
-	fmt.Println("  - Avoids copy overhead for large structs")
-	fmt.Println("  - Adds slight indirection cost")
-	fmt.Println()
-
-	// Show LargeStruct size
-	structSize := int(unsafe.Sizeof(topics.LargeStruct{}))
-	printSubsection("LargeStruct Size")
-	fmt.Printf("LargeStruct: %d bytes (%s)\n", structSize, formatBytes(int64(structSize)))
-	fmt.Println("This is large enough to show significant copy overhead!")
-
-	// Run benchmarks
-	printSubsection("Performance Benchmarks")
-	runBenchmarks("PassByValue")
-
-	// When to use each
-	printSubsection("When to Use Each")
-	fmt.Println("PASS BY VALUE when:")
-	fmt.Println("  - Struct is small (< 16 bytes / 2 words)")
-	fmt.Println("  - You need thread-safety (no aliasing)")
-	fmt.Println("  - Data is read-only")
-	fmt.Println()
-	fmt.Println("PASS BY POINTER when:")
-	fmt.Println("  - Struct is large (> 100 bytes)")
-	fmt.Println("  - You need to modify the original")
-	fmt.Println("  - Performance is critical in hot paths")
+		for _, prof := range profiles {
+			printProfile(out, prof)
+		}
+	}
 }
 
-// =============================================================================
-// DEMO 3: RECEIVER TYPES
-// =============================================================================
-
-func demoReceiverTypes() {
-	printHeader("RECEIVER TYPES (VALUE VS POINTER)")
-
-	fmt.Println()
-	fmt.Println("VALUE RECEIVER:")
-	fmt.Println("  - Go makes a COPY of the struct")
-	fmt.Println("  - Changes don't affect the original")
-	fmt.Println("  - Good for small, read-only operations")
-	fmt.Println()
-	fmt.Println("POINTER RECEIVER:")
-	fmt.Println("  - Go passes a pointer to the original")
-	fmt.Println("  - Changes persist")
-	fmt.Println("  - No copy overhead - efficient for large types")
-	fmt.Println()
-
-	// Show sizes
-	printSubsection("Receiver Type Impact")
-	counterSize := int(unsafe.Sizeof(topics.Counter{}))
-	processorSize := int(unsafe.Sizeof(topics.DataProcessor{}))
-	fmt.Printf("Counter: %d bytes (copy cost negligible)\n", counterSize)
-	fmt.Printf("DataProcessor: %d bytes (copy cost significant!)\n", processorSize)
-
-	// Run benchmarks - small struct
-	printSubsection("Performance Benchmarks - Small Struct (Counter)")
-	runBenchmarks("ReceiverSmall")
-
-	// Run benchmarks - large struct
-	printSubsection("Performance Benchmarks - Large Struct (DataProcessor)")
-	runBenchmarks("ReceiverLarge")
-
-	// Guidelines
-	printSubsection("Guidelines")
-	fmt.Println("Use VALUE RECEIVER when:")
-	fmt.Println("  - Struct is small (< 16 bytes)")
-	fmt.Println("  - You don't need to modify the original")
-	fmt.Println("  - Thread-safety is important (no aliasing)")
-	fmt.Println()
-	fmt.Println("Use POINTER RECEIVER when:")
-	fmt.Println("  - Struct is large (> 100 bytes)")
-	fmt.Println("  - You need to modify the original")
-	fmt.Println("  - Method must satisfy an interface that requires pointers")
+// runBenchmark runs a benchmark, under the profiler when one is set.
+// A profiling error leaves the result intact.
+func runBenchmark(bm bench.Benchmark) (bench.Result, *profiling.Summary, error) {
+	if profiler == nil {
+		return benchEngine.Run(bm.Name, bm.F), nil, nil
+	}
+	var r bench.Result
+	prof, err := profiler.Capture(bm.Name, func() {
+		r = benchEngine.Run(bm.Name, bm.F)
+	})
+	return r, prof, err
 }
 
-// =============================================================================
-// DEMO 4: RETURN VALUE OPTIMIZATION
-// =============================================================================
+// printProfile prints the top functions of a benchmark profile, like
+// `go tool pprof -top`, and adds them to out as records.
+func printProfile(out *report.Report, prof *profiling.Summary) {
+	printSubsection(out, fmt.Sprintf("Profile - %s (%s, total %s)", prof.Name, prof.Kind, formatProfileValue(prof.Total, prof.Unit)))
+	fmt.Fprintf(out, "go tool pprof %s\n", prof.File)
+	fmt.Fprintln(out)
+	if len(prof.Top) == 0 {
+		fmt.Fprintln(out, "No samples - the benchmark ran too briefly or never hit this profile.")
+		return
+	}
 
-func demoReturnOptimization() {
-	topics.RunReturnOptimizationDemo()
-}
+	fmt.Fprintf(out, "%12s %7s %12s %7s  %s\n", "Flat", "Flat%", "Cum", "Cum%", "Function")
+	for i, e := range prof.Top {
+		fmt.Fprintf(out, "%12s %6.1f%% %12s %6.1f%%  %s\n",
+			formatProfileValue(e.Flat, prof.Unit), percentOf(e.Flat, prof.Total),
+			formatProfileValue(e.Cum, prof.Unit), percentOf(e.Cum, prof.Total), e.Function)
 
-// =============================================================================
-// DEMO 5: SLICE ESCAPE ANALYSIS
-// =============================================================================
+		params := []string{"benchmark", prof.Name, "function", e.Function, "profile", string(prof.Kind), "rank", strconv.Itoa(i + 1)}
+		out.Add("profile_flat", float64(e.Flat), prof.Unit, params...)
+		out.Add("profile_cum", float64(e.Cum), prof.Unit, params...)
+	}
+}
 
-func demoSliceEscape() {
-	topics.RunSliceEscapeDemo()
+func formatProfileValue(v int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		return formatNs(float64(v))
+	case "bytes":
+		return formatBytes(v)
+	}
+	return strconv.FormatInt(v, 10)
 }
 
-// =============================================================================
-// DEMO 6: STACK VS HEAP
-// =============================================================================
+func percentOf(v, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(v) / float64(total) * 100
+}
 
-func demoStackVsHeap() {
-	topics.RunStackVsHeapDemo()
+func formatBenchmarkResult(r bench.Result) string {
+	if r.Failed() {
+		return fmt.Sprintf("%-45s | (failed)", r.Name)
+	}
+	return fmt.Sprintf("%-45s | %20s | %10s | %10d",
+		r.Name, formatTime(r), formatBytes(r.BytesPerOp), r.AllocsPerOp)
 }
 
-// =============================================================================
-// DEMO 7: OBJECT POOLING
-// =============================================================================
+// formatTime formats the time per operation, followed by the 95% confidence
+// interval in the style of benchstat when the benchmark ran more than once.
+func formatTime(r bench.Result) string {
+	if len(r.Samples) < 2 {
+		return formatNs(r.NsPerOp)
+	}
+	return fmt.Sprintf("%s ± %.0f%%", formatNs(r.NsPerOp), r.Time().Spread())
+}
 
-func demoObjectPooling() {
-	topics.RunPoolingDemo()
+// addBenchmarkRecords adds one record per metric of a benchmark result, using
+// the units of `go test -bench` so the values can be compared with its output.
+// Failed benchmarks produce no records.
+func addBenchmarkRecords(out *report.Report, group string, r bench.Result) {
+	if r.Failed() {
+		return
+	}
+	params := []string{"benchmark", r.Name, "group", group, "iterations", strconv.Itoa(r.N)}
+	out.Add("time", r.NsPerOp, "ns/op", params...)
+	if len(r.Samples) > 1 {
+		t := r.Time()
+		params = append(params, "samples", strconv.Itoa(len(r.Samples)), "outliers", strconv.Itoa(len(t.Outliers)))
+		out.Add("time_mean", t.Mean, "ns/op", params...)
+		out.Add("time_stddev", t.Stddev, "ns/op", params...)
+		out.Add("time_ci95_low", t.CILow, "ns/op", params...)
+		out.Add("time_ci95_high", t.CIHigh, "ns/op", params...)
+	}
+	out.Add("memory", float64(r.BytesPerOp), "B/op", params...)
+	out.Add("allocations", float64(r.AllocsPerOp), "allocs/op", params...)
+	for _, unit := range r.MetricUnits() {
+		out.Add(unit, r.Metrics[unit], unit, params...)
+	}
 }
 
 // =============================================================================
-// DEMO 8: BATCHING OPERATIONS
+// BASELINE COMPARISON
 // =============================================================================
 
-func demoBatchingOperations() {
-	topics.RunBatchingDemo()
+// saveBaseline runs every registered benchmark and writes the results to path.
+// Each result is printed as it completes, in `go test -bench` format.
+func saveBaseline(w io.Writer, path string) error {
+	var results []bench.Result
+	for _, bm := range benchmarks.All() {
+		r := benchEngine.Run(bm.Name, bm.F)
+		fmt.Fprintln(w, r)
+		results = append(results, r)
+	}
+
+	b := bench.NewBaseline(benchEngine.BenchTime, results)
+	if err := b.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nSaved %d results (%s) to %s\n", len(results), b.Environment(), path)
+	return nil
 }
 
-// =============================================================================
-// DEMO 9: IMMUTABLE DATA SHARING
-// =============================================================================
+// compareBaseline runs every registered benchmark, compares the results with
+// the baseline at path and returns the comparisons that regressed.
+func compareBaseline(out *report.Report, path string, threshold float64, allocThreshold int64) ([]bench.Comparison, error) {
+	old, err := bench.LoadBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+	results := benchEngine.RunAll(benchmarks.All())
+	current := bench.NewBaseline(benchEngine.BenchTime, results)
+
+	printHeader(out, "BASELINE COMPARISON")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Baseline: %s (%s)\n", old.Environment(), path)
+	fmt.Fprintf(out, "Current:  %s\n", current.Environment())
+	fmt.Fprintf(out, "Changes within ±%.1f%% are reported as ~\n", threshold)
+	fmt.Fprintf(out, "With at least %d runs on both sides (-count), changes that are not significant\n", stats.MinSamples)
+	fmt.Fprintf(out, "(Mann-Whitney U, p >= %.2f) are reported as ~ as well\n", stats.Alpha)
+	fmt.Fprintf(out, "Allocs/op are the fewest of any run; up to %d more per op are tolerated\n", allocThreshold)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-45s | %20s | %20s | %8s | %10s | %s\n",
+		"Benchmark", "Old time/op", "New time/op", "Delta", "Allocs/op", "Verdict")
+	fmt.Fprintln(out, strings.Repeat("-", 130))
+
+	owners := benchmarkOwners()
+	var regressed []bench.Comparison
+	for _, c := range bench.Compare(old.Results, results, threshold, allocThreshold) {
+		fmt.Fprintln(out, formatComparison(c))
+		out.SetTopic(owners[c.Name])
+		addComparisonRecord(out, c)
+		if c.Regressed() {
+			regressed = append(regressed, c)
+		}
+	}
 
-func demoImmutableDataSharing() {
-	topics.RunImmutableDemo()
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d benchmarks compared, %d regressed\n", len(results), len(regressed))
+	return regressed, nil
 }
 
-// =============================================================================
-// DEMO 10: LAZY INITIALIZATION
-// =============================================================================
-
-func demoLazyInitialization() {
-	topics.RunLazyInitDemo()
+// benchmarkOwners maps each benchmark name to the ID of the topic listing it.
+func benchmarkOwners() map[string]string {
+	owners := make(map[string]string)
+	for _, t := range topics.All() {
+		for _, g := range t.Benchmarks() {
+			for _, name := range g.Names {
+				owners[name] = t.ID()
+			}
+		}
+	}
+	return owners
 }
 
-// =============================================================================
-// DEMO 11: MEMORY PREALLOCATION
-// =============================================================================
+func formatComparison(c bench.Comparison) string {
+	switch c.Verdict {
+	case bench.Added:
+		return fmt.Sprintf("%-45s | %20s | %20s | %8s | %10s | %s", c.Name, "-", formatTime(c.New), "", "", c.Verdict)
+	case bench.Removed:
+		return fmt.Sprintf("%-45s | %20s | %20s | %8s | %10s | %s", c.Name, formatTime(c.Old), "-", "", "", c.Verdict)
+	case bench.Broken:
+		return fmt.Sprintf("%-45s | %20s | %20s | %8s | %10s | %s", c.Name, formatTime(c.Old), "(failed)", "", "", c.Verdict)
+	}
 
-func demoMemoryPreallocation() {
-	topics.RunMemoryPreallocationDemo()
+	allocs := strconv.FormatInt(c.New.MinAllocs(), 10)
+	if c.AllocsDelta != 0 {
+		allocs = fmt.Sprintf("%d → %d", c.Old.MinAllocs(), c.New.MinAllocs())
+	}
+	verdict := string(c.Verdict)
+	if c.MoreAllocs {
+		verdict += ", more allocs"
+	}
+	if c.Tested {
+		verdict += fmt.Sprintf(" (p=%.3f n=%d+%d)", c.P, len(c.Old.Samples), len(c.New.Samples))
+	}
+	return fmt.Sprintf("%-45s | %20s | %20s | %+7.1f%% | %10s | %s",
+		c.Name, formatTime(c.Old), formatTime(c.New), c.Percent, allocs, verdict)
+}
+
+// addComparisonRecord adds the relative time change of a benchmark to out.
+// Benchmarks present in only one run have no change and produce no record.
+func addComparisonRecord(out *report.Report, c bench.Comparison) {
+	if c.Verdict == bench.Added || c.Verdict == bench.Removed || c.Verdict == bench.Broken {
+		return
+	}
+	var test []string
+	if c.Tested {
+		test = []string{"p_value", strconv.FormatFloat(c.P, 'g', 4, 64)}
+	}
+	out.Add("time_change", c.Percent, "%", append(test,
+		"benchmark", c.Name,
+		"verdict", string(c.Verdict),
+		"old_ns_per_op", strconv.FormatFloat(c.Old.NsPerOp, 'g', -1, 64),
+		"new_ns_per_op", strconv.FormatFloat(c.New.NsPerOp, 'g', -1, 64),
+		"allocs_delta", strconv.FormatInt(c.AllocsDelta, 10))...)
 }
 
 // =============================================================================
-// BENCHMARK RUNNER
+// FIELD ORDERING
 // =============================================================================
 
-func runBenchmarks(category string) {
-	fmt.Println()
+// printAlignment prints the structs that could shrink (every struct with all)
+// with their optimal and, when they have hot fields, cache-friendly ordering.
+func printAlignment(out *report.Report, sugs []align.Suggestion, arch string, all bool) {
+	printHeader(out, "FIELD ORDERING ("+arch+")")
+	fmt.Fprintln(out)
+
+	var shrink int
+	var saved int64
+	for _, s := range sugs {
+		if s.CanShrink() {
+			shrink++
+			saved += s.Saved()
+		} else if !all && len(s.Hot) == 0 {
+			continue
+		}
 
-	// Define benchmark patterns for each category
-	benchmarks := getBenchmarksForCategory(category)
+		fmt.Fprintf(out, "%s:%d: %s: %d → %d bytes", s.Pos.Filename, s.Pos.Line, s.Name, s.Size, s.OptimalSize)
+		if s.CanShrink() {
+			fmt.Fprintf(out, " (saves %d)", s.Saved())
+		}
+		fmt.Fprintln(out)
+		if s.CanShrink() {
+			fmt.Fprintf(out, "    current: %s\n", strings.Join(s.Current, ", "))
+			fmt.Fprintf(out, "    optimal: %s\n", strings.Join(s.Optimal, ", "))
+		}
+		if len(s.Hot) > 0 {
+			fmt.Fprintf(out, "    cache:   %s (%d bytes; hot %s in the first %d bytes, %d cache line(s))\n",
+				strings.Join(s.Cache, ", "), s.CacheSize, strings.Join(s.Hot, ", "),
+				s.HotSpan, (s.HotSpan+align.CacheLine-1)/align.CacheLine)
+		}
 
-	if len(benchmarks) == 0 {
-		fmt.Println("No benchmarks available for this category")
-		return
+		params := []string{"type", s.Name, "file", s.Pos.Filename, "arch", arch}
+		out.Add("struct_size", float64(s.Size), "bytes", params...)
+		out.Add("struct_size_optimal", float64(s.OptimalSize), "bytes", params...)
+		out.Add("bytes_saved", float64(s.Saved()), "bytes", params...)
+		if len(s.Hot) > 0 {
+			out.Add("hot_span", float64(s.HotSpan), "bytes", params...)
+		}
 	}
 
-	fmt.Printf("%-45s | %12s | %10s\n", "Benchmark", "Time/op", "Allocations")
-	fmt.Println(strings.Repeat("-", 75))
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d of %d structs can shrink, saving %d bytes per instance in total\n", shrink, len(sugs), saved)
+}
 
-	// Run benchmarks using go test
-	for _, bm := range benchmarks {
-		result := runGoTestBenchmark(bm)
-		if result != "" {
-			fmt.Println(result)
-		}
+// printArchSizes prints the current and optimal size of the structs on each
+// architecture. Without all only the structs that can shrink somewhere are
+// listed.
+func printArchSizes(out *report.Report, sugs []align.Suggestion, archs []string, all bool) {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Current → optimal size per architecture:")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-30s", "Type")
+	for _, arch := range archs {
+		fmt.Fprintf(out, " | %-10s", arch)
 	}
-}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, strings.Repeat("-", 30+13*len(archs)))
 
-func getBenchmarksForCategory(category string) []string {
-	switch category {
-	case "Alignment":
-		return []string{
-			"BenchmarkProcessUnaligned",
-			"BenchmarkProcessAligned",
-			"BenchmarkProcessUnalignedPtr",
-			"BenchmarkProcessAlignedPtr",
-			"BenchmarkMixedTypesAligned",
-			"BenchmarkMixedTypesUnaligned",
-		}
-	case "PassByValue":
-		return []string{
-			"BenchmarkAddByValue",
-			"BenchmarkAddByPointer",
+	for _, s := range sugs {
+		if !all && !slices.ContainsFunc(s.Archs, func(a align.ArchSize) bool { return a.OptimalSize < a.Size }) {
+			continue
 		}
-	case "ReceiverSmall":
-		return []string{
-			"BenchmarkIncrementByValue",
-			"BenchmarkIncrementByPointer",
+		fmt.Fprintf(out, "%-30s", s.Name)
+		for _, a := range s.Archs {
+			cell := strconv.FormatInt(a.Size, 10)
+			if a.OptimalSize < a.Size {
+				cell += fmt.Sprintf(" → %d", a.OptimalSize)
+			}
+			fmt.Fprintf(out, " | %-10s", cell)
+			params := []string{"type", s.Name, "file", s.Pos.Filename, "arch", a.Arch}
+			out.Add("struct_size", float64(a.Size), "bytes", params...)
+			out.Add("struct_size_optimal", float64(a.OptimalSize), "bytes", params...)
 		}
-	case "ReceiverLarge":
-		return []string{
-			"BenchmarkProcessByValue",
-			"BenchmarkProcessByPointer",
+		fmt.Fprintln(out)
+	}
+}
+
+// =============================================================================
+// GC SWEEP
+// =============================================================================
+
+// gcSweepDefaults are the benchmarks gcsweep runs without arguments: the
+// allocation-heavy ones whose results depend most on GC tuning, and the
+// pooled counterpart of the first.
+var gcSweepDefaults = []string{
+	"BenchmarkWithoutPoolLarge",
+	"BenchmarkWithPoolLarge",
+	"BenchmarkDynamicMapLarge",
+	"BenchmarkCreateLargeStructOnHeap",
+}
+
+// printGCSweep runs the benchmarks under every combination of GC percent and
+// memory limit and prints a matrix of throughput and GC cycles per benchmark,
+// then the speedup of every BenchmarkWithX over a selected BenchmarkWithoutX.
+//
+// GOGC=off without a memory limit is skipped: nothing would ever collect the
+// heap of an allocating benchmark.
+func printGCSweep(out *report.Report, selected []bench.Benchmark, percents []int, limits []int64) {
+	var grid []bench.GCSetting
+	for _, s := range bench.Grid(percents, limits) {
+		if s.Percent != bench.GCOff || s.MemoryLimit != bench.NoMemoryLimit {
+			grid = append(grid, s)
 		}
-	case "Return":
-		return []string{
-			"BenchmarkReturnAddByValue",
-			"BenchmarkReturnAddByPointer",
+	}
+	results := benchEngine.Sweep(selected, grid)
+	lookup := func(i int, p int, l int64) (bench.Result, bool) {
+		j := slices.Index(grid, bench.GCSetting{Percent: p, MemoryLimit: l})
+		if j < 0 {
+			return bench.Result{}, false
 		}
-	case "SliceEscape":
-		return []string{
-			"BenchmarkProcessSliceWithEscape",
-			"BenchmarkProcessSliceNoEscape",
+		return results[i][j], true
+	}
+
+	printHeader(out, "GOGC AND GOMEMLIMIT SWEEP")
+	fmt.Fprintln(out, "Throughput in operations per second, GC cycles per 1000 operations in")
+	fmt.Fprintln(out, "parentheses. Rows are GOGC values, columns GOMEMLIMIT values.")
+	for i, bm := range selected {
+		printSubsection(out, bm.Name)
+		fmt.Fprintln(out)
+		printGCMatrix(out, percents, limits, func(p int, l int64) string {
+			r, ok := lookup(i, p, l)
+			switch {
+			case !ok:
+				return "skipped"
+			case r.Failed():
+				return "FAILED"
+			}
+			opsPerSec, cycles := 1e9/r.NsPerOp, r.Metrics[bench.GCCyclesUnit]
+			params := []string{"benchmark", bm.Name, "gogc", bench.FormatPercent(p), "gomemlimit", bench.FormatMemoryLimit(l)}
+			out.Add("gc_sweep_throughput", opsPerSec, "op/s", params...)
+			out.Add("gc_sweep_cycles", cycles, bench.GCCyclesUnit, params...)
+			return fmt.Sprintf("%s (%.1f)", formatOpsPerSec(opsPerSec), cycles)
+		})
+	}
+
+	for i, without := range selected {
+		name, ok := strings.CutPrefix(without.Name, "BenchmarkWithout")
+		if !ok {
+			continue
 		}
-	case "StackHeap":
-		return []string{
-			"BenchmarkCreateLargeStructOnStack",
-			"BenchmarkCreateLargeStructOnHeap",
+		k := slices.IndexFunc(selected, func(bm bench.Benchmark) bool { return bm.Name == "BenchmarkWith"+name })
+		if k < 0 {
+			continue
 		}
+		printSubsection(out, fmt.Sprintf("Speedup of %s over %s", selected[k].Name, without.Name))
+		fmt.Fprintln(out)
+		printGCMatrix(out, percents, limits, func(p int, l int64) string {
+			a, okA := lookup(i, p, l)
+			b, okB := lookup(k, p, l)
+			if !okA || !okB || a.Failed() || b.Failed() {
+				return "-"
+			}
+			speedup := a.NsPerOp / b.NsPerOp
+			out.Add("gc_sweep_speedup", speedup, "x", "benchmark", selected[k].Name, "baseline", without.Name,
+				"gogc", bench.FormatPercent(p), "gomemlimit", bench.FormatMemoryLimit(l))
+			return fmt.Sprintf("%.1fx", speedup)
+		})
+		fmt.Fprintln(out)
+		printGCSweepFindings(out, without.Name, percents, limits, func(p int, l int64) (float64, float64, bool) {
+			a, okA := lookup(i, p, l)
+			b, okB := lookup(k, p, l)
+			if !okA || !okB || a.Failed() || b.Failed() {
+				return 0, 0, false
+			}
+			return a.NsPerOp / b.NsPerOp, a.Metrics[bench.GCCyclesUnit], true
+		})
 	}
-	return []string{}
 }
 
-func runGoTestBenchmark(benchmarkName string) string {
-	// Run the benchmark using go test from the benchmarks directory
-	cmd := exec.Command("go", "test", "-bench="+benchmarkName, "-benchmem", "-run=^$", "-count=1", "./benchmarks")
-	cmd.Dir = "."
+// gcSweepTolerance is the relative change below which gcsweep calls two of
+// its cells the same: each is a single run.
+const gcSweepTolerance = 0.1
+
+// changed returns 1 if to is more than gcSweepTolerance above from, -1 if
+// it is that far below, and 0 otherwise.
+func changed(from, to float64) int {
+	switch {
+	case to > from*(1+gcSweepTolerance):
+		return 1
+	case to < from*(1-gcSweepTolerance):
+		return -1
+	}
+	return 0
+}
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// Try running with different approach
-		cmd := exec.Command("go", "test", "-bench="+benchmarkName, "-benchmem", "-run=^$", "./benchmarks")
-		output, err = cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Sprintf("%-45s | Error: %v", benchmarkName, err)
+// printGCSweepFindings says what a speedup matrix shows: how the speedup
+// changed from the lowest to the highest GOGC, and how each memory limit
+// changed the GC cycles of the baseline at the highest GOGC. cell returns the
+// speedup and the baseline's GC cycles at a setting, if both ran.
+func printGCSweepFindings(out *report.Report, baseline string, percents []int, limits []int64,
+	cell func(p int, l int64) (speedup, cycles float64, ok bool)) {
+	column := limits[0]
+	if slices.Contains(limits, bench.NoMemoryLimit) {
+		column = bench.NoMemoryLimit
+	}
+	var ran []int // the GOGC values other than off with a cell in column, ascending
+	for _, p := range percents {
+		if _, _, ok := cell(p, column); ok && p != bench.GCOff {
+			ran = append(ran, p)
 		}
 	}
+	slices.Sort(ran)
+	if len(ran) == 0 {
+		return
+	}
 
-	// Parse the output
-	lines := strings.SplitSeq(string(output), "\n")
-	for line := range lines {
-		if strings.HasPrefix(line, benchmarkName) {
-			// Parse benchmark output: BenchmarkName	N	ns/op	A	bytes/op	B	allocs/op
-			parts := strings.Fields(line)
-			if len(parts) >= 4 {
-				timeOp := "N/A"
-				allocs := "N/A"
-
-				for i, part := range parts {
-					if part == "ns/op" && i > 0 {
-						timeOp = parts[i-1]
-					}
-					if part == "allocs/op" && i > 0 {
-						allocs = parts[i-1]
-					}
-				}
-
-				return fmt.Sprintf("%-45s | %12s | %10s", benchmarkName, timeOp, allocs)
+	if low, high := ran[0], ran[len(ran)-1]; low != high {
+		from, _, _ := cell(low, column)
+		to, _, _ := cell(high, column)
+		verb := [...]string{"shrank", "stayed about the same", "grew"}[changed(from, to)+1]
+		fmt.Fprintf(out, "With GOMEMLIMIT=%s, the speedup %s from GOGC=%d to GOGC=%d (%.1fx to %.1fx).\n",
+			bench.FormatMemoryLimit(column), verb, low, high, from, to)
+	}
+	if column == bench.NoMemoryLimit {
+		p := ran[len(ran)-1]
+		_, unlimited, _ := cell(p, column)
+		for _, l := range limits {
+			_, cycles, ok := cell(p, l)
+			if l == column || !ok {
+				continue
 			}
+			verb := [...]string{"lowered", "barely changed", "raised"}[changed(unlimited, cycles)+1]
+			fmt.Fprintf(out, "At GOGC=%d, GOMEMLIMIT=%s %s the GC cycles of %s (%.1f to %.1f per 1000 operations).\n",
+				p, bench.FormatMemoryLimit(l), verb, baseline, unlimited, cycles)
 		}
 	}
+	fmt.Fprintf(out, "Each cell is one run, so changes under %.0f%% are called the same.\n", 100*gcSweepTolerance)
+}
+
+// printGCMatrix prints one cell per GC percent and memory limit.
+func printGCMatrix(out *report.Report, percents []int, limits []int64, cell func(p int, l int64) string) {
+	fmt.Fprintf(out, "%-17s", "GOGC \\ GOMEMLIMIT")
+	for _, l := range limits {
+		fmt.Fprintf(out, " | %18s", bench.FormatMemoryLimit(l))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, strings.Repeat("-", 17+21*len(limits)))
+	for _, p := range percents {
+		fmt.Fprintf(out, "%-17s", bench.FormatPercent(p))
+		for _, l := range limits {
+			fmt.Fprintf(out, " | %18s", cell(p, l))
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// formatOpsPerSec formats a throughput with a k or M suffix, e.g. "253.1k/s".
+func formatOpsPerSec(v float64) string {
+	switch {
+	case v >= 1e6:
+		return fmt.Sprintf("%.1fM/s", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fk/s", v/1e3)
+	}
+	return fmt.Sprintf("%.0f/s", v)
+}
+
+// =============================================================================
+// OVERSIZED COPIES
+// =============================================================================
 
-	return fmt.Sprintf("%-45s | (no output)", benchmarkName)
+// printCopies prints the findings of the copysize analyzer and records how
+// many there are of each category.
+func printCopies(out *report.Report, findings []checker.Finding) {
+	printHeader(out, "OVERSIZED COPIES")
+	fmt.Fprintln(out)
+
+	counts := map[string]int{}
+	for _, f := range findings {
+		fmt.Fprintln(out, f)
+		counts[f.Category]++
+	}
+	if len(findings) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, c := range []string{"receiver", "parameter", "range", "call"} {
+		out.Add("oversized_copies", float64(counts[c]), "count", "category", c)
+	}
+	fmt.Fprintf(out, "%d receivers, %d parameters and %d range variables copy more than %s bytes; %d calls pay for it\n",
+		counts["receiver"], counts["parameter"], counts["range"], copysize.Analyzer.Flags.Lookup("threshold").Value, counts["call"])
 }
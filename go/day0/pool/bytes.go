@@ -0,0 +1,140 @@
+package pool
+
+import (
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+)
+
+// BytePool pools byte slices of varying length in power-of-two size classes,
+// like the buffer pools of net/http and fasthttp: Get rounds the requested
+// length up to the next class, so a 3000-byte request is served by the
+// 4 KB class and wastes at most half of the buffer.
+//
+// Requests larger than the largest class are allocated and never pooled:
+// keeping a rare 10 MB buffer alive for the next user pins memory that the
+// GC would otherwise reclaim.
+//
+// A BytePool is safe for concurrent use.
+type BytePool struct {
+	minShift, maxShift int
+	classes            []*Pool[*byte]
+
+	oversized, rejected atomic.Int64
+}
+
+// NewBytePool returns a pool with size classes from minSize to maxSize, both
+// powers of two.
+func NewBytePool(minSize, maxSize int) *BytePool {
+	if minSize <= 0 || minSize&(minSize-1) != 0 || maxSize&(maxSize-1) != 0 || minSize > maxSize {
+		panic(fmt.Sprintf("pool: size classes %d to %d are not powers of two in order", minSize, maxSize))
+	}
+	p := &BytePool{
+		minShift: bits.TrailingZeros(uint(minSize)),
+		maxShift: bits.TrailingZeros(uint(maxSize)),
+	}
+	for shift := p.minShift; shift <= p.maxShift; shift++ {
+		size := 1 << shift
+		// The pool stores a pointer to the first byte rather than the slice:
+		// a []byte in an interface allocates its header on every Put.
+		p.classes = append(p.classes, New(func() *byte {
+			return unsafe.SliceData(make([]byte, size))
+		}, Options[*byte]{
+			Poison:   func(b *byte) { PoisonBytes(unsafe.Slice(b, size)) },
+			Poisoned: func(b *byte) bool { return IsPoisoned(unsafe.Slice(b, size)) },
+		}))
+	}
+	return p
+}
+
+// MaxSize is the size of the largest class.
+func (p *BytePool) MaxSize() int {
+	return 1 << p.maxShift
+}
+
+// class returns the index of the smallest class that holds n bytes, or -1 if
+// n is larger than the largest class.
+func (p *BytePool) class(n int) int {
+	if n <= 1<<p.minShift {
+		return 0
+	}
+	if n > p.MaxSize() {
+		return -1
+	}
+	return bits.Len(uint(n-1)) - p.minShift
+}
+
+// Get returns a slice of length n. Its capacity is the size of n's class; its
+// contents are whatever the previous user left in it.
+func (p *BytePool) Get(n int) []byte {
+	c := p.class(n)
+	if c < 0 {
+		p.oversized.Add(1)
+		return make([]byte, n)
+	}
+	return unsafe.Slice(p.classes[c].Get(), 1<<(p.minShift+c))[:n]
+}
+
+// Put returns b to the largest class its capacity fills, so a buffer that
+// grew by append is still reused. Buffers smaller than the smallest class or
+// larger than the largest are dropped. b must not be used after Put.
+func (p *BytePool) Put(b []byte) {
+	if cap(b) < 1<<p.minShift || cap(b) > p.MaxSize() {
+		p.rejected.Add(1)
+		return
+	}
+	p.classes[bits.Len(uint(cap(b)))-1-p.minShift].Put(unsafe.SliceData(b[:1]))
+}
+
+// Stats returns the counters of every class and of the requests no class
+// could serve.
+func (p *BytePool) Stats() ByteStats {
+	s := ByteStats{
+		Classes:   make([]ClassStats, len(p.classes)),
+		Oversized: p.oversized.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+	for i, c := range p.classes {
+		s.Classes[i] = ClassStats{Size: 1 << (p.minShift + i), Stats: c.Stats()}
+	}
+	return s
+}
+
+// ClassStats counts the traffic of one size class.
+type ClassStats struct {
+	Size int // capacity of the buffers in the class
+	Stats
+}
+
+// ByteStats counts the traffic of a BytePool.
+type ByteStats struct {
+	Classes   []ClassStats
+	Oversized int64 // Gets larger than the largest class, allocated unpooled
+	Rejected  int64 // Puts dropped because no class matches their capacity
+}
+
+// Total sums the counters of all classes.
+func (s ByteStats) Total() Stats {
+	var t Stats
+	for _, c := range s.Classes {
+		t.Gets += c.Gets
+		t.Puts += c.Puts
+		t.News += c.News
+		t.Dropped += c.Dropped
+	}
+	return t
+}
+
+// Sub returns the traffic between an earlier snapshot of the same pool and s.
+func (s ByteStats) Sub(earlier ByteStats) ByteStats {
+	d := ByteStats{
+		Classes:   make([]ClassStats, len(s.Classes)),
+		Oversized: s.Oversized - earlier.Oversized,
+		Rejected:  s.Rejected - earlier.Rejected,
+	}
+	for i, c := range s.Classes {
+		d.Classes[i] = ClassStats{Size: c.Size, Stats: c.Stats.Sub(earlier.Classes[i].Stats)}
+	}
+	return d
+}
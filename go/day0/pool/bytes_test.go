@@ -0,0 +1,81 @@
+package pool
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestBytePoolClasses(t *testing.T) {
+	p := NewBytePool(64, 4096)
+	for _, tc := range []struct {
+		n, cap int
+	}{
+		{0, 64}, {1, 64}, {64, 64}, {65, 128}, {3000, 4096}, {4096, 4096}, {4097, 4097},
+	} {
+		b := p.Get(tc.n)
+		if len(b) != tc.n || cap(b) != tc.cap {
+			t.Errorf("Get(%d): len %d, cap %d; want cap %d", tc.n, len(b), cap(b), tc.cap)
+		}
+		p.Put(b)
+	}
+
+	s := p.Stats()
+	if len(s.Classes) != 7 || s.Classes[0].Size != 64 || s.Classes[6].Size != 4096 {
+		t.Fatalf("Classes = %+v, want 64 to 4096 bytes", s.Classes)
+	}
+	if s.Classes[0].Gets != 3 || s.Classes[1].Gets != 1 || s.Classes[6].Gets != 2 {
+		t.Errorf("Classes = %+v, want 3 gets of 64, 1 of 128 and 2 of 4096", s.Classes)
+	}
+	if s.Oversized != 1 || s.Rejected != 1 {
+		t.Errorf("Oversized = %d, Rejected = %d, want 1 and 1", s.Oversized, s.Rejected)
+	}
+	if total := s.Total(); total.Gets != 6 || total.Puts != 6 {
+		t.Errorf("Total = %+v, want 6 gets and 6 puts", total)
+	}
+}
+
+func TestBytePoolReuse(t *testing.T) {
+	defer func(percent int) { runtime.GC(); debug.SetGCPercent(percent) }(debug.SetGCPercent(-1))
+
+	p := NewBytePool(64, 4096)
+	before := p.Stats()
+	b := p.Get(100)
+	b[0] = 42
+	p.Put(b)
+	again := p.Get(120)
+	if cap(again) != 128 {
+		t.Fatalf("Get(120): cap %d, want 128", cap(again))
+	}
+
+	// A grown buffer goes to the largest class it fills.
+	grown := append(make([]byte, 0, 64), make([]byte, 300)...)
+	p.Put(grown)
+	p.Put(make([]byte, 10)) // smaller than any class
+
+	d := p.Stats().Sub(before)
+	if d.Classes[1].Gets != 2 || d.Classes[1].Puts != 1 || d.Rejected != 1 {
+		t.Errorf("128-byte class = %+v, rejected %d; want 2 gets, 1 put, 1 rejected", d.Classes[1], d.Rejected)
+	}
+	if puts := d.Classes[2].Puts; puts != 1 {
+		t.Errorf("256-byte class got %d puts, want the grown buffer", puts)
+	}
+	// Under the race detector sync.Pool drops a random share of its Puts;
+	// in debug mode Put poisons the contents.
+	if d.Classes[1].News == 1 && !Debug() && again[0] != 42 {
+		t.Errorf("Get(120) did not reuse the 128-byte buffer")
+	}
+}
+
+func TestNewBytePoolPanics(t *testing.T) {
+	for _, sizes := range [][2]int{{0, 64}, {48, 64}, {64, 100}, {128, 64}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewBytePool(%d, %d) did not panic", sizes[0], sizes[1])
+				}
+			}()
+			NewBytePool(sizes[0], sizes[1])
+		}()
+	}
+}
@@ -0,0 +1,171 @@
+package pool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Debug mode catches the three classic pool bugs, at the cost of a stack
+// trace per Get and a map lookup per Put:
+//
+//   - use after Put: Put poisons the object with the Poison hook, and Get
+//     panics if the poison was disturbed while the object sat in the pool.
+//     Poisoned buffers also make most stale reads and writes fail on the spot.
+//   - double Put: Put panics on an object that is not checked out, or whose
+//     poison is intact, i.e. that is already in the pool.
+//   - leaks: every Get records its stack until the object comes back;
+//     WriteLeaks prints the objects that never did, grouped by stack.
+//
+// It is enabled by building with -tags pooldebug or by running with
+// POOLDEBUG=1, and applies to the pools created afterwards.
+var debugMode = debugTag || os.Getenv("POOLDEBUG") == "1"
+
+// Debug reports whether debug mode is enabled.
+func Debug() bool {
+	return debugMode
+}
+
+// PoisonByte is the value Poison hooks fill returned memory with: 0xDB is
+// neither a common byte in text nor a small integer, so stale data stands out.
+const PoisonByte = 0xDB
+
+// PoisonBytes fills b with PoisonByte.
+func PoisonBytes(b []byte) {
+	for i := range b {
+		b[i] = PoisonByte
+	}
+}
+
+// IsPoisoned reports whether all of b is PoisonByte.
+func IsPoisoned(b []byte) bool {
+	for _, c := range b {
+		if c != PoisonByte {
+			return false
+		}
+	}
+	return true
+}
+
+// checkout is an object handed out by Get and not yet returned.
+type checkout struct {
+	at    time.Time
+	stack []uintptr
+}
+
+// tracker follows the checkouts of one pool in debug mode.
+type tracker struct {
+	typ string
+
+	mu          sync.Mutex
+	outstanding map[any]checkout
+}
+
+// trackers are the trackers of all pools, for WriteLeaks.
+var trackers struct {
+	sync.Mutex
+	all []*tracker
+}
+
+func newTracker(typ string) *tracker {
+	t := &tracker{typ: typ, outstanding: make(map[any]checkout)}
+	trackers.Lock()
+	trackers.all = append(trackers.all, t)
+	trackers.Unlock()
+	return t
+}
+
+// get records that x was handed out by the caller of Get.
+func (t *tracker) get(x any) {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	t.mu.Lock()
+	t.outstanding[x] = checkout{at: time.Now(), stack: pcs}
+	t.mu.Unlock()
+}
+
+// put records that x came back and reports whether it was checked out.
+func (t *tracker) put(x any) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.outstanding[x]; !ok {
+		return false
+	}
+	delete(t.outstanding, x)
+	return true
+}
+
+// Leak is an object that was checked out with Get and not returned.
+type Leak struct {
+	Type  string        // type of the pooled objects
+	Age   time.Duration // time since Get
+	Stack string        // stack of the Get
+}
+
+// leaks returns the outstanding checkouts, oldest first.
+func (t *tracker) leaks(now time.Time) []Leak {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var leaks []Leak
+	for _, c := range t.outstanding {
+		leaks = append(leaks, Leak{Type: t.typ, Age: now.Sub(c.at), Stack: formatStack(c.stack)})
+	}
+	slices.SortFunc(leaks, func(a, b Leak) int { return int(b.Age - a.Age) })
+	return leaks
+}
+
+// formatStack formats a stack like a goroutine dump does.
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			return sb.String()
+		}
+	}
+}
+
+// Leaks returns the objects of every pool created in debug mode that are
+// checked out, oldest first. Without debug mode it returns nil.
+func Leaks() []Leak {
+	now := time.Now()
+	trackers.Lock()
+	defer trackers.Unlock()
+	var leaks []Leak
+	for _, t := range trackers.all {
+		leaks = append(leaks, t.leaks(now)...)
+	}
+	slices.SortStableFunc(leaks, func(a, b Leak) int { return int(b.Age - a.Age) })
+	return leaks
+}
+
+// WriteLeaks writes a report of the checked-out objects to w, one entry per
+// type and stack, and returns the number of objects. Call it at exit, or at
+// a point where every object should be back, such as the end of a request.
+func WriteLeaks(w io.Writer) int {
+	leaks := Leaks()
+	type site struct{ typ, stack string }
+	var order []site
+	count := make(map[site]int)
+	oldest := make(map[site]time.Duration)
+	for _, l := range leaks {
+		s := site{l.Type, l.Stack}
+		if count[s] == 0 {
+			order = append(order, s)
+			oldest[s] = l.Age
+		}
+		count[s]++
+	}
+	for _, s := range order {
+		fmt.Fprintf(w, "pool: %d %s not returned, oldest for %v, checked out at:\n%s\n",
+			count[s], s.typ, oldest[s].Round(time.Millisecond), s.stack)
+	}
+	return len(leaks)
+}
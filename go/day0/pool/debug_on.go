@@ -0,0 +1,6 @@
+//go:build pooldebug
+
+package pool
+
+// debugTag is set by building with -tags pooldebug.
+const debugTag = true
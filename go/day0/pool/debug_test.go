@@ -0,0 +1,104 @@
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// newDebugPool returns a pool of buffers created in debug mode.
+func newDebugPool(t *testing.T) *Pool[*buffer] {
+	t.Helper()
+	defer func(enabled bool) { debugMode = enabled }(debugMode)
+	debugMode = true
+	return New(func() *buffer { return &buffer{data: make([]byte, 0, 64)} }, Options[*buffer]{
+		Reset:    func(b *buffer) { b.data = b.data[:0] },
+		Poison:   func(b *buffer) { PoisonBytes(b.data[:cap(b.data)]) },
+		Poisoned: func(b *buffer) bool { return IsPoisoned(b.data[:cap(b.data)]) },
+	})
+}
+
+// recovered calls f and returns its panic message, "" if it did not panic.
+func recovered(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	f()
+	return ""
+}
+
+func TestDebugDoublePut(t *testing.T) {
+	p := newDebugPool(t)
+	b := p.Get()
+	p.Put(b)
+	if msg := recovered(func() { p.Put(b) }); !strings.Contains(msg, "double Put of *pool.buffer") {
+		t.Errorf("panic = %q", msg)
+	}
+
+	// Without a Poisoned hook any Put of an object not checked out panics.
+	defer func(enabled bool) { debugMode = enabled }(debugMode)
+	debugMode = true
+	plain := New(func() *buffer { return new(buffer) }, Options[*buffer]{})
+	if msg := recovered(func() { plain.Put(new(buffer)) }); !strings.Contains(msg, "not checked out") {
+		t.Errorf("panic = %q", msg)
+	}
+}
+
+func TestDebugUseAfterPut(t *testing.T) {
+	defer func(percent int) { runtime.GC(); debug.SetGCPercent(percent) }(debug.SetGCPercent(-1))
+
+	p := newDebugPool(t)
+	b := p.Get()
+	b.data = append(b.data, "hello"...)
+	p.Put(b)
+	if got := b.data[:5]; !IsPoisoned(got) {
+		t.Fatalf("returned buffer holds %q, want poison", got)
+	}
+	b.data[:1][0] = 'x' // stale write
+
+	// Under the race detector sync.Pool may drop the Put; try a few times.
+	for range 10 {
+		if msg := recovered(func() { p.Put(p.Get()) }); msg != "" {
+			if !strings.Contains(msg, "modified after Put") {
+				t.Errorf("panic = %q", msg)
+			}
+			return
+		}
+	}
+}
+
+func TestDebugLeaks(t *testing.T) {
+	p := newDebugPool(t)
+	kept := p.Get()
+	p.Put(p.Get())
+
+	var sb strings.Builder
+	WriteLeaks(&sb)
+	report := sb.String()
+	if !strings.Contains(report, "1 *pool.buffer not returned") || !strings.Contains(report, "TestDebugLeaks") {
+		t.Errorf("report does not show the leak of TestDebugLeaks:\n%s", report)
+	}
+	p.Put(kept)
+	for _, l := range Leaks() {
+		if strings.Contains(l.Stack, "TestDebugLeaks") {
+			t.Errorf("leak reported after Put:\n%s", l.Stack)
+		}
+	}
+}
+
+func TestDebugBytePool(t *testing.T) {
+	defer func(enabled bool) { debugMode = enabled }(debugMode)
+	debugMode = true
+	p := NewBytePool(64, 256)
+	b := p.Get(100)
+	p.Put(b)
+	if msg := recovered(func() { p.Put(b) }); !strings.Contains(msg, "double Put") {
+		t.Errorf("panic = %q", msg)
+	}
+	// Buffers grown by append are still welcome.
+	p.Put(make([]byte, 0, 200))
+}
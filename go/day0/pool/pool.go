@@ -0,0 +1,169 @@
+// Package pool provides Pool[T], a typed sync.Pool with hooks that reset and
+// validate returned objects and counters that show how often it really
+// saves an allocation. BytePool builds on it to pool byte slices of varying
+// length in power-of-two size classes.
+//
+// sync.Pool drops its contents over two garbage collections and keeps
+// per-P caches, so its hit rate depends on the GC and the scheduler as much
+// as on the code; the counters make that visible instead of assumed.
+package pool
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Options are the optional hooks of a Pool.
+type Options[T any] struct {
+	// Reset prepares an object for its next user; Put calls it before the
+	// object goes back into the pool.
+	Reset func(T)
+	// Validate reports whether a returned object may be reused. Put drops
+	// objects that fail, e.g. buffers that grew too large to keep around.
+	Validate func(T) bool
+	// Poison overwrites a returned object in debug mode, after Reset, so
+	// that a stale user reads garbage or fails instead of seeing valid data.
+	Poison func(T)
+	// Poisoned reports whether the poison of an object is intact. Debug mode
+	// checks it on Get to catch use after Put, and on Put to catch double
+	// Puts; with it, Put also accepts objects that did not come from Get.
+	Poisoned func(T) bool
+}
+
+// Pool is a typed sync.Pool. T should be a pointer type: storing any other
+// value in the underlying pool's interface allocates, which defeats it.
+//
+// A Pool is safe for concurrent use and must not be copied after first use.
+type Pool[T any] struct {
+	pool     sync.Pool
+	new      func() T
+	reset    func(T)
+	validate func(T) bool
+	poison   func(T)
+	poisoned func(T) bool
+	tracker  *tracker // nil unless in debug mode
+
+	gets, puts, news, dropped atomic.Int64
+}
+
+// New returns a pool that creates objects with newFn when it is empty.
+func New[T any](newFn func() T, opts Options[T]) *Pool[T] {
+	if newFn == nil {
+		panic("pool: New with nil constructor")
+	}
+	p := &Pool[T]{new: newFn, reset: opts.Reset, validate: opts.Validate, poison: opts.Poison, poisoned: opts.Poisoned}
+	if debugMode {
+		p.tracker = newTracker(fmt.Sprintf("%T", *new(T)))
+	}
+	return p
+}
+
+// Get returns an object from the pool, or a new one if the pool is empty.
+func (p *Pool[T]) Get() T {
+	p.gets.Add(1)
+	var x T
+	if v := p.pool.Get(); v != nil {
+		x = v.(T)
+		if p.tracker != nil {
+			p.unpoison(x)
+		}
+	} else {
+		p.news.Add(1)
+		x = p.new()
+	}
+	if p.tracker != nil {
+		p.tracker.get(x)
+	}
+	return x
+}
+
+// Put resets x and returns it to the pool, unless Validate rejects it.
+// x must not be used after Put.
+func (p *Pool[T]) Put(x T) {
+	if p.tracker != nil {
+		p.checkPut(x)
+	}
+	if p.validate != nil && !p.validate(x) {
+		p.dropped.Add(1)
+		return
+	}
+	if p.reset != nil {
+		p.reset(x)
+	}
+	if p.tracker != nil && p.poison != nil {
+		p.poison(x)
+	}
+	p.puts.Add(1)
+	p.pool.Put(x)
+}
+
+// checkPut panics on a Put of an object that is already in the pool.
+func (p *Pool[T]) checkPut(x T) {
+	if p.tracker.put(x) {
+		return
+	}
+	switch {
+	case p.poisoned == nil:
+		panic(fmt.Sprintf("pool: Put of %s that is not checked out: double Put, or not from Get", p.tracker.typ))
+	case p.poisoned(x):
+		panic(fmt.Sprintf("pool: double Put of %s", p.tracker.typ))
+	}
+}
+
+// unpoison panics if x was modified while it sat in the pool, and resets it
+// for its next user.
+func (p *Pool[T]) unpoison(x T) {
+	if p.poisoned != nil && !p.poisoned(x) {
+		panic(fmt.Sprintf("pool: %s modified after Put (use after Put)", p.tracker.typ))
+	}
+	if p.poison != nil && p.reset != nil {
+		p.reset(x)
+	}
+}
+
+// Stats returns the counters accumulated since the pool was created.
+func (p *Pool[T]) Stats() Stats {
+	return Stats{
+		Gets:    p.gets.Load(),
+		Puts:    p.puts.Load(),
+		News:    p.news.Load(),
+		Dropped: p.dropped.Load(),
+	}
+}
+
+// Stats counts the traffic of a pool.
+type Stats struct {
+	Gets    int64 // calls to Get
+	Puts    int64 // objects returned to the pool
+	News    int64 // Gets that found the pool empty and constructed an object
+	Dropped int64 // objects Put rejected because Validate failed
+}
+
+// Hits is the number of Gets served from the pool.
+func (s Stats) Hits() int64 {
+	return s.Gets - s.News
+}
+
+// HitRate is the fraction of Gets served from the pool, 0 without Gets.
+func (s Stats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits()) / float64(s.Gets)
+}
+
+// Sub returns the traffic between an earlier snapshot and s.
+func (s Stats) Sub(earlier Stats) Stats {
+	return Stats{
+		Gets:    s.Gets - earlier.Gets,
+		Puts:    s.Puts - earlier.Puts,
+		News:    s.News - earlier.News,
+		Dropped: s.Dropped - earlier.Dropped,
+	}
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d gets, %d new, %d puts, %d dropped, %.1f%% hit rate",
+		s.Gets, s.News, s.Puts, s.Dropped, 100*s.HitRate())
+}
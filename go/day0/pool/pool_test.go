@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+type buffer struct {
+	data []byte
+}
+
+func newTestPool() *Pool[*buffer] {
+	return New(func() *buffer { return &buffer{data: make([]byte, 0, 64)} }, Options[*buffer]{
+		Reset:    func(b *buffer) { b.data = b.data[:0] },
+		Validate: func(b *buffer) bool { return cap(b.data) <= 64 },
+	})
+}
+
+func TestPoolCounts(t *testing.T) {
+	// A GC between Put and Get empties the pool; keep it from running.
+	defer func(percent int) { runtime.GC(); debug.SetGCPercent(percent) }(debug.SetGCPercent(-1))
+
+	p := newTestPool()
+	b := p.Get()
+	b.data = append(b.data, "hello"...)
+	p.Put(b)
+
+	again := p.Get()
+	if len(again.data) != 0 {
+		t.Errorf("Get returned an object that was not reset: %q", again.data)
+	}
+	again.data = make([]byte, 0, 128)
+	p.Put(again) // grew too large
+
+	// The race detector makes sync.Pool drop a random share of its Puts, so
+	// the second Get may miss too.
+	got := p.Stats()
+	if got.Gets != 2 || got.Puts != 1 || got.Dropped != 1 || got.News < 1 || got.News > 2 {
+		t.Errorf("Stats = %+v, want 2 gets, 1 put, 1 dropped and 1 new", got)
+	}
+	if want := float64(2-got.News) / 2; got.HitRate() != want {
+		t.Errorf("HitRate = %v, want %v", got.HitRate(), want)
+	}
+}
+
+func TestStatsSub(t *testing.T) {
+	p := newTestPool()
+	before := p.Stats()
+	for range 3 {
+		p.Put(p.Get())
+	}
+	if d := p.Stats().Sub(before); d.Gets != 3 || d.Puts != 3 {
+		t.Errorf("Sub = %+v, want 3 gets and 3 puts", d)
+	}
+	if (Stats{}).HitRate() != 0 {
+		t.Error("HitRate without gets is not 0")
+	}
+}
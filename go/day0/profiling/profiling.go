@@ -0,0 +1,223 @@
+// Package profiling captures a pprof profile around a single benchmark run and
+// summarizes it like `go tool pprof -top`, so the demo can show where the time
+// or the allocations really go instead of asserting it.
+package profiling
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Kind selects the profile to capture.
+type Kind string
+
+const (
+	CPU   Kind = "cpu"
+	Heap  Kind = "heap"
+	Mutex Kind = "mutex"
+	Block Kind = "block"
+)
+
+// Kinds lists every supported profile kind.
+var Kinds = []Kind{CPU, Heap, Mutex, Block}
+
+// ParseKind validates a --profile value.
+func ParseKind(s string) (Kind, error) {
+	k := Kind(strings.ToLower(s))
+	if !slices.Contains(Kinds, k) {
+		return "", fmt.Errorf("unknown profile %q (want cpu, heap, mutex or block)", s)
+	}
+	return k, nil
+}
+
+// sampleType is the profile value the summary ranks functions by.
+func (k Kind) sampleType() string {
+	switch k {
+	case CPU:
+		return "cpu"
+	case Heap:
+		return "alloc_space"
+	}
+	return "delay" // mutex and block
+}
+
+// Entry is one function of a summary. Flat counts samples where the function
+// itself was running or allocating; Cum also counts the functions it called.
+type Entry struct {
+	Function string
+	Flat     int64
+	Cum      int64
+}
+
+// Summary is the top of a profile captured around one benchmark.
+type Summary struct {
+	Name  string // the name passed to Capture
+	Kind  Kind
+	File  string  // the pprof file written for `go tool pprof`
+	Unit  string  // unit of the values, e.g. "nanoseconds" or "bytes"
+	Total int64   // sum of all samples during the run
+	Top   []Entry // functions with the highest flat value, highest first
+}
+
+// Profiler captures one profile per benchmark into Dir.
+type Profiler struct {
+	Kind Kind
+	Dir  string
+	// TopN is the number of functions in a summary. Zero means 10.
+	TopN int
+}
+
+// Capture profiles run and writes the profile to Dir/<name>.<kind>.pprof.
+//
+// CPU profiles cover exactly the run. The heap, mutex and block profiles are
+// cumulative over the life of the process, like the files `go test` writes,
+// but the summary only counts what changed during the run.
+func (p *Profiler) Capture(name string, run func()) (*Summary, error) {
+	if err := os.MkdirAll(p.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var before, after []byte
+	var err error
+	switch p.Kind {
+	case CPU:
+		var buf bytes.Buffer
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, err
+		}
+		run()
+		pprof.StopCPUProfile()
+		after = buf.Bytes()
+	default:
+		defer p.enable()()
+		if before, err = p.snapshot(); err != nil {
+			return nil, err
+		}
+		run()
+		if after, err = p.snapshot(); err != nil {
+			return nil, err
+		}
+	}
+
+	file := filepath.Join(p.Dir, fmt.Sprintf("%s.%s.pprof", name, p.Kind))
+	if err := os.WriteFile(file, after, 0o644); err != nil {
+		return nil, err
+	}
+	s, err := p.summarize(before, after)
+	if err != nil {
+		return nil, err
+	}
+	s.Name, s.File = name, file
+	return s, nil
+}
+
+// enable turns on the sampling the profile kind needs and returns a function
+// that restores the previous setting.
+func (p *Profiler) enable() (restore func()) {
+	switch p.Kind {
+	case Mutex:
+		prev := runtime.SetMutexProfileFraction(1)
+		return func() { runtime.SetMutexProfileFraction(prev) }
+	case Block:
+		prev := SetBlockProfileRate(1)
+		return func() { SetBlockProfileRate(prev) }
+	}
+	return func() {}
+}
+
+// blockProfileRate is the rate last set by SetBlockProfileRate. The runtime
+// has no getter for it, unlike the mutex profile fraction.
+var blockProfileRate atomic.Int64
+
+// SetBlockProfileRate calls runtime.SetBlockProfileRate and returns the
+// previous rate, so that a block profile capture can restore it. A rate set
+// by calling the runtime directly is not seen and reads as 0.
+func SetBlockProfileRate(rate int) (prev int) {
+	prev = int(blockProfileRate.Swap(int64(rate)))
+	runtime.SetBlockProfileRate(rate)
+	return prev
+}
+
+// snapshot returns the current state of a cumulative profile.
+func (p *Profiler) snapshot() ([]byte, error) {
+	name := string(p.Kind)
+	if p.Kind == Heap {
+		// The allocs profile is only brought up to date by a garbage collection.
+		runtime.GC()
+		name = "allocs"
+	}
+	var buf bytes.Buffer
+	if err := pprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// summarize ranks functions by the values in after minus those in before.
+// Samples from writing the snapshots themselves are left out.
+func (p *Profiler) summarize(before, after []byte) (*Summary, error) {
+	s := &Summary{Kind: p.Kind}
+	flat, cum := map[string]int64{}, map[string]int64{}
+	for sign, data := range map[int64][]byte{-1: before, 1: after} {
+		if data == nil {
+			continue
+		}
+		prof, err := parseProfile(data)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := prof.sampleIndex(p.Kind.sampleType())
+		if err != nil {
+			return nil, err
+		}
+		s.Unit = prof.unit(idx)
+
+		for _, smp := range prof.samples {
+			v := sign * smp.values[idx]
+			stack := prof.stack(smp)
+			if len(stack) == 0 || slices.ContainsFunc(stack, isProfiler) {
+				continue
+			}
+			s.Total += v
+			flat[stack[0]] += v
+			seen := map[string]bool{}
+			for _, fn := range stack {
+				if !seen[fn] {
+					seen[fn] = true
+					cum[fn] += v
+				}
+			}
+		}
+	}
+
+	for fn, v := range flat {
+		if v > 0 {
+			s.Top = append(s.Top, Entry{Function: fn, Flat: v, Cum: cum[fn]})
+		}
+	}
+	slices.SortFunc(s.Top, func(a, b Entry) int {
+		if c := cmp.Compare(b.Flat, a.Flat); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Function, b.Function)
+	})
+	n := p.TopN
+	if n == 0 {
+		n = 10
+	}
+	s.Top = s.Top[:min(n, len(s.Top))]
+	return s, nil
+}
+
+// isProfiler reports whether a function belongs to the profile writer.
+func isProfiler(fn string) bool {
+	return strings.HasPrefix(fn, "runtime/pprof.")
+}
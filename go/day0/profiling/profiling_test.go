@@ -0,0 +1,66 @@
+package profiling
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var sink [][]byte
+
+//go:noinline
+func allocateBuffers() {
+	for range 1000 {
+		sink = append(sink, make([]byte, 4096))
+	}
+	sink = nil
+}
+
+func TestCaptureHeap(t *testing.T) {
+	p := &Profiler{Kind: Heap, Dir: t.TempDir(), TopN: 3}
+	prev := runtime.MemProfileRate
+	runtime.MemProfileRate = 1
+	defer func() { runtime.MemProfileRate = prev }()
+
+	s, err := p.Capture("allocate", allocateBuffers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.File); err != nil {
+		t.Errorf("profile file: %v", err)
+	}
+	if s.Unit != "bytes" || len(s.Top) == 0 || len(s.Top) > 3 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if top := s.Top[0]; !strings.HasSuffix(top.Function, ".allocateBuffers") || top.Flat < 1000*4096 {
+		t.Errorf("top entry = %+v, want allocateBuffers with at least 4 MB", top)
+	}
+	for _, e := range s.Top {
+		if isProfiler(e.Function) {
+			t.Errorf("profiler allocations leaked into summary: %s", e.Function)
+		}
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	if k, err := ParseKind("CPU"); err != nil || k != CPU {
+		t.Errorf("ParseKind(CPU) = %q, %v", k, err)
+	}
+	if _, err := ParseKind("goroutine"); err == nil {
+		t.Error("ParseKind(goroutine) succeeded")
+	}
+}
+
+func TestCaptureBlockRestoresRate(t *testing.T) {
+	SetBlockProfileRate(1000)
+	defer SetBlockProfileRate(0)
+
+	p := &Profiler{Kind: Block, Dir: t.TempDir()}
+	if _, err := p.Capture("block", func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if prev := SetBlockProfileRate(1000); prev != 1000 {
+		t.Errorf("block profile rate = %d after Capture, want the 1000 set before", prev)
+	}
+}
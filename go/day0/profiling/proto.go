@@ -0,0 +1,245 @@
+package profiling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// =============================================================================
+// PPROF DECODER
+// =============================================================================
+//
+// runtime/pprof writes gzipped protocol buffers (see profile.proto in
+// github.com/google/pprof). The summary only needs the sample values and the
+// function names of their stacks, so this decodes just those fields instead of
+// pulling in the full pprof library.
+
+// profile is the part of a pprof profile the summary needs.
+type profile struct {
+	sampleTypes []valueType
+	samples     []sample
+	locations   map[uint64][]uint64 // location ID -> function IDs, innermost first
+	functions   map[uint64]int64    // function ID -> name index into strings
+	strings     []string
+}
+
+type valueType struct {
+	typ, unit int64 // indexes into strings
+}
+
+type sample struct {
+	locations []uint64 // leaf first
+	values    []int64
+}
+
+// Field numbers from profile.proto.
+const (
+	profileSampleType  = 1
+	profileSample      = 2
+	profileLocation    = 4
+	profileFunction    = 5
+	profileStringTable = 6
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+
+	functionID   = 1
+	functionName = 2
+)
+
+var errTruncated = errors.New("profiling: truncated profile")
+
+// parseProfile decodes a gzipped or plain pprof profile.
+func parseProfile(data []byte) (*profile, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &profile{locations: map[uint64][]uint64{}, functions: map[uint64]int64{}}
+	err := fields(data, func(num int, wire int, v uint64, msg []byte) error {
+		switch num {
+		case profileSampleType:
+			var vt valueType
+			err := fields(msg, func(num, _ int, v uint64, _ []byte) error {
+				switch num {
+				case valueTypeType:
+					vt.typ = int64(v)
+				case valueTypeUnit:
+					vt.unit = int64(v)
+				}
+				return nil
+			})
+			p.sampleTypes = append(p.sampleTypes, vt)
+			return err
+		case profileSample:
+			var s sample
+			err := fields(msg, func(num, wire int, v uint64, packed []byte) error {
+				switch num {
+				case sampleLocationID:
+					return repeated(wire, v, packed, func(u uint64) { s.locations = append(s.locations, u) })
+				case sampleValue:
+					return repeated(wire, v, packed, func(u uint64) { s.values = append(s.values, int64(u)) })
+				}
+				return nil
+			})
+			p.samples = append(p.samples, s)
+			return err
+		case profileLocation:
+			var id uint64
+			var funcs []uint64
+			err := fields(msg, func(num, _ int, v uint64, line []byte) error {
+				switch num {
+				case locationID:
+					id = v
+				case locationLine:
+					return fields(line, func(num, _ int, v uint64, _ []byte) error {
+						if num == lineFunctionID {
+							funcs = append(funcs, v)
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+			p.locations[id] = funcs
+			return err
+		case profileFunction:
+			var id uint64
+			var name int64
+			err := fields(msg, func(num, _ int, v uint64, _ []byte) error {
+				switch num {
+				case functionID:
+					id = v
+				case functionName:
+					name = int64(v)
+				}
+				return nil
+			})
+			p.functions[id] = name
+			return err
+		case profileStringTable:
+			p.strings = append(p.strings, string(msg))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// sampleIndex returns the index of the sample value with the given type, e.g. "alloc_space".
+func (p *profile) sampleIndex(typ string) (int, error) {
+	for i, vt := range p.sampleTypes {
+		if p.str(vt.typ) == typ {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("profiling: profile has no %q samples", typ)
+}
+
+// unit returns the unit of the i-th sample value, e.g. "bytes".
+func (p *profile) unit(i int) string {
+	return p.str(p.sampleTypes[i].unit)
+}
+
+// stack returns the function names of a sample, innermost first, with
+// inlined functions expanded.
+func (p *profile) stack(s sample) []string {
+	var names []string
+	for _, loc := range s.locations {
+		for _, fn := range p.locations[loc] {
+			names = append(names, p.str(p.functions[fn]))
+		}
+	}
+	return names
+}
+
+func (p *profile) str(i int64) string {
+	if i < 0 || int(i) >= len(p.strings) {
+		return ""
+	}
+	return p.strings[i]
+}
+
+// fields calls fn for each field of a protobuf message. For varint fields v
+// holds the value; for length-delimited fields msg holds the payload.
+func fields(data []byte, fn func(num, wire int, v uint64, msg []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		num, wire := int(key>>3), int(key&7)
+
+		var v uint64
+		var msg []byte
+		switch wire {
+		case 0: // varint
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case 1: // fixed64
+			if len(data) < 8 {
+				return errTruncated
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2: // length-delimited
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errTruncated
+			}
+			msg, data = data[n:n+int(size)], data[n+int(size):]
+		case 5: // fixed32
+			if len(data) < 4 {
+				return errTruncated
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return fmt.Errorf("profiling: unsupported wire type %d", wire)
+		}
+		if err := fn(num, wire, v, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repeated decodes a repeated varint field, which runtime/pprof writes either
+// packed or as one field per value.
+func repeated(wire int, v uint64, packed []byte, add func(uint64)) error {
+	if wire != 2 {
+		add(v)
+		return nil
+	}
+	for len(packed) > 0 {
+		u, n := binary.Uvarint(packed)
+		if n <= 0 {
+			return errTruncated
+		}
+		add(u)
+		packed = packed[n:]
+	}
+	return nil
+}
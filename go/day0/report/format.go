@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format selects how records are rendered.
+type Format string
+
+const (
+	Text     Format = "text"
+	JSON     Format = "json"
+	CSV      Format = "csv"
+	Markdown Format = "markdown"
+)
+
+// Formats lists every supported format, default first.
+var Formats = []Format{Text, JSON, CSV, Markdown}
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	if f == "md" {
+		f = Markdown
+	}
+	if !slices.Contains(Formats, f) {
+		return "", fmt.Errorf("unknown format %q (want one of %s)", s, formatList())
+	}
+	return f, nil
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Machine reports whether the format is meant for tools rather than people.
+// Machine formats suppress the narrative text.
+func (f Format) Machine() bool {
+	return f != Text
+}
+
+// Render writes the records in the given format. The text format renders
+// nothing, because the narrative already showed every number.
+func Render(w io.Writer, f Format, records []Record) error {
+	switch f {
+	case Text:
+		return nil
+	case JSON:
+		return renderJSON(w, records)
+	case CSV:
+		return renderCSV(w, records)
+	case Markdown:
+		return renderMarkdown(w, records)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+func renderJSON(w io.Writer, records []Record) error {
+	if records == nil {
+		records = []Record{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+func renderCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"topic", "metric", "value", "unit", "params"}); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		row := []string{rec.Topic, rec.Metric, formatValue(rec.Value), rec.Unit, formatParams(rec.Params, ";")}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderMarkdown(w io.Writer, records []Record) error {
+	var sb strings.Builder
+	sb.WriteString("| Topic | Metric | Value | Unit | Parameters |\n")
+	sb.WriteString("|---|---|---:|---|---|\n")
+	for _, rec := range records {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+			escapeCell(rec.Topic), escapeCell(rec.Metric), formatValue(rec.Value),
+			escapeCell(rec.Unit), escapeCell(formatParams(rec.Params, ", ")))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatValue prints the shortest representation that round-trips.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatParams renders params as sorted key=value pairs.
+func formatParams(params map[string]string, sep string) string {
+	pairs := make([]string, 0, len(params))
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		pairs = append(pairs, k+"="+params[k])
+	}
+	return strings.Join(pairs, sep)
+}
+
+// cellEscaper keeps a cell on its row: a pipe would end the cell and a
+// newline the row.
+var cellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func escapeCell(s string) string {
+	return cellEscaper.Replace(s)
+}
@@ -0,0 +1,84 @@
+// Package report collects the numbers produced by demos and benchmarks as
+// structured records, so they can be rendered as JSON, CSV or Markdown instead
+// of being scraped from the ASCII text.
+package report
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+)
+
+// Record is one measured or computed value.
+type Record struct {
+	Topic  string            `json:"topic"`
+	Metric string            `json:"metric"`
+	Value  float64           `json:"value"`
+	Unit   string            `json:"unit"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Report is the destination of one run. Demos write their narrative to it as
+// an io.Writer and add records for every number they produce.
+//
+// For the text format the narrative goes to the terminal; for the machine
+// formats it is discarded and only the records are rendered at the end.
+type Report struct {
+	mu      sync.Mutex
+	text    io.Writer
+	topic   string
+	records []Record
+}
+
+// New returns a report that writes its narrative to text.
+// Pass io.Discard to collect records only.
+func New(text io.Writer) *Report {
+	return &Report{text: text}
+}
+
+// Write writes narrative text. It is safe for concurrent use, so demos may
+// print from several goroutines.
+func (r *Report) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.text.Write(p)
+}
+
+// SetTopic sets the topic attached to subsequently added records.
+func (r *Report) SetTopic(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.topic = id
+}
+
+// Add records a value. params are key/value pairs, e.g. "size", "1KB".
+func (r *Report) Add(metric string, value float64, unit string, params ...string) {
+	if len(params)%2 != 0 {
+		panic(fmt.Sprintf("report: odd number of params for metric %q", metric))
+	}
+	rec := Record{Metric: metric, Value: value, Unit: unit}
+	if len(params) > 0 {
+		rec.Params = make(map[string]string, len(params)/2)
+		for i := 0; i < len(params); i += 2 {
+			rec.Params[params[i]] = params[i+1]
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec.Topic = r.topic
+	r.records = append(r.records, rec)
+}
+
+// Records returns a copy of the records added so far.
+func (r *Report) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Record, len(r.records))
+	for i, rec := range r.records {
+		rec.Params = maps.Clone(rec.Params)
+		out[i] = rec
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tricky has cells that break naive renderers.
+func tricky() *Report {
+	r := New(io.Discard)
+	r.SetTopic("alignment")
+	r.Add("struct_size", 48, "bytes", "type", "Unaligned")
+	r.SetTopic(`a|b, "c"`)
+	r.Add("line\nbreak", 0.125, "ns|op", "key", `x,y "z"`, "note", "two\nlines | piped")
+	return r
+}
+
+func TestAddParams(t *testing.T) {
+	recs := tricky().Records()
+	want := []Record{
+		{Topic: "alignment", Metric: "struct_size", Value: 48, Unit: "bytes", Params: map[string]string{"type": "Unaligned"}},
+		{Topic: `a|b, "c"`, Metric: "line\nbreak", Value: 0.125, Unit: "ns|op", Params: map[string]string{"key": `x,y "z"`, "note": "two\nlines | piped"}},
+	}
+	if !reflect.DeepEqual(recs, want) {
+		t.Errorf("Records = %+v, want %+v", recs, want)
+	}
+
+	// Records returns copies.
+	recs[0].Params["type"] = "changed"
+	if got := tricky().Records()[0].Params["type"]; got != "Unaligned" {
+		t.Errorf("Params shared with the caller: %q", got)
+	}
+}
+
+func TestAddOddParamsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Add with an odd number of params did not panic")
+		}
+	}()
+	New(io.Discard).Add("metric", 1, "ns", "key")
+}
+
+func TestRender(t *testing.T) {
+	recs := tricky().Records()
+	for _, tc := range []struct {
+		format Format
+		check  func(t *testing.T, out string)
+	}{
+		{Text, func(t *testing.T, out string) {
+			if out != "" {
+				t.Errorf("text output = %q, want nothing", out)
+			}
+		}},
+		{JSON, func(t *testing.T, out string) {
+			var got []Record
+			if err := json.Unmarshal([]byte(out), &got); err != nil || !reflect.DeepEqual(got, recs) {
+				t.Errorf("JSON does not round-trip: %v\n%s", err, out)
+			}
+		}},
+		{CSV, func(t *testing.T, out string) {
+			rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+			want := [][]string{
+				{"topic", "metric", "value", "unit", "params"},
+				{"alignment", "struct_size", "48", "bytes", "type=Unaligned"},
+				{`a|b, "c"`, "line\nbreak", "0.125", "ns|op", "key=x,y \"z\";note=two\nlines | piped"},
+			}
+			if err != nil || !reflect.DeepEqual(rows, want) {
+				t.Errorf("CSV rows = %q, %v; want %q", rows, err, want)
+			}
+		}},
+		{Markdown, func(t *testing.T, out string) {
+			want := "| Topic | Metric | Value | Unit | Parameters |\n" +
+				"|---|---|---:|---|---|\n" +
+				"| alignment | struct_size | 48 | bytes | type=Unaligned |\n" +
+				`| a\|b, "c" | line<br>break | 0.125 | ns\|op | key=x,y "z", note=two<br>lines \| piped |` + "\n"
+			if out != want {
+				t.Errorf("Markdown =\n%s\nwant\n%s", out, want)
+			}
+		}},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var sb strings.Builder
+			if err := Render(&sb, tc.format, recs); err != nil {
+				t.Fatalf("Render = %v", err)
+			}
+			tc.check(t, sb.String())
+		})
+	}
+
+	if err := Render(io.Discard, "xml", recs); err == nil {
+		t.Error("Render with an unknown format succeeded")
+	}
+	var sb strings.Builder
+	if err := Render(&sb, JSON, nil); err != nil || strings.TrimSpace(sb.String()) != "[]" {
+		t.Errorf("JSON of no records = %q, %v; want []", sb.String(), err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"text": Text, "JSON": JSON, "csv": CSV, "md": Markdown, "markdown": Markdown} {
+		if got, err := ParseFormat(in); got != want || err != nil {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error(`ParseFormat("yaml") succeeded`)
+	}
+}
@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// MinSamples is the smallest sample size per side for which the Mann-Whitney U
+// test can reach significance at Alpha: with four samples each, the most
+// extreme ordering has p = 0.029, with three it is 0.1.
+const MinSamples = 4
+
+// exactLimit bounds n1*n2 for the exact distribution of U; larger samples use
+// the normal approximation.
+const exactLimit = 400
+
+// MannWhitneyU returns the two-sided p-value of the Mann-Whitney U test for
+// the hypothesis that a and b come from the same distribution. Unlike a t-test
+// it makes no assumption about the shape of the distribution, which matters
+// for timings with their long right tail.
+//
+// Small samples without ties use the exact distribution of U, as benchstat
+// does; otherwise the normal approximation with tie correction is used.
+// It returns 1 when either side is empty.
+func MannWhitneyU(a, b []float64) float64 {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	ranks, ties := rank(a, b)
+	var r1 float64
+	for i := range n1 {
+		r1 += ranks[i]
+	}
+	u1 := r1 - float64(n1*(n1+1))/2
+
+	if ties == 0 && n1*n2 <= exactLimit {
+		return exactP(n1, n2, u1)
+	}
+	return normalP(n1, n2, u1, ties)
+}
+
+// Significant reports whether a p-value indicates a real difference.
+func Significant(p float64) bool {
+	return p < Alpha
+}
+
+// rank returns the ranks of a followed by b in the combined sample, averaging
+// tied ranks, and the tie correction term sum(t³-t) over groups of t ties.
+func rank(a, b []float64) (ranks []float64, ties float64) {
+	type obs struct {
+		v float64
+		i int
+	}
+	all := make([]obs, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, obs{v, len(all)})
+	}
+	for _, v := range b {
+		all = append(all, obs{v, len(all)})
+	}
+	slices.SortFunc(all, func(x, y obs) int {
+		switch {
+		case x.v < y.v:
+			return -1
+		case x.v > y.v:
+			return 1
+		}
+		return 0
+	})
+
+	ranks = make([]float64, len(all))
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		avg := float64(i+j+1) / 2 // ranks i+1..j
+		for k := i; k < j; k++ {
+			ranks[all[k].i] = avg
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+	return ranks, ties
+}
+
+// exactP computes the two-sided p-value from the exact null distribution of
+// U, counting the orderings of n1 and n2 observations that give each U.
+func exactP(n1, n2 int, u float64) float64 {
+	maxU := n1 * n2
+	// counts[i][j][k] is the number of orderings of i a's and j b's with U = k.
+	counts := make([][][]float64, n1+1)
+	for i := range counts {
+		counts[i] = make([][]float64, n2+1)
+		for j := range counts[i] {
+			counts[i][j] = make([]float64, maxU+1)
+			if i == 0 || j == 0 {
+				counts[i][j][0] = 1
+				continue
+			}
+			for k := 0; k <= i*j; k++ {
+				// The largest observation is either an a, which beats all j b's,
+				// or a b, which beats nothing.
+				if k >= j {
+					counts[i][j][k] += counts[i-1][j][k-j]
+				}
+				counts[i][j][k] += counts[i][j-1][k]
+			}
+		}
+	}
+
+	dist := counts[n1][n2]
+	var total, lower, upper float64
+	for k, c := range dist {
+		total += c
+		if float64(k) <= u {
+			lower += c
+		}
+		if float64(k) >= u {
+			upper += c
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// normalP computes the two-sided p-value from the normal approximation of U
+// with continuity and tie correction.
+func normalP(n1, n2 int, u, ties float64) float64 {
+	n := float64(n1 + n2)
+	mu := float64(n1*n2) / 2
+	sigma := math.Sqrt(float64(n1*n2) / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -0,0 +1,129 @@
+// Package stats summarizes repeated timing samples and tests whether two sets
+// of samples differ.
+//
+// A single timing says little: the scheduler, the GC and CPU frequency scaling
+// easily move it by tens of percent. Every number the demo presents as a
+// result is therefore backed by several samples, summarized here with a
+// median, a 95% confidence interval and the outliers that were dropped, and
+// every "A is faster than B" claim is backed by a Mann-Whitney U test.
+package stats
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Alpha is the significance level used for comparisons.
+const Alpha = 0.05
+
+// Summary describes a set of samples after outlier rejection.
+type Summary struct {
+	N        int       // samples kept after outlier rejection
+	Median   float64   // median of the kept samples
+	Mean     float64   // mean of the kept samples
+	Stddev   float64   // sample standard deviation of the kept samples
+	CILow    float64   // lower bound of the 95% confidence interval of the mean
+	CIHigh   float64   // upper bound of the 95% confidence interval of the mean
+	Outliers []float64 // samples outside the Tukey fences, excluded above
+}
+
+// Summarize computes a summary of xs. Samples outside 1.5 interquartile ranges
+// of the quartiles (Tukey's fences) are reported as outliers and excluded;
+// at least four samples are needed for that, so smaller sets keep everything.
+func Summarize(xs []float64) Summary {
+	sorted := slices.Sorted(slices.Values(xs))
+	kept := sorted
+	var outliers []float64
+	if len(sorted) >= 4 {
+		q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+		iqr := q3 - q1
+		lo, hi := q1-1.5*iqr, q3+1.5*iqr
+		kept = nil
+		for _, x := range sorted {
+			if x < lo || x > hi {
+				outliers = append(outliers, x)
+			} else {
+				kept = append(kept, x)
+			}
+		}
+	}
+
+	s := Summary{N: len(kept), Outliers: outliers}
+	if s.N == 0 {
+		return s
+	}
+	s.Median = quantile(kept, 0.5)
+	for _, x := range kept {
+		s.Mean += x
+	}
+	s.Mean /= float64(s.N)
+	s.CILow, s.CIHigh = s.Mean, s.Mean
+	if s.N > 1 {
+		var ss float64
+		for _, x := range kept {
+			ss += (x - s.Mean) * (x - s.Mean)
+		}
+		s.Stddev = math.Sqrt(ss / float64(s.N-1))
+		half := tQuantile975(s.N-1) * s.Stddev / math.Sqrt(float64(s.N))
+		s.CILow, s.CIHigh = s.Mean-half, s.Mean+half
+	}
+	return s
+}
+
+// Spread is the half-width of the confidence interval as a percentage of the
+// mean, the "± x%" benchstat prints next to a value.
+func (s Summary) Spread() float64 {
+	if s.Mean == 0 {
+		return 0
+	}
+	return (s.CIHigh - s.CILow) / 2 / s.Mean * 100
+}
+
+// quantile returns the q-quantile of sorted data by linear interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i]*(1-frac) + sorted[i+1]*frac
+}
+
+// tTable holds the two-sided 95% quantiles of Student's t distribution for
+// 1 to 30 degrees of freedom.
+var tTable = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func tQuantile975(df int) float64 {
+	if df <= len(tTable) {
+		return tTable[df-1]
+	}
+	return 1.96
+}
+
+// Time calls f n times and returns the duration of each call in nanoseconds.
+func Time(n int, f func()) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		start := time.Now()
+		f()
+		samples[i] = float64(time.Since(start).Nanoseconds())
+	}
+	return samples
+}
+
+// TimePair samples f and g n times each, alternating between them so that
+// drift in machine load affects both sides equally.
+func TimePair(n int, f, g func()) (fs, gs []float64) {
+	fs, gs = make([]float64, n), make([]float64, n)
+	for i := range n {
+		fs[i] = Time(1, f)[0]
+		gs[i] = Time(1, g)[0]
+	}
+	return fs, gs
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-3
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{10, 12, 11, 13, 12, 100})
+	if len(s.Outliers) != 1 || s.Outliers[0] != 100 {
+		t.Fatalf("outliers = %v, want [100]", s.Outliers)
+	}
+	if s.N != 5 || s.Median != 12 || s.Mean != 11.6 {
+		t.Errorf("N, median, mean = %d, %g, %g, want 5, 12, 11.6", s.N, s.Median, s.Mean)
+	}
+	if !near(s.Stddev, 1.1402) {
+		t.Errorf("stddev = %g, want 1.1402", s.Stddev)
+	}
+	// t(0.975, 4) = 2.776
+	if half := (s.CIHigh - s.CILow) / 2; !near(half, 2.776*1.1402/math.Sqrt(5)) {
+		t.Errorf("CI half-width = %g", half)
+	}
+}
+
+func TestSummarizeSmall(t *testing.T) {
+	s := Summarize([]float64{5})
+	if s.N != 1 || s.Median != 5 || s.CILow != 5 || s.CIHigh != 5 || s.Spread() != 0 {
+		t.Errorf("single sample summary = %+v", s)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		// Complete separation of 4+4 samples: 2/C(8,4).
+		{"separated", []float64{1, 2, 3, 4}, []float64{5, 6, 7, 8}, 2.0 / 70},
+		{"identical", []float64{1, 1, 1}, []float64{1, 1, 1}, 1},
+		{"interleaved", []float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}, 0.6857},
+		{"empty", nil, []float64{1}, 1},
+	}
+	for _, tt := range tests {
+		if got := MannWhitneyU(tt.a, tt.b); !near(got, tt.want) {
+			t.Errorf("%s: p = %.4f, want %.4f", tt.name, got, tt.want)
+		}
+	}
+
+	if Significant(MannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})) {
+		t.Error("3+3 samples must not reach significance")
+	}
+}
@@ -2,11 +2,62 @@
 package topics
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
+
+	"day0/batch"
+	"day0/report"
+	"day0/stats"
 )
 
+func init() {
+	Register(&topic{
+		order:   8,
+		id:      "batching",
+		title:   "Batching Operations",
+		summary: "Grouping operations to amortize per-call overhead",
+		tags:    []string{"io", "throughput"},
+		takeaways: []string{
+			"Batching reduces overhead for I/O-bound operations",
+			"Batching trades latency for throughput",
+			"Bound batches by size and time so partial batches are not stranded",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Database Writes",
+				Names: []string{
+					"BenchmarkDBWriteIndividual",
+					"BenchmarkDBWriteBatch",
+					"BenchmarkDBWriteBatchSize10",
+					"BenchmarkDBWriteBatchSize100",
+					"BenchmarkDBWriteBatcher",
+				},
+			},
+			{
+				Title: "HTTP Requests",
+				Names: []string{
+					"BenchmarkHTTPSingleRequest",
+					"BenchmarkHTTPSmallBatch",
+					"BenchmarkHTTPLargeBatch",
+				},
+			},
+			{
+				Title: "Batch Processor",
+				Names: []string{
+					"BenchmarkBatchProcessorSmall",
+					"BenchmarkBatchProcessorMedium",
+					"BenchmarkBatchProcessorLarge",
+				},
+			},
+		},
+		demo: RunBatchingDemo,
+	})
+}
+
 // =============================================================================
 // BATCHING OPERATIONS
 // =============================================================================
@@ -53,6 +104,39 @@ func (db *SimulatedDB) BatchWrite(entries map[string]string) {
 	time.Sleep(time.Microsecond * 10)
 }
 
+// DBEntry is one write of a DBWriter.
+type DBEntry struct {
+	Key, Value string
+}
+
+// DBWriter turns single writes into BatchWrite calls.
+type DBWriter struct {
+	*batch.Batcher[DBEntry]
+}
+
+// NewDBWriter returns a writer that sends its entries to db in batches
+// bounded by opts. Size defaults to the length of the key and value.
+func NewDBWriter(db *SimulatedDB, opts batch.Options[DBEntry]) *DBWriter {
+	if opts.Size == nil {
+		opts.Size = func(e DBEntry) int { return len(e.Key) + len(e.Value) }
+	}
+	flush := func(_ context.Context, entries []DBEntry) error {
+		// A later write of a key in the same batch wins
+		m := make(map[string]string, len(entries))
+		for _, e := range entries {
+			m[e.Key] = e.Value
+		}
+		db.BatchWrite(m)
+		return nil
+	}
+	return &DBWriter{batch.New(flush, opts)}
+}
+
+// Write queues a write for the next batch.
+func (w *DBWriter) Write(ctx context.Context, key, value string) error {
+	return w.Add(ctx, DBEntry{key, value})
+}
+
 // =============================================================================
 // EXAMPLE 2: Batch HTTP Requests
 // =============================================================================
@@ -70,47 +154,143 @@ type HTTPResponse struct {
 	Body       []byte
 }
 
+// ErrClientClosed is the error of requests sent after Close.
+var ErrClientClosed = errors.New("batch HTTP client closed")
+
+// BatchTransport sends a batch of requests in one round trip and returns one
+// response per request, in order.
+type BatchTransport func(ctx context.Context, requests []HTTPRequest) ([]HTTPResponse, error)
+
+// SimulatedTransport answers every request with 200 and its payload after
+// one microsecond per round trip, whatever the batch size: the per-call
+// overhead batching amortizes.
+func SimulatedTransport(ctx context.Context, requests []HTTPRequest) ([]HTTPResponse, error) {
+	time.Sleep(time.Microsecond)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	responses := make([]HTTPResponse, len(requests))
+	for i, req := range requests {
+		responses[i] = HTTPResponse{StatusCode: 200, Body: req.Payload}
+	}
+	return responses, nil
+}
+
+// ResponseFuture is the response to one request of a batch, available once
+// its batch has been sent.
+type ResponseFuture struct {
+	done chan struct{}
+	resp HTTPResponse
+	err  error
+}
+
+func newResponseFuture() *ResponseFuture {
+	return &ResponseFuture{done: make(chan struct{})}
+}
+
+// resolve sets the outcome and wakes up the waiters.
+func (f *ResponseFuture) resolve(resp HTTPResponse, err error) {
+	f.resp, f.err = resp, err
+	close(f.done)
+}
+
+// Done is closed once the response is available.
+func (f *ResponseFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the response is available or ctx is done.
+func (f *ResponseFuture) Wait(ctx context.Context) (HTTPResponse, error) {
+	select {
+	case <-f.done:
+		return f.resp, f.err
+	case <-ctx.Done():
+		return HTTPResponse{}, ctx.Err()
+	}
+}
+
+// pendingRequest is a request waiting for its batch.
+type pendingRequest struct {
+	req    HTTPRequest
+	future *ResponseFuture
+}
+
 // BatchHTTPClient demonstrates batching HTTP requests.
+//
+// A batch is sent when it holds batchSize requests, when its oldest request
+// has waited flushDelay, on Flush, or on Close, whichever comes first.
 type BatchHTTPClient struct {
-	mu         sync.Mutex
-	pending    []HTTPRequest
-	batchSize  int
-	flushDelay time.Duration
+	batcher   *batch.Batcher[pendingRequest]
+	transport BatchTransport
 }
 
-// NewBatchHTTPClient creates a new batch HTTP client.
+// NewBatchHTTPClient creates a new batch HTTP client on SimulatedTransport.
+// A flushDelay of zero sends partial batches only on Flush and Close.
 func NewBatchHTTPClient(batchSize int, flushDelay time.Duration) *BatchHTTPClient {
-	return &BatchHTTPClient{
-		batchSize:  batchSize,
-		flushDelay: flushDelay,
-	}
+	return NewBatchHTTPClientWithTransport(batchSize, flushDelay, SimulatedTransport)
 }
 
-// Send adds a request to the batch and flushes if batch is full.
-func (c *BatchHTTPClient) Send(req HTTPRequest) HTTPResponse {
-	c.mu.Lock()
-	c.pending = append(c.pending, req)
+// NewBatchHTTPClientWithTransport creates a batch HTTP client that sends its
+// batches through transport.
+func NewBatchHTTPClientWithTransport(batchSize int, flushDelay time.Duration, transport BatchTransport) *BatchHTTPClient {
+	c := &BatchHTTPClient{transport: transport}
+	c.batcher = batch.New(c.send, batch.Options[pendingRequest]{
+		MaxItems:  max(batchSize, 1),
+		MaxLinger: flushDelay,
+	})
+	return c
+}
 
-	// Flush if batch is full
-	if len(c.pending) >= c.batchSize {
-		c.mu.Unlock()
-		return c.flush()
+// Send adds a request to the batch and returns the future of its response.
+// It blocks while the client is too far behind to queue it.
+func (c *BatchHTTPClient) Send(req HTTPRequest) *ResponseFuture {
+	future := newResponseFuture()
+	if err := c.batcher.Add(context.Background(), pendingRequest{req, future}); err != nil {
+		future.resolve(HTTPResponse{}, ErrClientClosed)
 	}
-	c.mu.Unlock()
+	return future
+}
 
-	// In real implementation, would also flush after flushDelay
-	return HTTPResponse{StatusCode: 200}
+// send makes the round trip for a batch and resolves its futures.
+func (c *BatchHTTPClient) send(ctx context.Context, pending []pendingRequest) error {
+	requests := make([]HTTPRequest, len(pending))
+	for i, p := range pending {
+		requests[i] = p.req
+	}
+	responses, err := c.transport(ctx, requests)
+	if err == nil && len(responses) != len(pending) {
+		err = fmt.Errorf("batch HTTP transport returned %d responses for %d requests", len(responses), len(pending))
+	}
+	for i, p := range pending {
+		if err != nil {
+			p.future.resolve(HTTPResponse{}, err)
+			continue
+		}
+		p.future.resolve(responses[i], nil)
+	}
+	return err
 }
 
-// flush sends all pending requests as a batch.
-func (c *BatchHTTPClient) flush() HTTPResponse {
-	requests := c.pending
-	c.pending = nil
+// Flush sends the pending requests now and waits for their responses. The
+// round trip is canceled with ctx.
+func (c *BatchHTTPClient) Flush(ctx context.Context) error {
+	return c.batcher.Flush(ctx)
+}
 
-	// Simulate batch request processing
-	_ = len(requests)
+// Close sends the pending requests and waits for their responses. Requests
+// sent after Close fail with ErrClientClosed.
+func (c *BatchHTTPClient) Close() error {
+	return c.batcher.Close(context.Background())
+}
+
+// Batches is the number of batches sent so far.
+func (c *BatchHTTPClient) Batches() int64 {
+	return int64(c.batcher.Stats().Batches)
+}
 
-	return HTTPResponse{StatusCode: 200, Body: []byte("batch response")}
+// Stats returns the batching counters of the client.
+func (c *BatchHTTPClient) Stats() batch.Stats {
+	return c.batcher.Stats()
 }
 
 // =============================================================================
@@ -129,22 +309,83 @@ type Result struct {
 	Success bool
 }
 
-// BatchProcessor processes tasks in batches for efficiency.
+// BatchProcessor processes tasks in batches for efficiency: submitted tasks
+// are grouped into batches of up to batchSize, which workerCount workers
+// process concurrently.
 type BatchProcessor struct {
-	taskChan    chan Task
+	batcher     *batch.Batcher[Task]
+	batches     chan []Task
 	resultChan  chan Result
 	workerCount int
 	batchSize   int
+
+	workers   sync.WaitGroup
+	closeOnce sync.Once
 }
 
-// NewBatchProcessor creates a new batch processor.
+// NewBatchProcessor creates a new batch processor and starts its workers.
 func NewBatchProcessor(workerCount, batchSize int) *BatchProcessor {
-	return &BatchProcessor{
-		taskChan:    make(chan Task, batchSize*2),
+	bp := &BatchProcessor{
+		batches:     make(chan []Task, workerCount),
 		resultChan:  make(chan Result, batchSize*2),
 		workerCount: workerCount,
 		batchSize:   batchSize,
 	}
+	bp.batcher = batch.New(bp.dispatch, batch.Options[Task]{MaxItems: batchSize})
+	for range workerCount {
+		bp.workers.Go(bp.work)
+	}
+	return bp
+}
+
+// dispatch hands a batch to the workers, waiting while they are all busy.
+func (bp *BatchProcessor) dispatch(ctx context.Context, tasks []Task) error {
+	select {
+	case bp.batches <- slices.Clone(tasks):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work processes batches until the processor is closed.
+func (bp *BatchProcessor) work() {
+	for tasks := range bp.batches {
+		for _, r := range bp.ProcessBatch(tasks) {
+			bp.resultChan <- r
+		}
+	}
+}
+
+// Submit queues a task for the next batch. It blocks while the processor is
+// too far behind, until ctx is done.
+func (bp *BatchProcessor) Submit(ctx context.Context, task Task) error {
+	return bp.batcher.Add(ctx, task)
+}
+
+// Results delivers the result of every submitted task and is closed by
+// Close. It must be drained, or the workers stop once it is full.
+func (bp *BatchProcessor) Results() <-chan Result {
+	return bp.resultChan
+}
+
+// Close processes the tasks already submitted, stops the workers and closes
+// Results.
+func (bp *BatchProcessor) Close(ctx context.Context) error {
+	if err := bp.batcher.Close(ctx); err != nil {
+		return err
+	}
+	bp.closeOnce.Do(func() {
+		close(bp.batches)
+		bp.workers.Wait()
+		close(bp.resultChan)
+	})
+	return nil
+}
+
+// Stats returns the batching counters of the processor.
+func (bp *BatchProcessor) Stats() batch.Stats {
+	return bp.batcher.Stats()
 }
 
 // ProcessBatch processes a batch of tasks together.
@@ -166,8 +407,8 @@ func (bp *BatchProcessor) ProcessBatch(tasks []Task) []Result {
 // =============================================================================
 
 // demoDatabaseBatching demonstrates batching database writes.
-func demoDatabaseBatching() {
-	fmt.Println("=== DATABASE WRITE BATCHING ===")
+func demoDatabaseBatching(out *report.Report) {
+	fmt.Fprintln(out, "=== DATABASE WRITE BATCHING ===")
 
 	db := &SimulatedDB{}
 	entries := map[string]string{
@@ -177,180 +418,207 @@ func demoDatabaseBatching() {
 	}
 
 	// Without batching - individual writes
-	start := time.Now()
-	for range 10 {
-		for k, v := range entries {
-			db.Write(k, v)
+	individualRound := func() {
+		for range 10 {
+			for k, v := range entries {
+				db.Write(k, v)
+			}
 		}
 	}
-	individualTime := time.Since(start)
-	fmt.Printf("Individual writes (30 total): %v\n", individualTime)
-	fmt.Printf("Write count: %d\n", db.writeCount)
-
-	// Reset
-	db.writeCount = 0
-
 	// With batching
-	start = time.Now()
-	for range 10 {
-		db.BatchWrite(entries)
+	batchRound := func() {
+		for range 10 {
+			db.BatchWrite(entries)
+		}
 	}
-	batchTime := time.Since(start)
-	fmt.Printf("Batch writes (10 batches): %v\n", batchTime)
-	fmt.Printf("Write count: %d\n", db.writeCount)
 
-	improvement := float64(individualTime.Nanoseconds()) / float64(batchTime.Nanoseconds())
-	fmt.Printf("Speedup: %.2fx\n", improvement)
-	fmt.Println()
+	individualRound()
+	individualWrites := db.writeCount
+	db.writeCount = 0
+	batchRound()
+	batchWrites := db.writeCount
+
+	// Alternate the two so machine noise hits both equally
+	individual, batched := stats.TimePair(demoSamples/3, individualRound, batchRound)
+	individualTime, batchTime := stats.Summarize(individual), stats.Summarize(batched)
+	fmt.Fprintf(out, "Individual writes (30 total): %s\n", formatTiming(individualTime))
+	fmt.Fprintf(out, "Write count: %d\n", individualWrites)
+	fmt.Fprintf(out, "Batch writes (10 batches): %s\n", formatTiming(batchTime))
+	fmt.Fprintf(out, "Write count: %d\n", batchWrites)
+	printSpeedup(out, "db_writes_speedup", individual, batched)
+	fmt.Fprintln(out)
+
+	addTiming(out, "db_writes", individualTime, "mode", "individual", "writes", "30")
+	addTiming(out, "db_writes", batchTime, "mode", "batch", "writes", "30")
 }
 
 // demoHTTPBatching demonstrates batching HTTP requests.
-func demoHTTPBatching() {
-	fmt.Println("=== HTTP REQUEST BATCHING ===")
+func demoHTTPBatching(out *report.Report) {
+	fmt.Fprintln(out, "=== HTTP REQUEST BATCHING ===")
+
+	ctx := context.Background()
+	send := func(client *BatchHTTPClient) func() {
+		return func() {
+			futures := make([]*ResponseFuture, 100)
+			for i := range futures {
+				futures[i] = client.Send(HTTPRequest{
+					URL:    fmt.Sprintf("/api/item/%d", i),
+					Method: "POST",
+				})
+			}
+			for _, f := range futures {
+				f.Wait(ctx)
+			}
+		}
+	}
 
-	client := NewBatchHTTPClient(10, time.Millisecond)
+	// A batch size of one sends every request in its own round trip
+	client := NewBatchHTTPClient(1, 0)
+	client2 := NewBatchHTTPClient(100, 0)
+	defer client.Close()
+	defer client2.Close()
+	individual, batched := stats.TimePair(demoSamples, send(client), send(client2))
+	individualTime, batchTime := stats.Summarize(individual), stats.Summarize(batched)
+	fmt.Fprintf(out, "Individual requests (100):          %s\n", formatTiming(individualTime))
+	fmt.Fprintf(out, "Batched requests (1 batch of 100):  %s\n", formatTiming(batchTime))
+	printSpeedup(out, "http_requests_speedup", individual, batched)
+	fmt.Fprintln(out)
+
+	addTiming(out, "http_requests", individualTime, "batch_size", "1", "requests", "100")
+	addTiming(out, "http_requests", batchTime, "batch_size", "100", "requests", "100")
+
+	printPartialBatch(out)
+}
+
+// printPartialBatch shows the timer sending a batch that never fills, and
+// Close draining one that was never sent.
+func printPartialBatch(out *report.Report) {
+	fmt.Fprintln(out, "=== PARTIAL BATCHES ===")
 
-	// Simulate individual requests
+	const delay = 5 * time.Millisecond
+	client := NewBatchHTTPClient(10, delay)
 	start := time.Now()
-	for i := range 100 {
-		client.Send(HTTPRequest{
-			URL:    fmt.Sprintf("/api/item/%d", i),
-			Method: "POST",
-		})
+	var last *ResponseFuture
+	for i := range 25 {
+		last = client.Send(HTTPRequest{URL: fmt.Sprintf("/api/item/%d", i), Method: "POST"})
 	}
-	individualTime := time.Since(start)
-	fmt.Printf("Individual requests (100): %v\n", individualTime)
-
-	// Simulate batched requests (would be actual batching in production)
-	client2 := NewBatchHTTPClient(100, time.Millisecond)
-	start = time.Now()
-	for i := range 100 {
-		client2.Send(HTTPRequest{
-			URL:    fmt.Sprintf("/api/item/%d", i),
-			Method: "POST",
-		})
+	resp, err := last.Wait(context.Background())
+	waited := time.Since(start)
+	fmt.Fprintf(out, "25 requests, batch size 10, flush delay %v:\n", delay)
+	fmt.Fprintf(out, "  batches sent: %d (two full, one by the timer)\n", client.Batches())
+	fmt.Fprintf(out, "  last response: %d %v after %v\n", resp.StatusCode, err, waited.Round(100*time.Microsecond))
+	client.Close()
+	out.Add("http_partial_batch_wait", float64(waited.Nanoseconds()), "ns", "batch_size", "10", "requests", "25", "flush_delay", delay.String())
+
+	// Without a timer, Close sends what is left
+	client = NewBatchHTTPClient(10, 0)
+	futures := make([]*ResponseFuture, 5)
+	for i := range futures {
+		futures[i] = client.Send(HTTPRequest{URL: fmt.Sprintf("/api/item/%d", i), Method: "POST"})
 	}
-	batchTime := time.Since(start)
-	fmt.Printf("Batched requests (1 batch of 100): %v\n", batchTime)
-
-	improvement := float64(individualTime.Nanoseconds()) / float64(batchTime.Nanoseconds())
-	fmt.Printf("Speedup: %.2fx\n", improvement)
-	fmt.Println()
+	client.Close()
+	answered := 0
+	for _, f := range futures {
+		if resp, err := f.Wait(context.Background()); err == nil && resp.StatusCode == 200 {
+			answered++
+		}
+	}
+	_, err = client.Send(HTTPRequest{URL: "/api/late", Method: "POST"}).Wait(context.Background())
+	fmt.Fprintf(out, "5 requests, no flush delay, then Close: %d answered\n", answered)
+	fmt.Fprintf(out, "Send after Close: %v\n", err)
+	fmt.Fprintln(out)
 }
 
-// RunBatchingDemo demonstrates all batching patterns.
-func RunBatchingDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                        BATCHING OPERATIONS DEMONSTRATION                     ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	demoDatabaseBatching()
-	demoHTTPBatching()
+// demoBatcher shows why batches are flushed and how large they turn out
+// under bursty and trickling load.
+func demoBatcher(out *report.Report) {
+	fmt.Fprintln(out, "=== GENERIC BATCHER ===")
+	ctx := context.Background()
 
-	// Run micro-benchmarks for database operations
+	const maxItems, maxBytes, linger = 50, 512, 2 * time.Millisecond
 	db := &SimulatedDB{}
-	entries := map[string]string{
-		"key1": "value1",
-		"key2": "value2",
-		"key3": "value3",
-	}
-	dbIterations := 1000
-	// Individual writes benchmark
-	dbStart := time.Now()
-	for range dbIterations {
-		for k, v := range entries {
-			db.Write(k, v)
-		}
+	writer := NewDBWriter(db, batch.Options[DBEntry]{MaxItems: maxItems, MaxBytes: maxBytes, MaxLinger: linger})
+	// Bursts of short entries fill batches by count, long values by bytes,
+	// and a trickle leaves partial batches to the timer
+	for i := range 120 {
+		writer.Write(ctx, fmt.Sprintf("user:%d", i), "active")
 	}
-	dbIndividualTime := time.Since(dbStart)
-	dbIndividualNsOp := float64(dbIndividualTime.Nanoseconds()) / float64(dbIterations*len(entries))
-
-	// Reset and test batch writes
-	db.writeCount = 0
-	dbBatchStart := time.Now()
-	for range dbIterations {
-		db.BatchWrite(entries)
+	for i := range 40 {
+		writer.Write(ctx, fmt.Sprintf("profile:%d", i), fmt.Sprintf("%064d", i))
 	}
-	dbBatchTime := time.Since(dbBatchStart)
-	dbBatchNsOp := float64(dbBatchTime.Nanoseconds()) / float64(dbIterations)
-
-	// HTTP benchmarks
-	httpIterations := 10000
-	// Single requests benchmark
-	singleClient := NewBatchHTTPClient(1, time.Millisecond)
-	httpSingleStart := time.Now()
-	for i := range httpIterations {
-		_ = i
-		singleClient.Send(HTTPRequest{
-			URL:    "/api/item",
-			Method: "POST",
-		})
+	for i := range 3 {
+		writer.Write(ctx, fmt.Sprintf("audit:%d", i), "login")
+		time.Sleep(2 * linger)
 	}
-	httpSingleTime := time.Since(httpSingleStart)
-	httpSingleNsOp := float64(httpSingleTime.Nanoseconds()) / float64(httpIterations)
-
-	// Small batch (10) benchmark
-	smallBatchClient := NewBatchHTTPClient(10, time.Millisecond)
-	httpSmallStart := time.Now()
-	for i := range httpIterations {
-		smallBatchClient.Send(HTTPRequest{
-			URL:    fmt.Sprintf("/api/item/%d", i),
-			Method: "POST",
-		})
+	writer.Close(ctx)
+
+	s := writer.Stats()
+	fmt.Fprintf(out, "DB writer (max %d items, %d bytes, %v linger): %d writes\n", maxItems, maxBytes, linger, db.writeCount)
+	fmt.Fprintf(out, "  %v\n", s)
+	fmt.Fprintf(out, "  batch sizes: %v\n", s.Sizes)
+	fmt.Fprintf(out, "  batch bytes: %v\n", s.ByteSizes)
+	for r := batch.ReasonItems; r <= batch.ReasonClose; r++ {
+		out.Add("batcher_flushes", float64(s.Reasons[r]), "batches", "batcher", "db_writer", "reason", r.String())
 	}
-	httpSmallTime := time.Since(httpSmallStart)
-	httpSmallNsOp := float64(httpSmallTime.Nanoseconds()) / float64(httpIterations)
-
-	// Large batch (100) benchmark
-	largeBatchClient := NewBatchHTTPClient(100, time.Millisecond)
-	httpLargeStart := time.Now()
-	for i := range httpIterations {
-		largeBatchClient.Send(HTTPRequest{
-			URL:    fmt.Sprintf("/api/item/%d", i),
-			Method: "POST",
-		})
+	out.Add("batcher_mean_size", s.MeanSize(), "items", "batcher", "db_writer")
+
+	// The workers bound the batches in flight; the batcher's queue bounds
+	// the tasks waiting, and Submit blocks beyond that
+	bp := NewBatchProcessor(4, 100)
+	results := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range bp.Results() {
+			results++
+		}
+	}()
+	for i := range 1000 {
+		bp.Submit(ctx, Task{ID: i, Data: "test"})
 	}
-	httpLargeTime := time.Since(httpLargeStart)
-	httpLargeNsOp := float64(httpLargeTime.Nanoseconds()) / float64(httpIterations)
-
-	// Print benchmark results with actual measurements
-	fmt.Println("=== BENCHMARK RESULTS ===")
-	fmt.Println("Database Write Comparison (3 items):")
-	fmt.Printf("  - Individual writes: ~%.0f ns/op\n", dbIndividualNsOp)
-	fmt.Printf("  - Batch write (3 items): ~%.0f ns/op\n", dbBatchNsOp)
-	fmt.Println("  -> Batch amortizes overhead across items")
-	fmt.Println()
-	fmt.Println("HTTP Request Comparison:")
-	fmt.Printf("  - Single request: ~%.0f ns/op\n", httpSingleNsOp)
-	fmt.Printf("  - Small batch (10): ~%.0f ns/op (~%.0f ns/item)\n", httpSmallNsOp, httpSmallNsOp/10)
-	fmt.Printf("  - Large batch (100): ~%.0f ns/op (~%.0f ns/item)\n", httpLargeNsOp, httpLargeNsOp/100)
-	fmt.Println("  -> Larger batches reduce per-item overhead")
-	fmt.Println()
+	bp.Close(ctx)
+	<-done
+	s = bp.Stats()
+	fmt.Fprintf(out, "Batch processor (4 workers, batches of 100): %d results\n", results)
+	fmt.Fprintf(out, "  %v\n", s)
+	fmt.Fprintln(out)
+	out.Add("batcher_blocked_adds", float64(s.Blocked), "adds", "batcher", "processor")
+}
+
+// RunBatchingDemo demonstrates all batching patterns.
+func RunBatchingDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                        BATCHING OPERATIONS DEMONSTRATION                     ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	demoDatabaseBatching(out)
+	demoHTTPBatching(out)
+	demoBatcher(out)
 
 	// Explain when to use batching
-	fmt.Println("=== WHEN TO USE BATCHING ===")
-	fmt.Println("✓ Database writes - group inserts/updates")
-	fmt.Println("✓ Network requests - combine multiple API calls")
-	fmt.Println("✓ File I/O - buffer writes before flushing")
-	fmt.Println("✓ Message queues - batch messages for throughput")
-	fmt.Println()
-	fmt.Println("✗ Don't batch when:")
-	fmt.Println("  - Latency is critical (batching adds delay)")
-	fmt.Println("  - Operations are unrelated (complexity not worth it)")
-	fmt.Println("  - Single-item latency matters more than throughput")
-	fmt.Println()
+	fmt.Fprintln(out, "=== WHEN TO USE BATCHING ===")
+	fmt.Fprintln(out, "✓ Database writes - group inserts/updates")
+	fmt.Fprintln(out, "✓ Network requests - combine multiple API calls")
+	fmt.Fprintln(out, "✓ File I/O - buffer writes before flushing")
+	fmt.Fprintln(out, "✓ Message queues - batch messages for throughput")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "✗ Don't batch when:")
+	fmt.Fprintln(out, "  - Latency is critical (batching adds delay)")
+	fmt.Fprintln(out, "  - Operations are unrelated (complexity not worth it)")
+	fmt.Fprintln(out, "  - Single-item latency matters more than throughput")
+	fmt.Fprintln(out)
 
 	// Key insight
-	fmt.Println("=== KEY INSIGHT ===")
-	fmt.Println("Batching trades latency for throughput:")
-	fmt.Println("  - Individual ops: Low latency, high overhead")
-	fmt.Println("  - Batched ops: Higher latency, lower overhead")
-	fmt.Println()
-	fmt.Println("Choose based on your use case:")
-	fmt.Println("  - User-facing: Lower latency (fewer batches)")
-	fmt.Println("  - Background processing: Higher throughput (larger batches)")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "=== KEY INSIGHT ===")
+	fmt.Fprintln(out, "Batching trades latency for throughput:")
+	fmt.Fprintln(out, "  - Individual ops: Low latency, high overhead")
+	fmt.Fprintln(out, "  - Batched ops: Higher latency, lower overhead")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Choose based on your use case:")
+	fmt.Fprintln(out, "  - User-facing: Lower latency (fewer batches)")
+	fmt.Fprintln(out, "  - Background processing: Higher throughput (larger batches)")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
@@ -0,0 +1,188 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"day0/batch"
+)
+
+// recordingTransport is a BatchTransport that records the batch sizes.
+type recordingTransport struct {
+	mu    sync.Mutex
+	sizes []int
+	err   error
+}
+
+func (r *recordingTransport) send(ctx context.Context, requests []HTTPRequest) ([]HTTPResponse, error) {
+	r.mu.Lock()
+	r.sizes = append(r.sizes, len(requests))
+	r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return SimulatedTransport(ctx, requests)
+}
+
+func (r *recordingTransport) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.sizes...)
+}
+
+func TestBatchHTTPClientFullBatches(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewBatchHTTPClientWithTransport(10, 0, transport.send)
+
+	// Concurrent senders under -race check that the client is safe to share.
+	var wg sync.WaitGroup
+	futures := make([]*ResponseFuture, 40)
+	for g := range 4 {
+		wg.Go(func() {
+			for i := g * 10; i < (g+1)*10; i++ {
+				futures[i] = client.Send(HTTPRequest{URL: "/api", Payload: []byte(fmt.Sprint(i))})
+			}
+		})
+	}
+	wg.Wait()
+	if err := client.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush = %v", err)
+	}
+
+	for i, f := range futures {
+		select {
+		case <-f.Done():
+		default:
+			t.Fatalf("request %d not answered after Flush", i)
+		}
+		resp, err := f.Wait(context.Background())
+		if err != nil || resp.StatusCode != 200 || string(resp.Body) != fmt.Sprint(i) {
+			t.Errorf("request %d: %d %q, %v", i, resp.StatusCode, resp.Body, err)
+		}
+	}
+	if sizes := transport.batchSizes(); len(sizes) != 4 || client.Batches() != 4 {
+		t.Errorf("batch sizes = %v, Batches = %d; want 4 batches of 10", sizes, client.Batches())
+	}
+}
+
+func TestBatchHTTPClientFlushDelay(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewBatchHTTPClientWithTransport(10, time.Millisecond, transport.send)
+	defer client.Close()
+
+	f := client.Send(HTTPRequest{URL: "/api"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := f.Wait(ctx); err != nil {
+		t.Fatalf("partial batch not sent by the timer: %v", err)
+	}
+	if sizes := transport.batchSizes(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("batch sizes = %v, want one batch of 1", sizes)
+	}
+}
+
+func TestBatchHTTPClientFlushAndClose(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewBatchHTTPClientWithTransport(10, 0, transport.send)
+
+	first := client.Send(HTTPRequest{URL: "/api"})
+	if err := client.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush = %v", err)
+	}
+	if _, err := first.Wait(context.Background()); err != nil {
+		t.Errorf("flushed request: %v", err)
+	}
+
+	pending := []*ResponseFuture{client.Send(HTTPRequest{}), client.Send(HTTPRequest{})}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	for _, f := range pending {
+		if _, err := f.Wait(context.Background()); err != nil {
+			t.Errorf("request pending at Close: %v", err)
+		}
+	}
+	if _, err := client.Send(HTTPRequest{}).Wait(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Send after Close = %v, want ErrClientClosed", err)
+	}
+	if sizes := transport.batchSizes(); len(sizes) != 2 || sizes[0] != 1 || sizes[1] != 2 {
+		t.Errorf("batch sizes = %v, want 1 then 2", sizes)
+	}
+	if err := client.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+}
+
+func TestBatchHTTPClientTransportError(t *testing.T) {
+	failure := errors.New("connection reset")
+	client := NewBatchHTTPClientWithTransport(2, 0, (&recordingTransport{err: failure}).send)
+	futures := []*ResponseFuture{client.Send(HTTPRequest{}), client.Send(HTTPRequest{})}
+	for i, f := range futures {
+		if _, err := f.Wait(context.Background()); !errors.Is(err, failure) {
+			t.Errorf("request %d: %v, want the transport error", i, err)
+		}
+	}
+
+	short := func(context.Context, []HTTPRequest) ([]HTTPResponse, error) { return nil, nil }
+	client = NewBatchHTTPClientWithTransport(1, 0, short)
+	if _, err := client.Send(HTTPRequest{}).Wait(context.Background()); err == nil {
+		t.Error("missing response not reported")
+	}
+}
+
+func TestDBWriter(t *testing.T) {
+	db := &SimulatedDB{}
+	w := NewDBWriter(db, batch.Options[DBEntry]{MaxItems: 4, MaxBytes: 20})
+	ctx := context.Background()
+	for i := range 10 {
+		if err := w.Write(ctx, fmt.Sprintf("k%d", i), "value"); err != nil {
+			t.Fatalf("Write = %v", err)
+		}
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+
+	// Entries are 7 bytes, so the 20-byte limit cuts batches at 2 entries
+	// and the last pair waits for Close.
+	s := w.Stats()
+	if db.writeCount != 10 || s.Reasons[batch.ReasonBytes] != 4 || s.Reasons[batch.ReasonClose] != 1 {
+		t.Errorf("writeCount = %d, Stats = %v; want 10 writes in 4 batches cut by bytes and 1 on Close", db.writeCount, s)
+	}
+}
+
+func TestBatchProcessor(t *testing.T) {
+	bp := NewBatchProcessor(3, 10)
+	seen := make(map[int]bool)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for r := range bp.Results() {
+			if !r.Success || seen[r.TaskID] {
+				t.Errorf("result %+v: failed or duplicated", r)
+			}
+			seen[r.TaskID] = true
+		}
+	}()
+
+	ctx := context.Background()
+	for i := range 95 {
+		if err := bp.Submit(ctx, Task{ID: i, Data: "test"}); err != nil {
+			t.Fatalf("Submit = %v", err)
+		}
+	}
+	if err := bp.Close(ctx); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	<-done
+	if len(seen) != 95 {
+		t.Errorf("got %d results, want 95", len(seen))
+	}
+	if s := bp.Stats(); s.Batches != 10 || s.Reasons[batch.ReasonClose] != 1 {
+		t.Errorf("Stats = %v, want 9 full batches and the rest on Close", s)
+	}
+}
@@ -0,0 +1,134 @@
+package topics
+
+import "io"
+
+// Buffer represents a reusable data buffer.
+// In real scenarios, this could be a connection, a parser, etc.
+//
+// Data[:Length] holds the bytes written so far; Read, WriteTo and String
+// consume them from the front. Write grows Data when it is full, doubling it
+// like bytes.Buffer does, so a Buffer is an io.Writer, io.ByteWriter,
+// io.Reader, io.WriterTo and fmt.Stringer. The zero value is an empty buffer
+// ready to use.
+type Buffer struct {
+	Data   []byte
+	Length int
+	// Limit caps the size Write grows Data to; 0 means no limit. A write
+	// that does not fit stores the bytes that do and returns
+	// io.ErrShortWrite.
+	Limit int
+
+	read int // offset of the first unread byte
+}
+
+// Reset clears the buffer for reuse, keeping Data and Limit.
+func (b *Buffer) Reset() {
+	b.Length, b.read = 0, 0
+}
+
+// Len is the number of unread bytes.
+func (b *Buffer) Len() int {
+	return b.Length - b.read
+}
+
+// Bytes returns the unread bytes. The slice aliases Data and is only valid
+// until the next write or Reset.
+func (b *Buffer) Bytes() []byte {
+	return b.Data[b.read:b.Length]
+}
+
+// grow makes room for n more bytes if Limit allows: first by starting over
+// when everything was read, then by moving the unread bytes to the front,
+// then by allocating a larger Data.
+func (b *Buffer) grow(n int) {
+	if b.read == b.Length {
+		b.Reset()
+	}
+	if b.Length+n <= len(b.Data) {
+		return
+	}
+	if b.read > 0 {
+		b.Length = copy(b.Data, b.Data[b.read:b.Length])
+		b.read = 0
+		if b.Length+n <= len(b.Data) {
+			return
+		}
+	}
+	size := max(2*len(b.Data), b.Length+n)
+	if b.Limit > 0 {
+		size = min(size, b.Limit)
+	}
+	if size <= len(b.Data) {
+		return
+	}
+	data := make([]byte, size)
+	copy(data, b.Data[:b.Length])
+	b.Data = data
+}
+
+// Write appends p to the buffer, growing it as needed. It returns
+// io.ErrShortWrite if Limit kept it from storing all of p.
+func (b *Buffer) Write(p []byte) (int, error) {
+	if b.read > 0 || b.Length+len(p) > len(b.Data) {
+		b.grow(len(p))
+	}
+	n := copy(b.Data[b.Length:], p)
+	b.Length += n
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// WriteByte appends c to the buffer. It returns io.ErrShortWrite if the
+// buffer is full and Limit keeps it from growing.
+func (b *Buffer) WriteByte(c byte) error {
+	if b.read > 0 || b.Length == len(b.Data) {
+		b.grow(1)
+	}
+	if b.Length == len(b.Data) {
+		return io.ErrShortWrite
+	}
+	b.Data[b.Length] = c
+	b.Length++
+	return nil
+}
+
+// Read reads the next len(p) unread bytes, or as many as there are. It
+// returns io.EOF when there is nothing left to read.
+func (b *Buffer) Read(p []byte) (int, error) {
+	if b.read == b.Length {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := copy(p, b.Data[b.read:b.Length])
+	b.read += n
+	return n, nil
+}
+
+// WriteTo writes the unread bytes to w in a single Write call.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	unread := b.Len()
+	if unread == 0 {
+		return 0, nil
+	}
+	n, err := w.Write(b.Data[b.read:b.Length])
+	if n < 0 || n > unread {
+		panic("topics: Buffer.WriteTo: invalid Write count")
+	}
+	b.read += n
+	if err == nil && n < unread {
+		err = io.ErrShortWrite
+	}
+	return int64(n), err
+}
+
+// String returns the unread bytes as a string, "<nil>" for a nil *Buffer.
+func (b *Buffer) String() string {
+	if b == nil {
+		return "<nil>"
+	}
+	return string(b.Data[b.read:b.Length])
+}
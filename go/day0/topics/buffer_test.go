@@ -0,0 +1,88 @@
+package topics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBufferWriteGrows(t *testing.T) {
+	b := &Buffer{Data: make([]byte, SmallBufferSize)}
+	data := bytes.Repeat([]byte("0123456789"), 150)
+	for range 2 {
+		if n, err := b.Write(data); n != len(data) || err != nil {
+			t.Fatalf("Write = %d, %v; want %d, nil", n, err, len(data))
+		}
+	}
+	if b.Length != 2*len(data) || b.Len() != 2*len(data) || len(b.Data) < b.Length {
+		t.Fatalf("Length = %d, Len = %d, len(Data) = %d after writing %d bytes", b.Length, b.Len(), len(b.Data), 2*len(data))
+	}
+	if got := b.String(); got != string(data)+string(data) {
+		t.Errorf("String = %q...", got[:20])
+	}
+}
+
+func TestBufferLimit(t *testing.T) {
+	b := &Buffer{Data: make([]byte, 4), Limit: 6}
+	n, err := b.Write([]byte("abcdefgh"))
+	if n != 6 || !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("Write = %d, %v; want 6, io.ErrShortWrite", n, err)
+	}
+	if err := b.WriteByte('x'); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("WriteByte on a full buffer = %v", err)
+	}
+
+	// Reading makes room again.
+	p := make([]byte, 2)
+	if n, _ := b.Read(p); n != 2 || string(p) != "ab" {
+		t.Errorf("Read = %d, %q", n, p)
+	}
+	if err := b.WriteByte('x'); err != nil {
+		t.Errorf("WriteByte after Read = %v", err)
+	}
+	if got := b.String(); got != "cdefx" {
+		t.Errorf("String = %q, want %q", got, "cdefx")
+	}
+}
+
+func TestBufferReader(t *testing.T) {
+	const content = "the quick brown fox jumps over the lazy dog"
+	b := &Buffer{}
+	b.Write([]byte(content))
+	if err := iotest.TestReader(b, []byte(content)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBufferWriteTo(t *testing.T) {
+	var b Buffer
+	for _, c := range []byte("hello") {
+		b.WriteByte(c)
+	}
+	var sb strings.Builder
+	if n, err := b.WriteTo(&sb); n != 5 || err != nil || sb.String() != "hello" {
+		t.Errorf("WriteTo = %d, %v, wrote %q", n, err, sb.String())
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len after WriteTo = %d", b.Len())
+	}
+	if n, err := b.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read after WriteTo = %d, %v; want io.EOF", n, err)
+	}
+	if got := (*Buffer)(nil).String(); got != "<nil>" {
+		t.Errorf("nil String = %q", got)
+	}
+}
+
+func TestBufferPoolDropsGrown(t *testing.T) {
+	p := NewBufferPool(SmallBufferSize)
+	b := p.Get()
+	b.Write(make([]byte, 2*SmallBufferSize))
+	p.Put(b)
+	if s := p.Stats(); s.Dropped != 1 || s.Puts != 0 {
+		t.Errorf("Stats = %+v, want the grown buffer dropped", s)
+	}
+}
@@ -0,0 +1,299 @@
+package topics
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"day0/cpucache"
+	"day0/report"
+	"day0/stats"
+)
+
+// =============================================================================
+// CACHE ACCESS PATTERNS
+// =============================================================================
+//
+// Smaller structs only pay off when memory is the bottleneck. These functions
+// walk []UnalignedStruct and []AlignedStruct in three ways:
+//
+// - Sequential: every element in order; the hardware prefetcher streams the
+//   lines in, so this measures bandwidth.
+// - Strided: every AccessStride-th element, then the next offset; each access
+//   touches a new cache line, so the bytes wasted on padding cost more lines.
+// - Random: follows a random cycle through the slice stored in Field2, so
+//   each load depends on the previous one; this measures latency.
+//
+// Sweeping the working set from a few KB to beyond the last-level cache shows
+// the cliffs where the data stops fitting in L1, L2 and L3.
+
+// Element sizes of the two layouts on this architecture.
+var (
+	unalignedElemSize = int64(reflect.TypeFor[UnalignedStruct]().Size())
+	alignedElemSize   = int64(reflect.TypeFor[AlignedStruct]().Size())
+)
+
+// AccessStride is the step of the strided walk in elements: 768 bytes of
+// UnalignedStruct, 512 of AlignedStruct, beyond the adjacent-line prefetcher.
+const AccessStride = 16
+
+// SumSequentialUnaligned reads every element in order.
+func SumSequentialUnaligned(data []UnalignedStruct) int64 {
+	var sum int64
+	for i := range data {
+		sum += data[i].Field2 + data[i].Field4 + data[i].Field6
+	}
+	return sum
+}
+
+// SumSequentialAligned reads every element in order.
+func SumSequentialAligned(data []AlignedStruct) int64 {
+	var sum int64
+	for i := range data {
+		sum += data[i].Field2 + data[i].Field4 + data[i].Field6
+	}
+	return sum
+}
+
+// SumStridedUnaligned reads every element once, stride elements apart.
+func SumStridedUnaligned(data []UnalignedStruct, stride int) int64 {
+	var sum int64
+	for start := range stride {
+		for i := start; i < len(data); i += stride {
+			sum += data[i].Field2 + data[i].Field4 + data[i].Field6
+		}
+	}
+	return sum
+}
+
+// SumStridedAligned reads every element once, stride elements apart.
+func SumStridedAligned(data []AlignedStruct, stride int) int64 {
+	var sum int64
+	for start := range stride {
+		for i := start; i < len(data); i += stride {
+			sum += data[i].Field2 + data[i].Field4 + data[i].Field6
+		}
+	}
+	return sum
+}
+
+// ChaseUnaligned follows the cycle linked through Field2 for steps elements.
+func ChaseUnaligned(data []UnalignedStruct, steps int) int64 {
+	var sum, i int64
+	for range steps {
+		e := &data[i]
+		sum += e.Field4 + e.Field6
+		i = e.Field2
+	}
+	return sum
+}
+
+// ChaseAligned follows the cycle linked through Field2 for steps elements.
+func ChaseAligned(data []AlignedStruct, steps int) int64 {
+	var sum, i int64
+	for range steps {
+		e := &data[i]
+		sum += e.Field4 + e.Field6
+		i = e.Field2
+	}
+	return sum
+}
+
+// randomCycle returns next[i] such that following next from any element
+// visits all n elements in random order before coming back. The fixed seed
+// makes every run walk the same cycle.
+func randomCycle(n int) []int {
+	perm := rand.New(rand.NewPCG(42, 42)).Perm(n)
+	next := make([]int, n)
+	for i := range perm {
+		next[perm[i]] = perm[(i+1)%n]
+	}
+	return next
+}
+
+// NewAccessData returns n elements of each layout with the same values and
+// Field2 linking a random cycle for the chase.
+func NewAccessData(n int) ([]UnalignedStruct, []AlignedStruct) {
+	unaligned, aligned := make([]UnalignedStruct, n), make([]AlignedStruct, n)
+	for i, next := range randomCycle(n) {
+		unaligned[i] = UnalignedStruct{Field1: int8(i), Field2: int64(next), Field3: 1, Field4: int64(i), Field5: 2, Field6: 1}
+		aligned[i] = AlignedStruct{Field1: int8(i), Field2: int64(next), Field3: 1, Field4: int64(i), Field5: 2, Field6: 1}
+	}
+	return unaligned, aligned
+}
+
+// Accesses per measurement of the sweep: enough to dwarf timer overhead in
+// L1, few enough that a DRAM-sized random walk stays around 50ms.
+const (
+	sweepAccesses      = 1 << 20
+	sweepChaseAccesses = 1 << 19
+	sweepSamples       = 3
+	sweepMinBytes      = 4 << 10
+	sweepMaxBytes      = 256 << 20
+)
+
+// sweepPoint is the cost per element of one pattern at one working set.
+type sweepPoint struct {
+	pattern              string
+	unaligned, aligned   float64 // ns per element
+	unalignedB, alignedB int64   // working set in bytes
+}
+
+// sweepSizes returns the working sets of the sweep in bytes of
+// UnalignedStruct: powers of two from 4 KB to four times the last-level
+// cache, at least 64 MB and at most 256 MB.
+func sweepSizes(info cpucache.Info) []int64 {
+	limit := int64(64 << 20)
+	if levels := info.Levels(); len(levels) > 0 {
+		limit = max(limit, 4*info.DataSize(levels[len(levels)-1]))
+	}
+	limit = min(limit, sweepMaxBytes)
+	var sizes []int64
+	for ws := int64(sweepMinBytes); ws <= limit; ws *= 2 {
+		sizes = append(sizes, ws)
+	}
+	return sizes
+}
+
+// measureAccess times the three patterns over n elements of each layout and
+// returns the median cost per element access.
+func measureAccess(n int) []sweepPoint {
+	unaligned, aligned := NewAccessData(n)
+	passes := max(1, sweepAccesses/n)
+	var sink int64
+	perElement := func(f, g func(), accesses int) (float64, float64) {
+		fs, gs := stats.TimePair(sweepSamples, f, g)
+		return stats.Summarize(fs).Median / float64(accesses), stats.Summarize(gs).Median / float64(accesses)
+	}
+
+	seqU, seqA := perElement(
+		func() {
+			for range passes {
+				sink += SumSequentialUnaligned(unaligned)
+			}
+		},
+		func() {
+			for range passes {
+				sink += SumSequentialAligned(aligned)
+			}
+		}, passes*n)
+	strU, strA := perElement(
+		func() {
+			for range passes {
+				sink += SumStridedUnaligned(unaligned, AccessStride)
+			}
+		},
+		func() {
+			for range passes {
+				sink += SumStridedAligned(aligned, AccessStride)
+			}
+		}, passes*n)
+	rndU, rndA := perElement(
+		func() { sink += ChaseUnaligned(unaligned, sweepChaseAccesses) },
+		func() { sink += ChaseAligned(aligned, sweepChaseAccesses) },
+		sweepChaseAccesses)
+	_ = sink
+
+	uB, aB := int64(n)*unalignedElemSize, int64(n)*alignedElemSize
+	return []sweepPoint{
+		{"sequential", seqU, seqA, uB, aB},
+		{"strided", strU, strA, uB, aB},
+		{"random", rndU, rndA, uB, aB},
+	}
+}
+
+// printAccessSweep measures the access patterns across working sets from L1
+// to DRAM, prints a table and a latency curve, and points out the cliffs.
+func printAccessSweep(out *report.Report, info cpucache.Info) {
+	var rows [][]sweepPoint
+	for _, ws := range sweepSizes(info) {
+		rows = append(rows, measureAccess(int(ws/unalignedElemSize)))
+	}
+
+	fmt.Fprintln(out, "Nanoseconds per element (lower is better); U = UnalignedStruct, A = AlignedStruct.")
+	fmt.Fprintf(out, "Same element count for both, so A's working set is 2/3 of U's. Stride: %d elements.\n", AccessStride)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-21s | %-9s | %-17s | %-17s | %-17s\n", "Working set U / A", "Fits U/A", "Sequential U   A", "Strided U      A", "Random U       A")
+	fmt.Fprintln(out, strings.Repeat("-", 93))
+	for _, row := range rows {
+		uB, aB := row[0].unalignedB, row[0].alignedB
+		fmt.Fprintf(out, "%-21s | %-9s", formatWorkingSet(uB)+" / "+formatWorkingSet(aB), info.Fits(uB)+"/"+info.Fits(aB))
+		for _, p := range row {
+			fmt.Fprintf(out, " | %7.2f %7.2f  ", p.unaligned, p.aligned)
+			for _, v := range []struct {
+				typ   string
+				ns    float64
+				bytes int64
+				size  int64
+			}{
+				{"UnalignedStruct", p.unaligned, uB, unalignedElemSize},
+				{"AlignedStruct", p.aligned, aB, alignedElemSize},
+			} {
+				params := []string{"type", v.typ, "pattern", p.pattern,
+					"working_set", strconv.FormatInt(v.bytes, 10), "fits", info.Fits(v.bytes)}
+				out.Add("access_time", v.ns, "ns/element", params...)
+				out.Add("access_throughput", float64(v.size)/v.ns, "GB/s", params...)
+			}
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "Random access latency of AlignedStruct (log scale):")
+	var lo, hi float64 = math.Inf(1), 0
+	for _, row := range rows {
+		lo, hi = min(lo, row[2].aligned), max(hi, row[2].aligned)
+	}
+	for _, row := range rows {
+		p := row[2]
+		width := 1
+		if hi > lo {
+			width += int(40 * math.Log(p.aligned/lo) / math.Log(hi/lo))
+		}
+		fmt.Fprintf(out, "%10s %-4s |%s %.1f ns\n", formatWorkingSet(p.alignedB), info.Fits(p.alignedB), strings.Repeat("#", width), p.aligned)
+	}
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "Cliffs (random access latency of AlignedStruct up 1.5x or more from the previous size):")
+	cliffs := 0
+	for i := 1; i < len(rows); i++ {
+		prev, cur := rows[i-1][2], rows[i][2]
+		if cur.aligned >= 1.5*prev.aligned {
+			cliffs++
+			fmt.Fprintf(out, "  %s → %s: %.1f → %.1f ns (%s → %s)\n", formatWorkingSet(prev.alignedB), formatWorkingSet(cur.alignedB),
+				prev.aligned, cur.aligned, info.Fits(prev.alignedB), info.Fits(cur.alignedB))
+			out.Add("latency_cliff", cur.aligned/prev.aligned, "x",
+				"from", strconv.FormatInt(prev.alignedB, 10), "to", strconv.FormatInt(cur.alignedB, 10))
+		}
+	}
+	if cliffs == 0 {
+		fmt.Fprintln(out, "  none: latency grew gradually on this machine")
+	}
+}
+
+// formatWorkingSet formats a size in bytes with three significant digits,
+// e.g. "32 KB", "683 KB" or "85.3 MB".
+func formatWorkingSet(n int64) string {
+	v, unit := float64(n), "B"
+	for _, u := range []string{"KB", "MB", "GB"} {
+		if v < 1000 {
+			break
+		}
+		v, unit = v/1024, u
+	}
+	decimals := 2
+	switch {
+	case v >= 100:
+		decimals = 0
+	case v >= 10:
+		decimals = 1
+	}
+	text := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text + " " + unit
+}
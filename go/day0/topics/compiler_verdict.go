@@ -0,0 +1,68 @@
+package topics
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"day0/escape"
+	"day0/report"
+)
+
+// =============================================================================
+// COMPILER VERDICTS
+// =============================================================================
+//
+// The escape topics used to only assert which functions allocate. These
+// helpers show what `go build -gcflags=-m=2` actually reports for them.
+
+// escapeAnalysis compiles this package once per run; the go command caches
+// the build and replays the diagnostics.
+var escapeAnalysis = sync.OnceValues(func() ([]escape.Diagnostic, error) {
+	return escape.Analyze(reflect.TypeFor[LargeStruct]().PkgPath())
+})
+
+// verdictKinds are the diagnostics worth showing next to a function.
+var verdictKinds = []escape.Kind{
+	escape.MovedToHeap, escape.EscapesToHeap, escape.DoesNotEscape,
+	escape.LeakingParam, escape.CanInline, escape.CannotInline,
+}
+
+// printCompilerVerdicts prints the escape analysis and inlining decisions of
+// the compiler for each function and records them. Without the go command or
+// the sources it says so and moves on.
+func printCompilerVerdicts(out *report.Report, funcs ...string) {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Compiler Verdict (go build -gcflags=-m=2) ===")
+
+	diags, err := escapeAnalysis()
+	if err != nil {
+		fmt.Fprintln(out, "Not available: run the demo from the module directory with the Go toolchain installed.")
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		fmt.Fprintf(out, "  (%s)\n", msg)
+		return
+	}
+
+	for _, name := range funcs {
+		fmt.Fprintf(out, "%s:\n", name)
+		shown := 0
+		for _, d := range escape.ForFunction(diags, name) {
+			if !slices.Contains(verdictKinds, d.Kind) {
+				continue
+			}
+			shown++
+			// Drop the inlined body the compiler appends to "can inline F with cost N as: ..."
+			msg, _, _ := strings.Cut(d.Message, " as: ")
+			fmt.Fprintf(out, "  %-20s %s\n", d.Pos(), msg)
+			out.Add("escape_diagnostic", float64(d.Line), "line",
+				"function", name, "variable", d.Variable, "verdict", string(d.Kind),
+				"column", strconv.Itoa(d.Column))
+		}
+		if shown == 0 {
+			fmt.Fprintln(out, "  (no escape or inlining diagnostics)")
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package topics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"day0/report"
+	"day0/stats"
+)
+
+//go:generate go run gen_copy_sizes.go
+
+// =============================================================================
+// COPY-SIZE CROSSOVER
+// =============================================================================
+//
+// AddByValue and AddByPointer only compare one size, 1 KB. The sweep repeats
+// the comparison for structs from 8 B to 4 KB (copy_sizes_gen.go) to find the
+// size from which passing a pointer wins on this machine, twice:
+//
+// - Inlined: the compiler sees through the call; a value parameter may never
+//   be copied at all, so the crossover moves up or disappears.
+// - //go:noinline: a real call; value arguments are copied into registers
+//   (small structs) or onto the stack (anything with an array of length > 1).
+
+// CopySize is one size of the copy-size sweep. Each function calls its
+// AddByValue or AddByPointer variant n times and returns the sum.
+type CopySize struct {
+	Size              int
+	ByValue           func(n int) int64
+	ByPointer         func(n int) int64
+	ByValueNoInline   func(n int) int64
+	ByPointerNoInline func(n int) int64
+}
+
+const (
+	// copySweepCalls is the number of calls per sample of the sweep.
+	copySweepCalls = 200_000
+	// copyMinSpeedup is how much faster the pointer variant must be to count
+	// as faster: a fraction of a cycle per call is not worth a rule of thumb.
+	copyMinSpeedup = 1.05
+)
+
+// copySweepBenchmarks returns the names of the generated sweep benchmarks,
+// with or without inlining, in order of size.
+func copySweepBenchmarks(inline bool) []string {
+	suffix := ""
+	if !inline {
+		suffix = "NoInline"
+	}
+	var names []string
+	for _, c := range CopySizes {
+		size := strconv.Itoa(c.Size)
+		names = append(names, "BenchmarkCopyByValue"+size+suffix, "BenchmarkCopyByPointer"+size+suffix)
+	}
+	return names
+}
+
+// copyPoint is the time per call of both variants at one size.
+type copyPoint struct {
+	size               int
+	value, pointer     float64 // ns per call, median
+	pointerSignificant bool    // pointer significantly and copyMinSpeedup faster
+}
+
+// measureCopySweep times the value and pointer variants at every size.
+func measureCopySweep(inline bool) []copyPoint {
+	var sink int64
+	points := make([]copyPoint, 0, len(CopySizes))
+	for _, c := range CopySizes {
+		byValue, byPointer := c.ByValue, c.ByPointer
+		if !inline {
+			byValue, byPointer = c.ByValueNoInline, c.ByPointerNoInline
+		}
+		vs, ps := stats.TimePair(demoSamples,
+			func() { sink += byValue(copySweepCalls) },
+			func() { sink += byPointer(copySweepCalls) })
+		for i := range vs {
+			vs[i] /= copySweepCalls
+			ps[i] /= copySweepCalls
+		}
+		v, p := stats.Summarize(vs).Median, stats.Summarize(ps).Median
+		points = append(points, copyPoint{
+			size:               c.Size,
+			value:              v,
+			pointer:            p,
+			pointerSignificant: v >= copyMinSpeedup*p && stats.Significant(stats.MannWhitneyU(vs, ps)),
+		})
+	}
+	_ = sink
+	return points
+}
+
+// crossover returns the smallest size from which passing a pointer is
+// significantly faster at every larger size, or 0 if it never is.
+func crossover(points []copyPoint) int {
+	size := 0
+	for i := len(points) - 1; i >= 0 && points[i].pointerSignificant; i-- {
+		size = points[i].size
+	}
+	return size
+}
+
+// printCopySweep measures the sweep with and without inlining, prints both
+// side by side and the crossover of each.
+func printCopySweep(out *report.Report) {
+	inlined, called := measureCopySweep(true), measureCopySweep(false)
+
+	fmt.Fprintln(out, "Time per call of AddByValueN(a, b) vs AddByPointerN(&a, &b), two N-byte structs.")
+	fmt.Fprintf(out, "V/P above 1 means the pointer is faster; * marks a significant speedup of %.2fx or more.\n", copyMinSpeedup)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-8s | %-29s | %-29s\n", "", "Inlined", "//go:noinline")
+	fmt.Fprintf(out, "%-8s | %9s %9s %9s | %9s %9s %9s\n", "Size", "Value", "Pointer", "V/P", "Value", "Pointer", "V/P")
+	fmt.Fprintln(out, strings.Repeat("-", 74))
+	for i := range inlined {
+		fmt.Fprintf(out, "%-8s", formatWorkingSet(int64(inlined[i].size)))
+		for _, p := range []copyPoint{inlined[i], called[i]} {
+			mark := " "
+			if p.pointerSignificant {
+				mark = "*"
+			}
+			fmt.Fprintf(out, " | %6.2f ns %6.2f ns %7.2fx%s", p.value, p.pointer, p.value/p.pointer, mark)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+
+	for _, mode := range []struct {
+		name, inlining string
+		points         []copyPoint
+	}{
+		{"Inlined", "on", inlined},
+		{"Not inlined", "off", called},
+	} {
+		for _, p := range mode.points {
+			size := strconv.Itoa(p.size)
+			out.Add("copy_call_time", p.value, "ns/op", "pass", "value", "size", size, "inlining", mode.inlining)
+			out.Add("copy_call_time", p.pointer, "ns/op", "pass", "pointer", "size", size, "inlining", mode.inlining)
+		}
+		size := crossover(mode.points)
+		out.Add("copy_crossover", float64(size), "bytes", "inlining", mode.inlining)
+		if size == 0 {
+			fmt.Fprintf(out, "%-12s no crossover up to %s: passing by value was never consistently slower\n",
+				mode.name+":", formatWorkingSet(int64(mode.points[len(mode.points)-1].size)))
+			continue
+		}
+		fmt.Fprintf(out, "%-12s pointers win from %s on\n", mode.name+":", formatWorkingSet(int64(size)))
+	}
+	fmt.Fprintln(out, "Compare these with the 16- and 100-byte rules of thumb above before relying on them.")
+}
@@ -0,0 +1,581 @@
+// Code generated by gen_copy_sizes.go; DO NOT EDIT.
+
+package topics
+
+// Copy8 is a 8-byte struct of the copy-size sweep.
+type Copy8 struct {
+	Data [8 / 8]int64
+}
+
+// AddByValue8 copies both 8-byte arguments.
+func AddByValue8(a, b Copy8) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer8 passes both arguments as pointers.
+func AddByPointer8(a, b *Copy8) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue8NoInline is AddByValue8 as a real call.
+//
+//go:noinline
+func AddByValue8NoInline(a, b Copy8) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer8NoInline is AddByPointer8 as a real call.
+//
+//go:noinline
+func AddByPointer8NoInline(a, b *Copy8) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy16 is a 16-byte struct of the copy-size sweep.
+type Copy16 struct {
+	Data [16 / 8]int64
+}
+
+// AddByValue16 copies both 16-byte arguments.
+func AddByValue16(a, b Copy16) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer16 passes both arguments as pointers.
+func AddByPointer16(a, b *Copy16) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue16NoInline is AddByValue16 as a real call.
+//
+//go:noinline
+func AddByValue16NoInline(a, b Copy16) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer16NoInline is AddByPointer16 as a real call.
+//
+//go:noinline
+func AddByPointer16NoInline(a, b *Copy16) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy32 is a 32-byte struct of the copy-size sweep.
+type Copy32 struct {
+	Data [32 / 8]int64
+}
+
+// AddByValue32 copies both 32-byte arguments.
+func AddByValue32(a, b Copy32) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer32 passes both arguments as pointers.
+func AddByPointer32(a, b *Copy32) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue32NoInline is AddByValue32 as a real call.
+//
+//go:noinline
+func AddByValue32NoInline(a, b Copy32) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer32NoInline is AddByPointer32 as a real call.
+//
+//go:noinline
+func AddByPointer32NoInline(a, b *Copy32) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy64 is a 64-byte struct of the copy-size sweep.
+type Copy64 struct {
+	Data [64 / 8]int64
+}
+
+// AddByValue64 copies both 64-byte arguments.
+func AddByValue64(a, b Copy64) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer64 passes both arguments as pointers.
+func AddByPointer64(a, b *Copy64) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue64NoInline is AddByValue64 as a real call.
+//
+//go:noinline
+func AddByValue64NoInline(a, b Copy64) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer64NoInline is AddByPointer64 as a real call.
+//
+//go:noinline
+func AddByPointer64NoInline(a, b *Copy64) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy128 is a 128-byte struct of the copy-size sweep.
+type Copy128 struct {
+	Data [128 / 8]int64
+}
+
+// AddByValue128 copies both 128-byte arguments.
+func AddByValue128(a, b Copy128) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer128 passes both arguments as pointers.
+func AddByPointer128(a, b *Copy128) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue128NoInline is AddByValue128 as a real call.
+//
+//go:noinline
+func AddByValue128NoInline(a, b Copy128) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer128NoInline is AddByPointer128 as a real call.
+//
+//go:noinline
+func AddByPointer128NoInline(a, b *Copy128) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy256 is a 256-byte struct of the copy-size sweep.
+type Copy256 struct {
+	Data [256 / 8]int64
+}
+
+// AddByValue256 copies both 256-byte arguments.
+func AddByValue256(a, b Copy256) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer256 passes both arguments as pointers.
+func AddByPointer256(a, b *Copy256) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue256NoInline is AddByValue256 as a real call.
+//
+//go:noinline
+func AddByValue256NoInline(a, b Copy256) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer256NoInline is AddByPointer256 as a real call.
+//
+//go:noinline
+func AddByPointer256NoInline(a, b *Copy256) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy512 is a 512-byte struct of the copy-size sweep.
+type Copy512 struct {
+	Data [512 / 8]int64
+}
+
+// AddByValue512 copies both 512-byte arguments.
+func AddByValue512(a, b Copy512) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer512 passes both arguments as pointers.
+func AddByPointer512(a, b *Copy512) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue512NoInline is AddByValue512 as a real call.
+//
+//go:noinline
+func AddByValue512NoInline(a, b Copy512) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer512NoInline is AddByPointer512 as a real call.
+//
+//go:noinline
+func AddByPointer512NoInline(a, b *Copy512) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy1024 is a 1024-byte struct of the copy-size sweep.
+type Copy1024 struct {
+	Data [1024 / 8]int64
+}
+
+// AddByValue1024 copies both 1024-byte arguments.
+func AddByValue1024(a, b Copy1024) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer1024 passes both arguments as pointers.
+func AddByPointer1024(a, b *Copy1024) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue1024NoInline is AddByValue1024 as a real call.
+//
+//go:noinline
+func AddByValue1024NoInline(a, b Copy1024) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer1024NoInline is AddByPointer1024 as a real call.
+//
+//go:noinline
+func AddByPointer1024NoInline(a, b *Copy1024) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy2048 is a 2048-byte struct of the copy-size sweep.
+type Copy2048 struct {
+	Data [2048 / 8]int64
+}
+
+// AddByValue2048 copies both 2048-byte arguments.
+func AddByValue2048(a, b Copy2048) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer2048 passes both arguments as pointers.
+func AddByPointer2048(a, b *Copy2048) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue2048NoInline is AddByValue2048 as a real call.
+//
+//go:noinline
+func AddByValue2048NoInline(a, b Copy2048) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer2048NoInline is AddByPointer2048 as a real call.
+//
+//go:noinline
+func AddByPointer2048NoInline(a, b *Copy2048) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// Copy4096 is a 4096-byte struct of the copy-size sweep.
+type Copy4096 struct {
+	Data [4096 / 8]int64
+}
+
+// AddByValue4096 copies both 4096-byte arguments.
+func AddByValue4096(a, b Copy4096) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer4096 passes both arguments as pointers.
+func AddByPointer4096(a, b *Copy4096) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue4096NoInline is AddByValue4096 as a real call.
+//
+//go:noinline
+func AddByValue4096NoInline(a, b Copy4096) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer4096NoInline is AddByPointer4096 as a real call.
+//
+//go:noinline
+func AddByPointer4096NoInline(a, b *Copy4096) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+var (
+	copyA8, copyB8       = Copy8{}, Copy8{}
+	copyA16, copyB16     = Copy16{}, Copy16{}
+	copyA32, copyB32     = Copy32{}, Copy32{}
+	copyA64, copyB64     = Copy64{}, Copy64{}
+	copyA128, copyB128   = Copy128{}, Copy128{}
+	copyA256, copyB256   = Copy256{}, Copy256{}
+	copyA512, copyB512   = Copy512{}, Copy512{}
+	copyA1024, copyB1024 = Copy1024{}, Copy1024{}
+	copyA2048, copyB2048 = Copy2048{}, Copy2048{}
+	copyA4096, copyB4096 = Copy4096{}, Copy4096{}
+)
+
+// CopySizes are the sizes of the sweep with loops that call their AddByValue
+// and AddByPointer functions n times.
+var CopySizes = []CopySize{
+	{
+		Size: 8,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue8(copyA8, copyB8)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer8(&copyA8, &copyB8)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue8NoInline(copyA8, copyB8)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer8NoInline(&copyA8, &copyB8)
+			}
+			return
+		},
+	},
+	{
+		Size: 16,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue16(copyA16, copyB16)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer16(&copyA16, &copyB16)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue16NoInline(copyA16, copyB16)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer16NoInline(&copyA16, &copyB16)
+			}
+			return
+		},
+	},
+	{
+		Size: 32,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue32(copyA32, copyB32)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer32(&copyA32, &copyB32)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue32NoInline(copyA32, copyB32)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer32NoInline(&copyA32, &copyB32)
+			}
+			return
+		},
+	},
+	{
+		Size: 64,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue64(copyA64, copyB64)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer64(&copyA64, &copyB64)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue64NoInline(copyA64, copyB64)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer64NoInline(&copyA64, &copyB64)
+			}
+			return
+		},
+	},
+	{
+		Size: 128,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue128(copyA128, copyB128)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer128(&copyA128, &copyB128)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue128NoInline(copyA128, copyB128)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer128NoInline(&copyA128, &copyB128)
+			}
+			return
+		},
+	},
+	{
+		Size: 256,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue256(copyA256, copyB256)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer256(&copyA256, &copyB256)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue256NoInline(copyA256, copyB256)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer256NoInline(&copyA256, &copyB256)
+			}
+			return
+		},
+	},
+	{
+		Size: 512,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue512(copyA512, copyB512)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer512(&copyA512, &copyB512)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue512NoInline(copyA512, copyB512)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer512NoInline(&copyA512, &copyB512)
+			}
+			return
+		},
+	},
+	{
+		Size: 1024,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue1024(copyA1024, copyB1024)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer1024(&copyA1024, &copyB1024)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue1024NoInline(copyA1024, copyB1024)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer1024NoInline(&copyA1024, &copyB1024)
+			}
+			return
+		},
+	},
+	{
+		Size: 2048,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue2048(copyA2048, copyB2048)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer2048(&copyA2048, &copyB2048)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue2048NoInline(copyA2048, copyB2048)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer2048NoInline(&copyA2048, &copyB2048)
+			}
+			return
+		},
+	},
+	{
+		Size: 4096,
+		ByValue: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue4096(copyA4096, copyB4096)
+			}
+			return
+		},
+		ByPointer: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer4096(&copyA4096, &copyB4096)
+			}
+			return
+		},
+		ByValueNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByValue4096NoInline(copyA4096, copyB4096)
+			}
+			return
+		},
+		ByPointerNoInline: func(n int) (sum int64) {
+			for range n {
+				sum += AddByPointer4096NoInline(&copyA4096, &copyB4096)
+			}
+			return
+		},
+	},
+}
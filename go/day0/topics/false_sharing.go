@@ -0,0 +1,235 @@
+// Package topics provides Go performance optimization demonstrations.
+package topics
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"day0/report"
+	"day0/stats"
+)
+
+func init() {
+	Register(&topic{
+		order:   12,
+		id:      "false-sharing",
+		title:   "False Sharing",
+		summary: "Counters of different cores that share a cache line slow each other down",
+		tags:    []string{"memory", "layout", "cache", "concurrency"},
+		takeaways: []string{
+			"Pad data written by different goroutines onto separate cache lines",
+			"Cores keep caches coherent per 64-byte line, not per variable",
+			"Adjacent counters can get slower, not faster, as GOMAXPROCS grows",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Parallel Counters (b.RunParallel)",
+				Names: []string{
+					"BenchmarkCounterShared",
+					"BenchmarkCounterAdjacent",
+					"BenchmarkCounterPadded",
+				},
+			},
+		},
+		demo: RunFalseSharingDemo,
+	})
+}
+
+// =============================================================================
+// FALSE SHARING
+// =============================================================================
+//
+// Caches keep copies of memory in lines of BenchCacheLine bytes. Before a core
+// writes to a line it must own it exclusively, which invalidates the copies of
+// every other core. When two goroutines on two cores write to two different
+// variables that happen to sit in the same line, the line ping-pongs between
+// the cores although they never touch each other's data: false sharing.
+//
+// ANALOGY:
+// - Adjacent counters: two cashiers sharing one till drawer, taking turns
+// - Padded counters: each cashier has a drawer of their own
+//
+// The fix is to give each writer its own cache line, at the cost of memory.
+
+// Padded holds a value alone on its cache line(s): the padding after it keeps
+// the next value in an array or struct at least BenchCacheLine bytes away.
+//
+// The padding is a full line because the size of a type parameter is not a
+// constant; Padded[int64] is 72 bytes, not 64. Some Intel CPUs prefetch lines
+// in pairs, where 128 bytes apart is needed to avoid all interference.
+type Padded[T any] struct {
+	Value T
+	_     [BenchCacheLine]byte
+}
+
+// Counters is a set of counters with one slot per worker.
+type Counters interface {
+	// Inc increments the counter of a worker.
+	Inc(worker int)
+	// Sum returns the total over all workers.
+	Sum() int64
+}
+
+// SharedCounter is a single counter every worker increments: true sharing,
+// the contended baseline.
+type SharedCounter struct {
+	n atomic.Int64
+}
+
+func (c *SharedCounter) Inc(int)    { c.n.Add(1) }
+func (c *SharedCounter) Sum() int64 { return c.n.Load() }
+
+// AdjacentCounters gives each worker its own int64, packed next to each
+// other: eight of them share one cache line, so the workers still fight.
+type AdjacentCounters struct {
+	slots []atomic.Int64
+}
+
+// NewAdjacentCounters returns counters for n workers.
+func NewAdjacentCounters(n int) *AdjacentCounters {
+	return &AdjacentCounters{slots: make([]atomic.Int64, n)}
+}
+
+func (c *AdjacentCounters) Inc(worker int) { c.slots[worker].Add(1) }
+
+func (c *AdjacentCounters) Sum() int64 {
+	var sum int64
+	for i := range c.slots {
+		sum += c.slots[i].Load()
+	}
+	return sum
+}
+
+// PaddedCounters gives each worker its own int64 on its own cache line.
+type PaddedCounters struct {
+	slots []Padded[atomic.Int64]
+}
+
+// NewPaddedCounters returns counters for n workers.
+func NewPaddedCounters(n int) *PaddedCounters {
+	return &PaddedCounters{slots: make([]Padded[atomic.Int64], n)}
+}
+
+func (c *PaddedCounters) Inc(worker int) { c.slots[worker].Value.Add(1) }
+
+func (c *PaddedCounters) Sum() int64 {
+	var sum int64
+	for i := range c.slots {
+		sum += c.slots[i].Value.Load()
+	}
+	return sum
+}
+
+// The counters are atomic on purpose: a plain c[i]++ in a loop is kept in a
+// register by the compiler and written back once, which hides the effect.
+
+// RunCounterWorkers starts one goroutine per worker, each incrementing its
+// own counter ops times, and waits for all of them.
+func RunCounterWorkers(c Counters, workers, ops int) {
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for range ops {
+				c.Inc(w)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// falseSharingOps is the number of increments per worker in the demo.
+const falseSharingOps = 200_000
+
+// gomaxprocsSteps returns the GOMAXPROCS values of the scaling table: powers
+// of two up to the number of CPUs, and the number of CPUs itself.
+func gomaxprocsSteps() []int {
+	var steps []int
+	for p := 1; p < runtime.NumCPU(); p *= 2 {
+		steps = append(steps, p)
+	}
+	return append(steps, runtime.NumCPU())
+}
+
+// RunFalseSharingDemo demonstrates false sharing between per-worker counters
+func RunFalseSharingDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                       FALSE SHARING BETWEEN CPU CORES                        ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== COUNTER LAYOUT ===")
+	adjacent := reflect.TypeFor[atomic.Int64]().Size()
+	padded := reflect.TypeFor[Padded[atomic.Int64]]().Size()
+	fmt.Fprintf(out, "Cache line size:            %d bytes\n", BenchCacheLine)
+	fmt.Fprintf(out, "Adjacent counter (int64):   %d bytes, %d per cache line\n", adjacent, BenchCacheLine/adjacent)
+	fmt.Fprintf(out, "Padded[int64] counter:      %d bytes, never two per cache line\n", padded)
+	fmt.Fprintln(out)
+	out.Add("counter_size", float64(adjacent), "bytes", "type", "AdjacentCounters")
+	out.Add("counter_size", float64(padded), "bytes", "type", "PaddedCounters")
+
+	fmt.Fprintln(out, "=== ADJACENT VS PADDED ===")
+	workers := runtime.GOMAXPROCS(0)
+	fmt.Fprintf(out, "Workers: %d (one per P), %d increments each\n", workers, falseSharingOps)
+	if runtime.NumCPU() < 2 {
+		fmt.Fprintln(out, "Only 1 CPU is available: the workers take turns on one core, so no cache")
+		fmt.Fprintln(out, "line can bounce between cores and both layouts should perform the same.")
+	}
+	adj, pad := stats.TimePair(demoSamples,
+		func() { RunCounterWorkers(NewAdjacentCounters(workers), workers, falseSharingOps) },
+		func() { RunCounterWorkers(NewPaddedCounters(workers), workers, falseSharingOps) })
+	adjTime, padTime := stats.Summarize(adj), stats.Summarize(pad)
+	fmt.Fprintf(out, "Adjacent counters: %s\n", formatTiming(adjTime))
+	fmt.Fprintf(out, "Padded counters:   %s\n", formatTiming(padTime))
+	w := strconv.Itoa(workers)
+	addTiming(out, "counter_increments", adjTime, "type", "AdjacentCounters", "workers", w)
+	addTiming(out, "counter_increments", padTime, "type", "PaddedCounters", "workers", w)
+	printSpeedup(out, "counter_increments_speedup", adj, pad, "workers", w)
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== SCALING WITH GOMAXPROCS ===")
+	fmt.Fprintln(out, "One worker per P, time per increment (lower is better). With perfect scaling")
+	fmt.Fprintln(out, "the time per increment halves each time GOMAXPROCS doubles.")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-10s | %14s | %14s | %14s | %8s\n", "GOMAXPROCS", "Shared", "Adjacent", "Padded", "Adj/Pad")
+	fmt.Fprintln(out, strings.Repeat("-", 72))
+
+	prev := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(prev)
+	for _, p := range gomaxprocsSteps() {
+		runtime.GOMAXPROCS(p)
+		perOp := func(newCounters func() Counters) stats.Summary {
+			samples := stats.Time(demoSamples/3, func() { RunCounterWorkers(newCounters(), p, falseSharingOps) })
+			for i := range samples {
+				samples[i] /= float64(p * falseSharingOps)
+			}
+			return stats.Summarize(samples)
+		}
+		shared := perOp(func() Counters { return &SharedCounter{} })
+		adj := perOp(func() Counters { return NewAdjacentCounters(p) })
+		pad := perOp(func() Counters { return NewPaddedCounters(p) })
+
+		fmt.Fprintf(out, "%-10d | %14s | %14s | %14s | %7.2fx\n", p,
+			formatPerOp(shared.Median), formatPerOp(adj.Median), formatPerOp(pad.Median), adj.Median/pad.Median)
+		procs := strconv.Itoa(p)
+		out.Add("increment_time", shared.Median, "ns/op", "type", "SharedCounter", "gomaxprocs", procs)
+		out.Add("increment_time", adj.Median, "ns/op", "type", "AdjacentCounters", "gomaxprocs", procs)
+		out.Add("increment_time", pad.Median, "ns/op", "type", "PaddedCounters", "gomaxprocs", procs)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Shared is one counter for everyone (true sharing). Adjacent counters are")
+	fmt.Fprintln(out, "logically private but physically shared, so they scale like Shared; padded")
+	fmt.Fprintln(out, "counters scale with the number of cores.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "To compare on more cores, run:")
+	fmt.Fprintln(out, "  day0 bench false-sharing")
+}
+
+// formatPerOp formats a duration in nanoseconds with two decimals.
+func formatPerOp(ns float64) string {
+	return fmt.Sprintf("%.2f ns", ns)
+}
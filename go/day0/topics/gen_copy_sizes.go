@@ -0,0 +1,161 @@
+//go:build ignore
+
+// gen_copy_sizes generates the structs, functions and benchmarks of the
+// copy-size sweep, one set per size in copySizes:
+//
+//	topics/copy_sizes_gen.go              CopyN, AddByValueN, AddByPointerN, ...
+//	benchmarks/copy_sizes_gen.go          BenchmarkCopyByValueN, ...
+//	benchmarks/copy_sizes_gen_test.go     forwarders for go test -bench
+//
+// Array lengths cannot be type parameters, so every size needs its own type.
+// Run it with go generate in the topics directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"text/template"
+)
+
+// copySizes are the struct sizes in bytes, powers of two from 8 B to 4 KB.
+var copySizes = []int{8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096}
+
+const header = "// Code generated by gen_copy_sizes.go; DO NOT EDIT.\n\n"
+
+var topicsTmpl = template.Must(template.New("topics").Parse(header + `package topics
+{{range .}}
+// Copy{{.}} is a {{.}}-byte struct of the copy-size sweep.
+type Copy{{.}} struct {
+	Data [{{.}} / 8]int64
+}
+
+// AddByValue{{.}} copies both {{.}}-byte arguments.
+func AddByValue{{.}}(a, b Copy{{.}}) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer{{.}} passes both arguments as pointers.
+func AddByPointer{{.}}(a, b *Copy{{.}}) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByValue{{.}}NoInline is AddByValue{{.}} as a real call.
+//
+//go:noinline
+func AddByValue{{.}}NoInline(a, b Copy{{.}}) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+
+// AddByPointer{{.}}NoInline is AddByPointer{{.}} as a real call.
+//
+//go:noinline
+func AddByPointer{{.}}NoInline(a, b *Copy{{.}}) int64 {
+	return a.Data[0] + b.Data[len(b.Data)-1]
+}
+{{end}}
+var (
+{{- range .}}
+	copyA{{.}}, copyB{{.}} = Copy{{.}}{}, Copy{{.}}{}
+{{- end}}
+)
+
+// CopySizes are the sizes of the sweep with loops that call their AddByValue
+// and AddByPointer functions n times.
+var CopySizes = []CopySize{
+{{- range .}}
+	{
+		Size:              {{.}},
+		ByValue:           func(n int) (sum int64) { for range n { sum += AddByValue{{.}}(copyA{{.}}, copyB{{.}}) }; return },
+		ByPointer:         func(n int) (sum int64) { for range n { sum += AddByPointer{{.}}(&copyA{{.}}, &copyB{{.}}) }; return },
+		ByValueNoInline:   func(n int) (sum int64) { for range n { sum += AddByValue{{.}}NoInline(copyA{{.}}, copyB{{.}}) }; return },
+		ByPointerNoInline: func(n int) (sum int64) { for range n { sum += AddByPointer{{.}}NoInline(&copyA{{.}}, &copyB{{.}}) }; return },
+	},
+{{- end}}
+}
+`))
+
+var benchTmpl = template.Must(template.New("bench").Parse(header + `package benchmarks
+
+import (
+	"testing"
+
+	"day0/topics"
+)
+
+func init() {
+	register(
+{{- range .}}
+		BenchmarkCopyByValue{{.}},
+		BenchmarkCopyByPointer{{.}},
+		BenchmarkCopyByValue{{.}}NoInline,
+		BenchmarkCopyByPointer{{.}}NoInline,
+{{- end}}
+	)
+}
+{{range .}}
+func BenchmarkCopyByValue{{.}}(b *testing.B) {
+	var x, y topics.Copy{{.}}
+	for b.Loop() {
+		_ = topics.AddByValue{{.}}(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer{{.}}(b *testing.B) {
+	x, y := &topics.Copy{{.}}{}, &topics.Copy{{.}}{}
+	for b.Loop() {
+		_ = topics.AddByPointer{{.}}(x, y)
+	}
+}
+
+func BenchmarkCopyByValue{{.}}NoInline(b *testing.B) {
+	var x, y topics.Copy{{.}}
+	for b.Loop() {
+		_ = topics.AddByValue{{.}}NoInline(x, y)
+	}
+}
+
+func BenchmarkCopyByPointer{{.}}NoInline(b *testing.B) {
+	x, y := &topics.Copy{{.}}{}, &topics.Copy{{.}}{}
+	for b.Loop() {
+		_ = topics.AddByPointer{{.}}NoInline(x, y)
+	}
+}
+{{end}}`))
+
+var forwardTmpl = template.Must(template.New("forward").Parse(header + `package benchmarks_test
+
+import (
+	"testing"
+
+	"day0/benchmarks"
+)
+{{range .}}
+func BenchmarkCopyByValue{{.}}(b *testing.B) { benchmarks.BenchmarkCopyByValue{{.}}(b) }
+func BenchmarkCopyByPointer{{.}}(b *testing.B) { benchmarks.BenchmarkCopyByPointer{{.}}(b) }
+func BenchmarkCopyByValue{{.}}NoInline(b *testing.B) { benchmarks.BenchmarkCopyByValue{{.}}NoInline(b) }
+func BenchmarkCopyByPointer{{.}}NoInline(b *testing.B) { benchmarks.BenchmarkCopyByPointer{{.}}NoInline(b) }
+{{end}}`))
+
+func main() {
+	for file, tmpl := range map[string]*template.Template{
+		"copy_sizes_gen.go":                    topicsTmpl,
+		"../benchmarks/copy_sizes_gen.go":      benchTmpl,
+		"../benchmarks/copy_sizes_gen_test.go": forwardTmpl,
+	} {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, copySizes); err != nil {
+			log.Fatal(err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			log.Fatalf("%s: %v\n%s", file, err, buf.Bytes())
+		}
+		if err := os.WriteFile(file, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("wrote", file)
+	}
+}
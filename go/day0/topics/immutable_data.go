@@ -4,10 +4,63 @@ package topics
 import (
 	"fmt"
 	"maps"
+	"strconv"
 	"sync"
 	"time"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   9,
+		id:      "immutable",
+		title:   "Immutable Data Sharing",
+		summary: "Sharing data between goroutines without locks",
+		tags:    []string{"concurrency"},
+		takeaways: []string{
+			"Immutable data enables safe concurrent access without locks",
+			"Updates create new values instead of modifying shared ones",
+			"Copy-on-write makes writes expensive; use it for read-heavy data",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Immutable Struct",
+				Names: []string{
+					"BenchmarkImmutableUserCreate",
+					"BenchmarkImmutableUserWithAge",
+					"BenchmarkImmutableUserWithName",
+				},
+			},
+			{
+				Title: "Immutable Map",
+				Names: []string{
+					"BenchmarkImmutableMapGet",
+					"BenchmarkImmutableMapSet",
+					"BenchmarkImmutableMapConcurrentReads",
+				},
+			},
+			{
+				Title: "Immutable Slice",
+				Names: []string{
+					"BenchmarkImmutableSliceAppend",
+					"BenchmarkImmutableSliceGet",
+					"BenchmarkImmutableSliceLen",
+				},
+			},
+			{
+				Title: "Mutable vs Immutable",
+				Names: []string{
+					"BenchmarkMutableCounterWithLock",
+					"BenchmarkAtomicCounter",
+					"BenchmarkConcurrentImmutableMapReadWrite",
+				},
+			},
+		},
+		demo: RunImmutableDemo,
+	})
+}
+
 // =============================================================================
 // IMMUTABLE DATA SHARING
 // =============================================================================
@@ -162,26 +215,26 @@ func (s *ImmutableSlice) Len() int {
 // =============================================================================
 
 // demoImmutableStruct demonstrates immutable struct usage.
-func demoImmutableStruct() {
-	fmt.Println("=== IMMUTABLE STRUCT ===")
+func demoImmutableStruct(out *report.Report) {
+	fmt.Fprintln(out, "=== IMMUTABLE STRUCT ===")
 
 	// Create an immutable user
 	user := NewImmutableUser(1, "Alice", 30, "alice@example.com")
-	fmt.Printf("Original: %+v\n", user)
+	fmt.Fprintf(out, "Original: %+v\n", user)
 
 	// "Modify" by creating a new instance
 	olderUser := user.WithAge(31)
-	fmt.Printf("After 'modification': %+v\n", olderUser)
-	fmt.Printf("Original unchanged: %+v\n", user)
+	fmt.Fprintf(out, "After 'modification': %+v\n", olderUser)
+	fmt.Fprintf(out, "Original unchanged: %+v\n", user)
 
 	// Both can be safely accessed concurrently
-	fmt.Println("Both user and olderUser can be safely accessed concurrently!")
-	fmt.Println()
+	fmt.Fprintln(out, "Both user and olderUser can be safely accessed concurrently!")
+	fmt.Fprintln(out)
 }
 
 // demoConcurrentImmutable demonstrates concurrent access with immutability.
-func demoConcurrentImmutable() {
-	fmt.Println("=== CONCURRENT ACCESS COMPARISON ===")
+func demoConcurrentImmutable(out *report.Report) {
+	fmt.Fprintln(out, "=== CONCURRENT ACCESS COMPARISON ===")
 
 	// Mutable approach (needs locking)
 	mutableCounter := struct {
@@ -211,14 +264,16 @@ func demoConcurrentImmutable() {
 
 	// Note: The immutable approach demonstration is simplified here
 	// In practice, you'd use atomic operations or lock-free structures
-	fmt.Printf("Mutable (with locks): %v\n", mutableTime)
-	fmt.Printf("Immutable (no locks for reads): Use atomic/int64 for counters")
-	fmt.Println()
+	fmt.Fprintf(out, "Mutable (with locks): %v\n", mutableTime)
+	fmt.Fprintf(out, "Immutable (no locks for reads): Use atomic/int64 for counters")
+	out.Add("mutex_counter", float64(mutableTime.Nanoseconds()), "ns",
+		"goroutines", strconv.Itoa(goroutines), "increments", strconv.Itoa(goroutines*iterations))
+	fmt.Fprintln(out)
 }
 
 // demoCopyOnWrite demonstrates copy-on-write pattern.
-func demoCopyOnWrite() {
-	fmt.Println("=== COPY-ON-WRITE PATTERN ===")
+func demoCopyOnWrite(out *report.Report) {
+	fmt.Fprintln(out, "=== COPY-ON-WRITE PATTERN ===")
 
 	slice := NewImmutableSlice()
 
@@ -229,155 +284,47 @@ func demoCopyOnWrite() {
 
 	// Reading is safe - we get a copy
 	data := slice.Get()
-	fmt.Printf("Read data: %v\n", data)
+	fmt.Fprintf(out, "Read data: %v\n", data)
 
 	// Original slice is still valid and unmodified
-	fmt.Printf("Length: %d\n", slice.Len())
-	fmt.Println("No race conditions possible!")
-	fmt.Println()
+	fmt.Fprintf(out, "Length: %d\n", slice.Len())
+	fmt.Fprintln(out, "No race conditions possible!")
+	fmt.Fprintln(out)
 }
 
 // RunImmutableDemo demonstrates all immutable patterns.
-func RunImmutableDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                    IMMUTABLE DATA SHARING DEMONSTRATION                       ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	demoImmutableStruct()
-	demoConcurrentImmutable()
-	demoCopyOnWrite()
-
-	// Run micro-benchmarks for immutable operations
-	const benchIterations = 100000
-
-	// Immutable struct benchmarks
-	userCreateStart := time.Now()
-	for range benchIterations {
-		_ = NewImmutableUser(1, "Alice", 30, "alice@example.com")
-	}
-	userCreateTime := time.Since(userCreateStart)
-	userCreateNsOp := float64(userCreateTime.Nanoseconds()) / float64(benchIterations)
-
-	user := NewImmutableUser(1, "Alice", 30, "alice@example.com")
-	userUpdateStart := time.Now()
-	for range benchIterations {
-		_ = user.WithAge(31)
-	}
-	userUpdateTime := time.Since(userUpdateStart)
-	userUpdateNsOp := float64(userUpdateTime.Nanoseconds()) / float64(benchIterations)
-
-	// Immutable map benchmarks
-	immMap := NewImmutableMap()
-	for i := range 100 {
-		immMap.Set(fmt.Sprintf("key%d", i), i)
-	}
-
-	mapGetStart := time.Now()
-	for range benchIterations {
-		_, _ = immMap.Get("key50")
-	}
-	mapGetTime := time.Since(mapGetStart)
-	mapGetNsOp := float64(mapGetTime.Nanoseconds()) / float64(benchIterations)
-
-	mapSetStart := time.Now()
-	for i := range 1000 {
-		immMap.Set(fmt.Sprintf("key%d", i+100), i)
-	}
-	mapSetTime := time.Since(mapSetStart)
-	mapSetNsOp := float64(mapSetTime.Nanoseconds()) / 1000
-
-	// Immutable slice benchmarks
-	immSlice := NewImmutableSlice()
-	sliceAppendStart := time.Now()
-	for i := range 1000 {
-		immSlice.Append(i)
-	}
-	sliceAppendTime := time.Since(sliceAppendStart)
-	sliceAppendNsOp := float64(sliceAppendTime.Nanoseconds()) / 1000
-
-	sliceReadStart := time.Now()
-	for range benchIterations {
-		_ = immSlice.Get()
-	}
-	sliceReadTime := time.Since(sliceReadStart)
-	sliceReadNsOp := float64(sliceReadTime.Nanoseconds()) / float64(benchIterations)
-
-	sliceLenStart := time.Now()
-	for range benchIterations {
-		_ = immSlice.Len()
-	}
-	sliceLenTime := time.Since(sliceLenStart)
-	sliceLenNsOp := float64(sliceLenTime.Nanoseconds()) / float64(benchIterations)
+func RunImmutableDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    IMMUTABLE DATA SHARING DEMONSTRATION                       ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
 
-	// Concurrent access benchmarks (mutex vs atomic)
-	mutableCounter := struct {
-		mu    sync.Mutex
-		value int64
-	}{}
-
-	const concurrentIters = 10000
-	const goroutines = 10
-
-	mutexStart := time.Now()
-	var wg sync.WaitGroup
-	for range goroutines {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for range concurrentIters {
-				mutableCounter.mu.Lock()
-				mutableCounter.value++
-				mutableCounter.mu.Unlock()
-			}
-		}()
-	}
-	wg.Wait()
-	mutexTime := time.Since(mutexStart)
-	mutexNsOp := float64(mutexTime.Nanoseconds()) / float64(concurrentIters*goroutines)
-
-	// Print benchmark results with actual measurements
-	fmt.Println("=== BENCHMARK RESULTS ===")
-	fmt.Println("Immutable Struct Operations:")
-	fmt.Printf("  - Create user: ~%.1f ns/op\n", userCreateNsOp)
-	fmt.Printf("  - Update age (functional): ~%.1f ns/op\n", userUpdateNsOp)
-	fmt.Println()
-	fmt.Println("Immutable Map Operations:")
-	fmt.Printf("  - Read (with lock): ~%.1f ns/op\n", mapGetNsOp)
-	fmt.Printf("  - Write (copy-on-write): ~%.0f ns/op\n", mapSetNsOp)
-	fmt.Println()
-	fmt.Println("Immutable Slice Operations:")
-	fmt.Printf("  - Append (copies array): ~%.0f ns/op\n", sliceAppendNsOp)
-	fmt.Printf("  - Read: ~%.1f ns/op\n", sliceReadNsOp)
-	fmt.Printf("  - Length: ~%.1f ns/op\n", sliceLenNsOp)
-	fmt.Println()
-	fmt.Println("Concurrent Access Comparison:")
-	fmt.Printf("  - Mutex counter: ~%.0f ns/op\n", mutexNsOp)
-	fmt.Println("  - Atomic counter: ~400 ns/op (lock-free, faster)")
-	fmt.Println()
+	demoImmutableStruct(out)
+	demoConcurrentImmutable(out)
+	demoCopyOnWrite(out)
 
 	// Explain when to use immutable data
-	fmt.Println("=== WHEN TO USE IMMUTABLE DATA ===")
-	fmt.Println("✓ Concurrent access without locks")
-	fmt.Println("✓ Functional programming patterns")
-	fmt.Println("✓ Event sourcing / CQRS architectures")
-	fmt.Println("✓ Preventing accidental mutations")
-	fmt.Println("✓ Simplified debugging (no hidden state changes)")
-	fmt.Println()
-	fmt.Println("✗ Be careful with:")
-	fmt.Println("  - Frequent modifications (copy overhead)")
-	fmt.Println("  - Large data structures (copy cost)")
-	fmt.Println("  - Memory pressure (more allocations)")
-	fmt.Println()
+	fmt.Fprintln(out, "=== WHEN TO USE IMMUTABLE DATA ===")
+	fmt.Fprintln(out, "✓ Concurrent access without locks")
+	fmt.Fprintln(out, "✓ Functional programming patterns")
+	fmt.Fprintln(out, "✓ Event sourcing / CQRS architectures")
+	fmt.Fprintln(out, "✓ Preventing accidental mutations")
+	fmt.Fprintln(out, "✓ Simplified debugging (no hidden state changes)")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "✗ Be careful with:")
+	fmt.Fprintln(out, "  - Frequent modifications (copy overhead)")
+	fmt.Fprintln(out, "  - Large data structures (copy cost)")
+	fmt.Fprintln(out, "  - Memory pressure (more allocations)")
+	fmt.Fprintln(out)
 
 	// Key insight
-	fmt.Println("=== KEY INSIGHT ===")
-	fmt.Println("Immutable data = safe to share without locks!")
-	fmt.Println()
-	fmt.Println("Trade-off:")
-	fmt.Println("  - Mutable + Locks: Lower memory, higher CPU (contention)")
-	fmt.Println("  - Immutable: Higher memory (copies), lower CPU (no contention)")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "=== KEY INSIGHT ===")
+	fmt.Fprintln(out, "Immutable data = safe to share without locks!")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Trade-off:")
+	fmt.Fprintln(out, "  - Mutable + Locks: Lower memory, higher CPU (contention)")
+	fmt.Fprintln(out, "  - Immutable: Higher memory (copies), lower CPU (no contention)")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
@@ -0,0 +1,455 @@
+// Package topics provides Go performance optimization demonstrations.
+package topics
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"day0/bench"
+	"day0/escape"
+	"day0/profiling"
+	"day0/report"
+	"day0/stats"
+)
+
+func init() {
+	Register(&topic{
+		order:   13,
+		id:      "interfaces",
+		title:   "Interface Dispatch and Boxing",
+		summary: "What calling through an interface, boxing a value and asserting a type cost",
+		tags:    []string{"methods", "allocation", "compiler"},
+		takeaways: []string{
+			"Interface calls cannot be inlined; a direct call to a small method disappears",
+			"Storing a value in an interface allocates unless it is a pointer or a small integer",
+			"Asserting a concrete type compares one word; asserting an interface looks up an itab",
+			"PGO turns hot interface calls into a type check plus a direct, inlinable call",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Method Calls (Counter.Increment)",
+				Names: []string{
+					"BenchmarkCallDirect",
+					"BenchmarkCallInterface",
+					"BenchmarkCallGeneric",
+					"BenchmarkCallDevirtualized",
+				},
+			},
+			{
+				Title: "Boxing into an Interface",
+				Names: []string{
+					"BenchmarkBoxSmallCounter",
+					"BenchmarkBoxCounter",
+					"BenchmarkBoxVector",
+					"BenchmarkBoxPointer",
+				},
+			},
+			{
+				Title: "Type Assertions and Switches (per value)",
+				Names: []string{
+					"BenchmarkAssertConcrete",
+					"BenchmarkAssertInterface",
+					"BenchmarkTypeSwitchConcrete",
+					"BenchmarkTypeSwitchInterface",
+				},
+			},
+		},
+		demo: RunInterfaceDispatchDemo,
+	})
+}
+
+// =============================================================================
+// INTERFACE DISPATCH AND BOXING
+// =============================================================================
+//
+// An interface value is two words: a pointer to an itab (the dynamic type and
+// its method table) and a pointer to the data. That has three costs:
+//
+// - Dispatch: a call loads the method from the itab and calls it indirectly,
+//   so the compiler cannot inline it.
+// - Boxing: the data word must be a pointer, so a non-pointer value is copied
+//   to the heap when it is stored in an interface that escapes.
+// - Assertions: x.(T) compares the type word; x.(I) with an interface I must
+//   find or build the itab for the pair.
+//
+// ANALOGY:
+// - Direct call: dialling a colleague's extension
+// - Interface call: calling reception and asking to be put through
+// - Devirtualization: reception noticing you always want the same person
+//   and giving you the extension
+
+// IncrementDirect calls (*Counter).Increment n times on a concrete pointer;
+// the compiler inlines the method into the loop.
+//
+//go:noinline
+func IncrementDirect(c *Counter, n int) (sum int) {
+	for range n {
+		sum += c.Increment()
+	}
+	return sum
+}
+
+// IncrementVia calls Increment n times through the interface: one indirect
+// call each, unless a PGO profile shows that inc is usually a *Counter.
+//
+//go:noinline
+func IncrementVia(inc Incrementer, n int) (sum int) {
+	for range n {
+		sum += inc.Increment()
+	}
+	return sum
+}
+
+// IncrementGeneric calls Increment n times through a type parameter. All
+// pointer types share one instantiation (GC shape), which finds the method
+// in a dictionary: an interface call in disguise.
+//
+//go:noinline
+func IncrementGeneric[T Incrementer](inc T, n int) (sum int) {
+	for range n {
+		sum += inc.Increment()
+	}
+	return sum
+}
+
+// IncrementKnown stores c in an Incrementer and calls Increment through it.
+// The compiler can see that the dynamic type is *Counter and devirtualizes
+// the calls without any profile.
+//
+//go:noinline
+func IncrementKnown(c *Counter, n int) (sum int) {
+	var inc Incrementer = c
+	for range n {
+		sum += inc.Increment()
+	}
+	return sum
+}
+
+// ValueIncrementer is implemented by Counter itself through its value
+// receiver IncrementByValue, so a Counter value can be stored in it.
+type ValueIncrementer interface {
+	IncrementByValue() int
+}
+
+// Vector is a 24-byte value type with a value receiver method.
+type Vector struct {
+	X, Y, Z int
+}
+
+// IncrementByValue returns X+1; the receiver is a copy.
+func (v Vector) IncrementByValue() int {
+	return v.X + 1
+}
+
+// boxed keeps the boxed values reachable, as storing an interface in a
+// field or a slice would.
+var boxed ValueIncrementer
+
+// BoxCounter stores a Counter value in an interface.
+//
+//go:noinline
+func BoxCounter(c Counter) { boxed = c }
+
+// BoxVector stores a Vector value in an interface.
+//
+//go:noinline
+func BoxVector(v Vector) { boxed = v }
+
+// BoxPointer stores a *Counter in an interface; the pointer is the data word.
+//
+//go:noinline
+func BoxPointer(c *Counter) { boxed = c }
+
+// NewAssertValues returns n interface values cycling through a *Counter, a
+// Vector, a string and an int, for the assertion and switch functions.
+func NewAssertValues(n int) []any {
+	kinds := []any{&Counter{value: 1}, Vector{X: 2}, "three", 4}
+	values := make([]any, n)
+	for i := range values {
+		values[i] = kinds[i%len(kinds)]
+	}
+	return values
+}
+
+// AssertConcrete sums the values that are *Counter: one comparison of the
+// type word per value.
+//
+//go:noinline
+func AssertConcrete(values []any) (sum int) {
+	for _, v := range values {
+		if c, ok := v.(*Counter); ok {
+			sum += c.value
+		}
+	}
+	return sum
+}
+
+// AssertInterface sums the values that implement ValueIncrementer: the
+// runtime looks up the itab for each dynamic type, with a cache in front.
+//
+//go:noinline
+func AssertInterface(values []any) (sum int) {
+	for _, v := range values {
+		if inc, ok := v.(ValueIncrementer); ok {
+			sum += inc.IncrementByValue()
+		}
+	}
+	return sum
+}
+
+// TypeSwitchConcrete switches on concrete types only; the compiler turns it
+// into comparisons of the type hash and the type word.
+//
+//go:noinline
+func TypeSwitchConcrete(values []any) (sum int) {
+	for _, v := range values {
+		switch v := v.(type) {
+		case *Counter:
+			sum += v.value
+		case Vector:
+			sum += v.X
+		case string:
+			sum += len(v)
+		case int:
+			sum += v
+		}
+	}
+	return sum
+}
+
+// TypeSwitchInterface switches on interface types, each case an itab lookup
+// until one matches.
+//
+//go:noinline
+func TypeSwitchInterface(values []any) (sum int) {
+	for _, v := range values {
+		switch v := v.(type) {
+		case Incrementer:
+			sum += v.Increment()
+		case ValueIncrementer:
+			sum += v.IncrementByValue()
+		case fmt.Stringer:
+			sum += len(v.String())
+		case error:
+			sum += len(v.Error())
+		}
+	}
+	return sum
+}
+
+const (
+	// interfaceCalls is the number of calls per sample of the call timings.
+	interfaceCalls = 1_000_000
+	// assertValues is the number of values per sample of the assertion timings.
+	assertValues = 1 << 16
+	// pgoProfileTime is how long the interface call is profiled for PGO.
+	pgoProfileTime = time.Second
+)
+
+// allocsPerCall returns the heap allocations and bytes per call of f,
+// averaged over n calls.
+func allocsPerCall(n int, f func()) (allocs, bytes float64) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range n {
+		f()
+	}
+	runtime.ReadMemStats(&after)
+	return float64(after.Mallocs-before.Mallocs) / float64(n), float64(after.TotalAlloc-before.TotalAlloc) / float64(n)
+}
+
+// RunInterfaceDispatchDemo demonstrates interface dispatch, boxing, type
+// assertions and devirtualization.
+func RunInterfaceDispatchDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    INTERFACE DISPATCH AND BOXING                              ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	printMethodCalls(out)
+	printBoxing(out)
+	printAssertions(out)
+	printDevirtualization(out)
+
+	fmt.Fprintln(out, "================================================================================")
+}
+
+// printMethodCalls times (*Counter).Increment called directly, through
+// Incrementer, through a type parameter and through a devirtualized interface.
+func printMethodCalls(out *report.Report) {
+	fmt.Fprintln(out, "=== METHOD CALLS ===")
+	fmt.Fprintf(out, "Time per call of (*Counter).Increment, %d calls per sample:\n", interfaceCalls)
+	c := &Counter{}
+	var sink int
+	calls := []struct {
+		kind, note string
+		run        func()
+	}{
+		{"direct", "inlined into the loop", func() { sink += IncrementDirect(c, interfaceCalls) }},
+		{"interface", "indirect call through the itab", func() { sink += IncrementVia(c, interfaceCalls) }},
+		{"generic", "shared pointer-shape code, dictionary call", func() { sink += IncrementGeneric(c, interfaceCalls) }},
+		{"devirtualized", "interface the compiler sees through", func() { sink += IncrementKnown(c, interfaceCalls) }},
+	}
+	var direct float64
+	for _, call := range calls {
+		s := stats.Summarize(stats.Time(demoSamples, call.run))
+		perCall := s.Median / interfaceCalls
+		if direct == 0 {
+			direct = perCall
+		}
+		fmt.Fprintf(out, "  %-14s %6.2f ns  %5.1fx   %s\n", call.kind, perCall, perCall/direct, call.note)
+		out.Add("call_time", perCall, "ns/op", "call", call.kind)
+	}
+	_ = sink
+	fmt.Fprintln(out)
+}
+
+// printBoxing measures the allocations of storing values in an interface.
+func printBoxing(out *report.Report) {
+	fmt.Fprintln(out, "=== BOXING ===")
+	fmt.Fprintln(out, "Storing a value in an interface that outlives the call:")
+	const n = 10_000
+	for _, b := range []struct {
+		name, note string
+		box        func()
+	}{
+		{"Counter{7}", "small integers share a static table", func() { BoxCounter(Counter{value: 7}) }},
+		{"Counter{1000}", "copied to an 8-byte heap object", func() { BoxCounter(Counter{value: 1000}) }},
+		{"Vector{1, 2, 3}", "copied to a 24-byte heap object", func() { BoxVector(Vector{1, 2, 3}) }},
+		{"&Counter{}", "the pointer is the data word", func() { BoxPointer(&boxCounter) }},
+	} {
+		allocs, bytes := allocsPerCall(n, b.box)
+		fmt.Fprintf(out, "  %-16s %4.1f allocs %5.0f B   %s\n", b.name, allocs, bytes, b.note)
+		out.Add("box_allocs", allocs, "allocs/op", "value", b.name)
+		out.Add("box_bytes", bytes, "B/op", "value", b.name)
+	}
+	fmt.Fprintln(out, "Value receivers make a type's values implement the interface, and every")
+	fmt.Fprintln(out, "conversion of such a value to the interface copies it.")
+	fmt.Fprintln(out)
+}
+
+// boxCounter is the Counter BoxPointer stores, so only the boxing is measured.
+var boxCounter Counter
+
+// printAssertions times type assertions and type switches per value.
+func printAssertions(out *report.Report) {
+	fmt.Fprintln(out, "=== TYPE ASSERTIONS AND SWITCHES ===")
+	fmt.Fprintf(out, "Time per value over %d values of 4 dynamic types:\n", assertValues)
+	values := NewAssertValues(assertValues)
+	var sink int
+	for _, a := range []struct {
+		kind string
+		run  func([]any) int
+	}{
+		{"v.(*Counter)", AssertConcrete},
+		{"v.(ValueIncrementer)", AssertInterface},
+		{"switch, concrete cases", TypeSwitchConcrete},
+		{"switch, interface cases", TypeSwitchInterface},
+	} {
+		s := stats.Summarize(stats.Time(demoSamples, func() { sink += a.run(values) }))
+		perValue := s.Median / assertValues
+		fmt.Fprintf(out, "  %-24s %6.2f ns\n", a.kind, perValue)
+		out.Add("assert_time", perValue, "ns/op", "assertion", a.kind)
+	}
+	_ = sink
+	fmt.Fprintln(out)
+}
+
+// printDevirtualization shows the compiler devirtualizing IncrementKnown on
+// its own, then profiles IncrementVia, compiles the package with the profile
+// and benchmarks the interface call with and without it.
+func printDevirtualization(out *report.Report) {
+	fmt.Fprintln(out, "=== DEVIRTUALIZATION ===")
+	fmt.Fprintln(out, "Without a profile the compiler only devirtualizes when it can prove the")
+	fmt.Fprintln(out, "dynamic type, as in IncrementKnown:")
+	diags, err := escapeAnalysis()
+	if err != nil {
+		fmt.Fprintln(out, "  Not available: run the demo from the module directory with the Go toolchain installed.")
+		fmt.Fprintln(out)
+		return
+	}
+	printDevirtualized(out, diags, "IncrementKnown", "static")
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "With PGO: profiling IncrementVia for %v, then compiling the package with the profile.\n", pgoProfileTime)
+	pkg := reflect.TypeFor[Counter]().PkgPath()
+	profile, err := profileInterfaceCalls()
+	if err == nil {
+		diags, err = escape.AnalyzeProfile(pkg, profile)
+	}
+	if err != nil {
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		fmt.Fprintf(out, "  Not available: %s\n", msg)
+		fmt.Fprintln(out)
+		return
+	}
+	defer os.RemoveAll(path.Dir(profile))
+	printDevirtualized(out, diags, "IncrementVia", "pgo")
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "BenchmarkCallInterface, go test without and with the profile:")
+	e := bench.Engine{BenchTime: 200 * time.Millisecond, Count: 5}
+	benchPkg := path.Join(path.Dir(pkg), "benchmarks")
+	const pattern = "^BenchmarkCallInterface$"
+	without, err := e.GoTest(benchPkg, pattern)
+	var with []bench.Result
+	if err == nil {
+		with, err = e.GoTest(benchPkg, pattern, escape.ProfileGcflags(pkg, profile))
+	}
+	if err != nil || len(without) != 1 || len(with) != 1 {
+		fmt.Fprintln(out, "  Not available: go test did not report the benchmark")
+		fmt.Fprintln(out)
+		return
+	}
+	before, after := without[0].Time(), with[0].Time()
+	fmt.Fprintf(out, "  without PGO: %6.2f ns/op\n", before.Median)
+	fmt.Fprintf(out, "  with PGO:    %6.2f ns/op\n", after.Median)
+	out.Add("call_time", before.Median, "ns/op", "call", "interface", "pgo", "off")
+	out.Add("call_time", after.Median, "ns/op", "call", "interface", "pgo", "on")
+	printSpeedup(out, "pgo_speedup", without[0].Samples, with[0].Samples, "call", "interface")
+	fmt.Fprintln(out, "The devirtualized call checks the type word and calls (*Counter).Increment")
+	fmt.Fprintln(out, "directly, inlined; any other type still takes the interface call.")
+	fmt.Fprintln(out)
+}
+
+// printDevirtualized prints the devirtualization diagnostics of a function.
+func printDevirtualized(out *report.Report, diags []escape.Diagnostic, function, via string) {
+	shown := 0
+	for _, d := range escape.ForFunction(diags, function) {
+		if d.Kind != escape.Devirtualized {
+			continue
+		}
+		shown++
+		fmt.Fprintf(out, "  %-28s %s\n", d.Pos(), d.Message)
+		out.Add("devirtualized_call", float64(d.Line), "line", "function", function, "call", d.Variable, "via", via)
+	}
+	if shown == 0 {
+		fmt.Fprintf(out, "  %s: no call was devirtualized\n", function)
+	}
+}
+
+// profileInterfaceCalls records a CPU profile of IncrementVia on a *Counter
+// and returns the name of the pprof file, in a new temporary directory.
+func profileInterfaceCalls() (string, error) {
+	dir, err := os.MkdirTemp("", "day0-pgo-")
+	if err != nil {
+		return "", err
+	}
+	p := &profiling.Profiler{Kind: profiling.CPU, Dir: dir}
+	c := &Counter{}
+	s, err := p.Capture("interfaces", func() {
+		for start := time.Now(); time.Since(start) < pgoProfileTime; {
+			IncrementVia(c, interfaceCalls)
+		}
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return s.File, nil
+}
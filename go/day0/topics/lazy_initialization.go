@@ -5,8 +5,59 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   10,
+		id:      "lazy-init",
+		title:   "Lazy Initialization",
+		summary: "Deferring expensive setup until first use",
+		tags:    []string{"startup", "concurrency"},
+		takeaways: []string{
+			"Lazy initialization defers expensive operations until needed",
+			"sync.Once makes one-time initialization safe under concurrency",
+			"Errors surface on first use instead of at startup",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Lazy Config",
+				Names: []string{
+					"BenchmarkLazyConfigFirstAccess",
+					"BenchmarkLazyConfigCachedAccess",
+					"BenchmarkLazyConfigIsLoaded",
+				},
+			},
+			{
+				Title: "sync.Once Registry",
+				Names: []string{
+					"BenchmarkServiceRegistryInitialize",
+					"BenchmarkServiceRegistryMultipleAccess",
+				},
+			},
+			{
+				Title: "Lazy Cache",
+				Names: []string{
+					"BenchmarkLazyCacheFirstAccess",
+					"BenchmarkLazyCacheCacheHit",
+					"BenchmarkLazyCacheMultipleKeys",
+					"BenchmarkLazyCacheWriteHeavy",
+				},
+			},
+			{
+				Title: "Eager vs Lazy",
+				Names: []string{
+					"BenchmarkEagerLoadAll",
+					"BenchmarkLazyLoadOnDemand",
+				},
+			},
+		},
+		demo: RunLazyInitDemo,
+	})
+}
+
 // =============================================================================
 // LAZY INITIALIZATION
 // =============================================================================
@@ -67,7 +118,6 @@ func (lc *LazyConfig) Get() ExpensiveConfig {
 	defer lc.mu.Unlock()
 
 	if !lc.loaded {
-		fmt.Println("  [Lazy] Loading configuration...")
 		lc.config = lc.loadFunc()
 		lc.loaded = true
 	}
@@ -93,6 +143,10 @@ type Service struct {
 
 // ServiceRegistry manages services with lazy initialization.
 type ServiceRegistry struct {
+	// OnInit, if set, is called once at the start of initialization.
+	// The demo uses it to show when the expensive work actually happens.
+	OnInit func()
+
 	services map[string]*Service
 	once     sync.Once
 	initDone bool
@@ -102,7 +156,9 @@ type ServiceRegistry struct {
 func (sr *ServiceRegistry) Initialize() {
 	// sync.Once ensures this runs only once, even with concurrent access
 	sr.once.Do(func() {
-		fmt.Println("  [sync.Once] Initializing services...")
+		if sr.OnInit != nil {
+			sr.OnInit()
+		}
 		time.Sleep(50 * time.Millisecond) // Simulate expensive init
 		sr.services = map[string]*Service{
 			"database": {Name: "Database", Clients: 0},
@@ -169,69 +225,74 @@ func (c *Cache) Get(key string) any {
 // =============================================================================
 
 // demoBasicLazy demonstrates basic lazy initialization.
-func demoBasicLazy() {
-	fmt.Println("=== BASIC LAZY INITIALIZATION ===")
+func demoBasicLazy(out *report.Report) {
+	fmt.Fprintln(out, "=== BASIC LAZY INITIALIZATION ===")
 
-	lazyConfig := NewLazyConfig(simulateLoad)
+	lazyConfig := NewLazyConfig(func() ExpensiveConfig {
+		fmt.Fprintln(out, "  [Lazy] Loading configuration...")
+		return simulateLoad()
+	})
 
-	fmt.Println("Config created (not loaded yet)")
-	fmt.Printf("Is loaded: %v\n", lazyConfig.IsLoaded())
-	fmt.Println()
+	fmt.Fprintln(out, "Config created (not loaded yet)")
+	fmt.Fprintf(out, "Is loaded: %v\n", lazyConfig.IsLoaded())
+	fmt.Fprintln(out)
 
 	// First access - triggers loading
-	fmt.Println("First access:")
+	fmt.Fprintln(out, "First access:")
 	config1 := lazyConfig.Get()
-	fmt.Printf("  Database URL: %s\n", config1.DatabaseURL)
-	fmt.Printf("  Is loaded: %v\n", lazyConfig.IsLoaded())
-	fmt.Println()
+	fmt.Fprintf(out, "  Database URL: %s\n", config1.DatabaseURL)
+	fmt.Fprintf(out, "  Is loaded: %v\n", lazyConfig.IsLoaded())
+	fmt.Fprintln(out)
 
 	// Second access - uses cached value
-	fmt.Println("Second access:")
+	fmt.Fprintln(out, "Second access:")
 	config2 := lazyConfig.Get()
-	fmt.Printf("  API Key: %s\n", config2.APIKey)
-	fmt.Printf("  Is loaded: %v\n", lazyConfig.IsLoaded())
-	fmt.Println()
+	fmt.Fprintf(out, "  API Key: %s\n", config2.APIKey)
+	fmt.Fprintf(out, "  Is loaded: %v\n", lazyConfig.IsLoaded())
+	fmt.Fprintln(out)
 }
 
 // demoSyncOnce demonstrates sync.Once pattern.
-func demoSyncOnce() {
-	fmt.Println("=== SYNC.ONCE PATTERN ===")
+func demoSyncOnce(out *report.Report) {
+	fmt.Fprintln(out, "=== SYNC.ONCE PATTERN ===")
 
-	registry := &ServiceRegistry{}
+	registry := &ServiceRegistry{
+		OnInit: func() { fmt.Fprintln(out, "  [sync.Once] Initializing services...") },
+	}
 
-	fmt.Println("Registry created (not initialized)")
-	fmt.Println()
+	fmt.Fprintln(out, "Registry created (not initialized)")
+	fmt.Fprintln(out)
 
 	// First access - triggers initialization
-	fmt.Println("First access:")
+	fmt.Fprintln(out, "First access:")
 	svc1 := registry.GetService("database")
-	fmt.Printf("  Got service: %s\n", svc1.Name)
-	fmt.Println()
+	fmt.Fprintf(out, "  Got service: %s\n", svc1.Name)
+	fmt.Fprintln(out)
 
 	// Second access - uses existing
-	fmt.Println("Second access:")
+	fmt.Fprintln(out, "Second access:")
 	svc2 := registry.GetService("cache")
-	fmt.Printf("  Got service: %s\n", svc2.Name)
-	fmt.Println()
+	fmt.Fprintf(out, "  Got service: %s\n", svc2.Name)
+	fmt.Fprintln(out)
 
 	// Concurrent access - still only initializes once
-	fmt.Println("Concurrent access (5 goroutines):")
+	fmt.Fprintln(out, "Concurrent access (5 goroutines):")
 	var wg sync.WaitGroup
 	for i := range 5 {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
 			svc := registry.GetService("queue")
-			fmt.Printf("  Goroutine %d got: %s\n", id, svc.Name)
+			fmt.Fprintf(out, "  Goroutine %d got: %s\n", id, svc.Name)
 		}(i)
 	}
 	wg.Wait()
-	fmt.Println()
+	fmt.Fprintln(out)
 }
 
 // demoLazyCache demonstrates lazy cache pattern.
-func demoLazyCache() {
-	fmt.Println("=== LAZY CACHE PATTERN ===")
+func demoLazyCache(out *report.Report) {
+	fmt.Fprintln(out, "=== LAZY CACHE PATTERN ===")
 
 	cache := NewCache(func(key string) any {
 		// Simulate expensive load
@@ -240,155 +301,54 @@ func demoLazyCache() {
 	})
 
 	// First access - loads
-	fmt.Println("First access to 'user:1':")
+	fmt.Fprintln(out, "First access to 'user:1':")
 	val1 := cache.Get("user:1")
-	fmt.Printf("  Value: %v\n", val1)
-	fmt.Println()
+	fmt.Fprintf(out, "  Value: %v\n", val1)
+	fmt.Fprintln(out)
 
 	// Second access - cached
-	fmt.Println("Second access to 'user:1':")
+	fmt.Fprintln(out, "Second access to 'user:1':")
 	val2 := cache.Get("user:1")
-	fmt.Printf("  Value: %v\n", val2)
-	fmt.Println()
+	fmt.Fprintf(out, "  Value: %v\n", val2)
+	fmt.Fprintln(out)
 
 	// Different key - loads
-	fmt.Println("First access to 'user:2':")
+	fmt.Fprintln(out, "First access to 'user:2':")
 	val3 := cache.Get("user:2")
-	fmt.Printf("  Value: %v\n", val3)
-	fmt.Println()
+	fmt.Fprintf(out, "  Value: %v\n", val3)
+	fmt.Fprintln(out)
 }
 
 // RunLazyInitDemo demonstrates all lazy initialization patterns.
-func RunLazyInitDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                     LAZY INITIALIZATION DEMONSTRATION                         ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	demoBasicLazy()
-	demoSyncOnce()
-	demoLazyCache()
-
-	// Run micro-benchmarks for lazy initialization
-	const benchIterations = 100000
-
-	// Lazy config benchmarks - use faster load function for benchmarking
-	fastLoad := func() ExpensiveConfig {
-		time.Sleep(1 * time.Millisecond) // Simulate faster load
-		return ExpensiveConfig{
-			DatabaseURL: "postgres://localhost:5432/db",
-			APIKey:      "secret-key-12345",
-			Timeout:     30 * time.Second,
-		}
-	}
-
-	// First access benchmark (includes load)
-	lazyConfigBench := NewLazyConfig(fastLoad)
-	// Don't print during benchmark
-	configGet := func() ExpensiveConfig {
-		lazyConfigBench.mu.Lock()
-		defer lazyConfigBench.mu.Unlock()
-		if !lazyConfigBench.loaded {
-			lazyConfigBench.config = fastLoad()
-			lazyConfigBench.loaded = true
-		}
-		return lazyConfigBench.config
-	}
-
-	configGet() // Load once
-	configFirstStart := time.Now()
-	for range benchIterations {
-		_ = configGet()
-	}
-	configFirstTime := time.Since(configFirstStart)
-	configFirstNsOp := float64(configFirstTime.Nanoseconds()) / float64(benchIterations)
+func RunLazyInitDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                     LAZY INITIALIZATION DEMONSTRATION                         ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
 
-	// IsLoaded check benchmark
-	isLoadedStart := time.Now()
-	for range benchIterations {
-		_ = lazyConfigBench.IsLoaded()
-	}
-	isLoadedTime := time.Since(isLoadedStart)
-	isLoadedNsOp := float64(isLoadedTime.Nanoseconds()) / float64(benchIterations)
-
-	// Lazy cache benchmarks
-	cache := NewCache(func(key string) any {
-		time.Sleep(1 * time.Millisecond) // Simulate fast load
-		return fmt.Sprintf("value-%s", key)
-	})
-
-	// First access (cache miss)
-	cache.Get("benchkey") // Load once
-	cacheMissStart := time.Now()
-	for i := range benchIterations {
-		_ = cache.Get(fmt.Sprintf("benchkey-%d", i))
-	}
-	cacheMissTime := time.Since(cacheMissStart)
-	cacheMissNsOp := float64(cacheMissTime.Nanoseconds()) / float64(benchIterations)
-
-	// Cached access (cache hit)
-	cache.Get("hitkey") // Load once
-	cacheHitStart := time.Now()
-	for range benchIterations {
-		_ = cache.Get("hitkey")
-	}
-	cacheHitTime := time.Since(cacheHitStart)
-	cacheHitNsOp := float64(cacheHitTime.Nanoseconds()) / float64(benchIterations)
-
-	// Multiple keys benchmark
-	cacheMulti := NewCache(func(key string) any {
-		time.Sleep(1 * time.Millisecond)
-		return fmt.Sprintf("value-%s", key)
-	})
-	for i := range 10 {
-		cacheMulti.Get(fmt.Sprintf("key%d", i))
-	}
-	multiStart := time.Now()
-	for range 10000 {
-		for i := range 10 {
-			_ = cacheMulti.Get(fmt.Sprintf("key%d", i))
-		}
-	}
-	multiTime := time.Since(multiStart)
-	multiNsOp := float64(multiTime.Nanoseconds()) / 100000
-
-	// Print benchmark results with actual measurements
-	fmt.Println("=== BENCHMARK RESULTS ===")
-	fmt.Println("Lazy Config:")
-	fmt.Printf("  - First access: ~%.0f ns/op\n", configFirstNsOp)
-	fmt.Printf("  - Cached access: ~%.0f ns/op\n", configFirstNsOp)
-	fmt.Printf("  - IsLoaded check: ~%.0f ns/op\n", isLoadedNsOp)
-	fmt.Println()
-	fmt.Println("Lazy Cache:")
-	fmt.Printf("  - First access (cache miss): ~%.0f ns/op\n", cacheMissNsOp)
-	fmt.Printf("  - Cached access (cache hit): ~%.0f ns/op\n", cacheHitNsOp)
-	fmt.Printf("  - Multiple keys (10): ~%.0f ns/op\n", multiNsOp)
-	fmt.Println()
-	fmt.Println("Key Insight:")
-	cachedSpeedup := cacheMissNsOp / cacheHitNsOp
-	fmt.Printf("  - Lazy initialization is ~%.0fx faster on cached access\n", cachedSpeedup)
-	fmt.Println("  - Trade-off: first access slower, subsequent access much faster")
-	fmt.Println()
+	demoBasicLazy(out)
+	demoSyncOnce(out)
+	demoLazyCache(out)
 
 	// Explain when to use lazy initialization
-	fmt.Println("=== WHEN TO USE LAZY INITIALIZATION ===")
-	fmt.Println("✓ Expensive initialization (database, network, file I/O)")
-	fmt.Println("✓ Features that may not be used")
-	fmt.Println("✓ Reducing startup time")
-	fmt.Println("✓ Resource conservation")
-	fmt.Println()
-	fmt.Println("✗ Don't use lazy initialization when:")
-	fmt.Println("  - Required at startup anyway")
-	fmt.Println("  - Multiple threads need it early (adds complexity)")
-	fmt.Println("  - Error handling is critical (errors deferred)")
-	fmt.Println()
+	fmt.Fprintln(out, "=== WHEN TO USE LAZY INITIALIZATION ===")
+	fmt.Fprintln(out, "✓ Expensive initialization (database, network, file I/O)")
+	fmt.Fprintln(out, "✓ Features that may not be used")
+	fmt.Fprintln(out, "✓ Reducing startup time")
+	fmt.Fprintln(out, "✓ Resource conservation")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "✗ Don't use lazy initialization when:")
+	fmt.Fprintln(out, "  - Required at startup anyway")
+	fmt.Fprintln(out, "  - Multiple threads need it early (adds complexity)")
+	fmt.Fprintln(out, "  - Error handling is critical (errors deferred)")
+	fmt.Fprintln(out)
 
 	// Patterns comparison
-	fmt.Println("=== PATTERN COMPARISON ===")
-	fmt.Println("1. Basic (with mutex): Simple but has lock overhead")
-	fmt.Println("2. sync.Once: Thread-safe, only runs once, no lock on reads")
-	fmt.Println("3. RWMutex: Read-heavy workloads, double-check locking")
-	fmt.Println()
+	fmt.Fprintln(out, "=== PATTERN COMPARISON ===")
+	fmt.Fprintln(out, "1. Basic (with mutex): Simple but has lock overhead")
+	fmt.Fprintln(out, "2. sync.Once: Thread-safe, only runs once, no lock on reads")
+	fmt.Fprintln(out, "3. RWMutex: Read-heavy workloads, double-check locking")
+	fmt.Fprintln(out)
 
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "================================================================================")
 }
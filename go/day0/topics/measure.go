@@ -0,0 +1,107 @@
+package topics
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"day0/gcstats"
+	"day0/report"
+	"day0/stats"
+)
+
+// =============================================================================
+// DEMO TIMINGS
+// =============================================================================
+//
+// A single time.Since around a loop swings by tens of percent between runs, so
+// the demos take several samples of every timing they print, report the median
+// with a 95% confidence interval, and only claim a speedup when a Mann-Whitney
+// U test says the difference is real.
+
+// demoSamples is the number of samples behind every timing a demo prints.
+const demoSamples = 15
+
+// formatTiming formats a summary as "1.2ms ± 3% (median of 15, 1 outlier dropped)".
+func formatTiming(s stats.Summary) string {
+	text := fmt.Sprintf("%v ± %.0f%% (median of %d", time.Duration(s.Median), s.Spread(), s.N)
+	switch len(s.Outliers) {
+	case 0:
+		return text + ")"
+	case 1:
+		return text + ", 1 outlier dropped)"
+	}
+	return fmt.Sprintf("%s, %d outliers dropped)", text, len(s.Outliers))
+}
+
+// addTiming records the median of a summary together with its confidence interval.
+func addTiming(out *report.Report, metric string, s stats.Summary, params ...string) {
+	params = append(params,
+		"samples", strconv.Itoa(s.N),
+		"ci95_low", strconv.FormatFloat(s.CILow, 'f', 0, 64),
+		"ci95_high", strconv.FormatFloat(s.CIHigh, 'f', 0, 64))
+	out.Add(metric, s.Median, "ns", params...)
+}
+
+// printSpeedup compares the samples of a baseline and an improved variant.
+// It prints the ratio of the medians when the difference is significant -
+// as a slowdown if the "improved" variant lost - and says so plainly when it
+// is not, then records the ratio with its p-value.
+func printSpeedup(out *report.Report, metric string, baseline, improved []float64, params ...string) {
+	ratio := stats.Summarize(baseline).Median / stats.Summarize(improved).Median
+	p := stats.MannWhitneyU(baseline, improved)
+	switch {
+	case stats.Significant(p) && ratio >= 1:
+		fmt.Fprintf(out, "Speedup: %.2fx (p=%.3f, Mann-Whitney U)\n", ratio, p)
+	case stats.Significant(p):
+		fmt.Fprintf(out, "Slowdown: %.2fx - slower here, not faster (p=%.3f, Mann-Whitney U)\n", 1/ratio, p)
+	default:
+		fmt.Fprintf(out, "No significant difference (p=%.3f, Mann-Whitney U); the %.2fx ratio is noise\n", p, ratio)
+	}
+
+	params = append(params,
+		"p_value", strconv.FormatFloat(p, 'g', 4, 64),
+		"significant", strconv.FormatBool(stats.Significant(p)))
+	out.Add(metric, ratio, "x", params...)
+}
+
+// =============================================================================
+// GC PRESSURE
+// =============================================================================
+//
+// Timings show GC pressure only indirectly, so the allocation-heavy demos also
+// run each workload once under gcstats.Measure and print what it cost the GC.
+
+// gcWorkload is one row of a GC pressure table.
+type gcWorkload struct {
+	name string
+	run  func()
+}
+
+// printGCPressure runs each workload once, after a forced GC so that all
+// start from the same heap, and prints and records the GC activity it caused.
+func printGCPressure(out *report.Report, workloads []gcWorkload, params ...string) {
+	fmt.Fprintf(out, "%-16s | %9s | %10s | %10s | %10s | %10s\n", "Workload", "GC cycles", "Paused", "GC CPU", "Allocated", "Objects")
+	fmt.Fprintln(out, strings.Repeat("-", 81))
+	for _, w := range workloads {
+		runtime.GC()
+		d := gcstats.Measure(w.run)
+		fmt.Fprintf(out, "%-16s | %9d | %10v | %10v | %10s | %10d\n", w.name, d.Cycles,
+			d.Pause.Round(time.Microsecond), d.CPU.Round(time.Microsecond), formatWorkingSet(int64(d.AllocBytes)), d.AllocObjects)
+		addGC(out, d, append(params, "workload", w.name)...)
+	}
+	fmt.Fprintln(out, "Paused is stop-the-world time; GC CPU adds the concurrent marking that")
+	fmt.Fprintln(out, "competes with the application for cores.")
+}
+
+// addGC records the GC activity of a workload.
+func addGC(out *report.Report, d gcstats.Delta, params ...string) {
+	out.Add("gc_cycles", float64(d.Cycles), "count", params...)
+	out.Add("gc_pause", float64(d.Pause), "ns", params...)
+	out.Add("gc_cpu", float64(d.CPU), "ns", params...)
+	out.Add("heap_alloc", float64(d.AllocBytes), "bytes", params...)
+	out.Add("heap_alloc_objects", float64(d.AllocObjects), "count", params...)
+	out.Add("heap_objects_delta", float64(d.Objects), "count", params...)
+}
@@ -3,9 +3,53 @@ package topics
 
 import (
 	"fmt"
-	"time"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   11,
+		id:      "preallocation",
+		title:   "Memory Preallocation",
+		summary: "Preallocating slices and maps to avoid regrowth",
+		tags:    []string{"allocation", "memory"},
+		takeaways: []string{
+			"Preallocate slices and maps when size is known",
+			"Growing a slice reallocates and copies its backing array",
+			"A map size hint avoids incremental rehashing",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Slice Growth",
+				Names: []string{
+					"BenchmarkDynamicSliceSmall",
+					"BenchmarkPreallocatedSliceSmall",
+					"BenchmarkPreallocatedSliceExactSmall",
+					"BenchmarkDynamicSliceMedium",
+					"BenchmarkPreallocatedSliceMedium",
+					"BenchmarkPreallocatedSliceExactMedium",
+					"BenchmarkDynamicSliceLarge",
+					"BenchmarkPreallocatedSliceLarge",
+					"BenchmarkPreallocatedSliceExactLarge",
+				},
+			},
+			{
+				Title: "Map Growth",
+				Names: []string{
+					"BenchmarkDynamicMapSmall",
+					"BenchmarkPreallocatedMapSmall",
+					"BenchmarkDynamicMapMedium",
+					"BenchmarkPreallocatedMapMedium",
+					"BenchmarkDynamicMapLarge",
+					"BenchmarkPreallocatedMapLarge",
+				},
+			},
+		},
+		demo: RunMemoryPreallocationDemo,
+	})
+}
+
 // =============================================================================
 // MEMORY PREALLOCATION
 // =============================================================================
@@ -112,131 +156,61 @@ func PreallocatedMap(n int) map[string]int {
 	return m
 }
 
-// =============================================================================
-// PERFORMANCE COMPARISON
-// =============================================================================
-
-// benchmarkSliceComparison compares dynamic vs preallocated slice growth.
-func benchmarkSliceComparison(size int) (dynamicTime, preallocatedTime time.Duration) {
-	// Warm up
-	DynamicSlice(100)
-	PreallocatedSlice(100)
-
-	// Test dynamic growth
-	start := time.Now()
-	for range 1000 {
-		_ = DynamicSlice(size)
-	}
-	dynamicTime = time.Since(start)
-
-	// Test preallocated growth
-	start = time.Now()
-	for range 1000 {
-		_ = PreallocatedSlice(size)
-	}
-	preallocatedTime = time.Since(start)
-
-	return
-}
-
-// benchmarkMapComparison compares dynamic vs preallocated map growth.
-func benchmarkMapComparison(size int) (dynamicTime, preallocatedTime time.Duration) {
-	// Warm up
-	DynamicMap(100)
-	PreallocatedMap(100)
-
-	// Test dynamic growth
-	start := time.Now()
-	for range 1000 {
-		_ = DynamicMap(size)
-	}
-	dynamicTime = time.Since(start)
-
-	// Test preallocated growth
-	start = time.Now()
-	for range 1000 {
-		_ = PreallocatedMap(size)
-	}
-	preallocatedTime = time.Since(start)
-
-	return
-}
-
 // =============================================================================
 // DEMONSTRATION
 // =============================================================================
 
 // RunMemoryPreallocationDemo demonstrates the performance impact of preallocation.
-func RunMemoryPreallocationDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                MEMORY PREALLOCATION DEMONSTRATION                              ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	fmt.Println("WHAT IS PREALLOCATION?")
-	fmt.Println("Preallocating memory for slices and maps before adding elements.")
-	fmt.Println()
-	fmt.Println("DYNAMIC GROWTH (without preallocation):")
-	fmt.Println("  - Start with small capacity")
-	fmt.Println("  - Trigger reallocation when capacity exceeded")
-	fmt.Println("  - Each reallocation: copy all data to new location")
-	fmt.Println("  - More allocations = more GC pressure")
-	fmt.Println()
-	fmt.Println("PREALLOCATION (with capacity hint):")
-	fmt.Println("  - Allocate capacity upfront")
-	fmt.Println("  - No reallocations during growth")
-	fmt.Println("  - Single allocation, better performance")
-	fmt.Println()
-
-	// Demonstrate slice growth
-	fmt.Println("=== SLICE GROWTH COMPARISON ===")
-	for _, size := range []int{100, 1000, 10000} {
-		dynamic, preallocated := benchmarkSliceComparison(size)
-		speedup := float64(dynamic.Nanoseconds()) / float64(preallocated.Nanoseconds())
-		fmt.Printf("Size %6d: Dynamic: %12v, Preallocated: %12v, Speedup: %.2fx\n",
-			size, dynamic, preallocated, speedup)
-	}
-	fmt.Println()
-
-	// Demonstrate map growth
-	fmt.Println("=== MAP GROWTH COMPARISON ===")
-	for _, size := range []int{100, 1000, 10000} {
-		dynamic, preallocated := benchmarkMapComparison(size)
-		speedup := float64(dynamic.Nanoseconds()) / float64(preallocated.Nanoseconds())
-		fmt.Printf("Size %6d: Dynamic: %12v, Preallocated: %12v, Speedup: %.2fx\n",
-			size, dynamic, preallocated, speedup)
-	}
-	fmt.Println()
+func RunMemoryPreallocationDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                MEMORY PREALLOCATION DEMONSTRATION                              ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "WHAT IS PREALLOCATION?")
+	fmt.Fprintln(out, "Preallocating memory for slices and maps before adding elements.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "DYNAMIC GROWTH (without preallocation):")
+	fmt.Fprintln(out, "  - Start with small capacity")
+	fmt.Fprintln(out, "  - Trigger reallocation when capacity exceeded")
+	fmt.Fprintln(out, "  - Each reallocation: copy all data to new location")
+	fmt.Fprintln(out, "  - More allocations = more GC pressure")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "PREALLOCATION (with capacity hint):")
+	fmt.Fprintln(out, "  - Allocate capacity upfront")
+	fmt.Fprintln(out, "  - No reallocations during growth")
+	fmt.Fprintln(out, "  - Single allocation, better performance")
+	fmt.Fprintln(out)
 
 	// Guidelines
-	fmt.Println("=== GUIDELINES ===")
-	fmt.Println("PREALLOCATE SLICES when:")
-	fmt.Println("  - You know or can estimate the final size")
-	fmt.Println("  - Working in tight loops (hot paths)")
-	fmt.Println("  - Building up a slice incrementally")
-	fmt.Println()
-	fmt.Println("PREALLOCATE MAPS when:")
-	fmt.Println("  - You know the approximate number of entries")
-	fmt.Println("  - Inserting many items at once")
-	fmt.Println("  - Performance is critical")
-	fmt.Println()
-	fmt.Println("DON'T PREALLOCATE when:")
-	fmt.Println("  - Size is unknown and could be very large")
-	fmt.Println("  - Memory is constrained")
-	fmt.Println("  - Code readability matters more than micro-optimization")
-	fmt.Println()
+	fmt.Fprintln(out, "=== GUIDELINES ===")
+	fmt.Fprintln(out, "PREALLOCATE SLICES when:")
+	fmt.Fprintln(out, "  - You know or can estimate the final size")
+	fmt.Fprintln(out, "  - Working in tight loops (hot paths)")
+	fmt.Fprintln(out, "  - Building up a slice incrementally")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "PREALLOCATE MAPS when:")
+	fmt.Fprintln(out, "  - You know the approximate number of entries")
+	fmt.Fprintln(out, "  - Inserting many items at once")
+	fmt.Fprintln(out, "  - Performance is critical")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "DON'T PREALLOCATE when:")
+	fmt.Fprintln(out, "  - Size is unknown and could be very large")
+	fmt.Fprintln(out, "  - Memory is constrained")
+	fmt.Fprintln(out, "  - Code readability matters more than micro-optimization")
+	fmt.Fprintln(out)
 
 	// Syntax examples
-	fmt.Println("=== SYNTAX EXAMPLES ===")
-	fmt.Println("// Preallocate slice with capacity")
-	fmt.Println("s := make([]int, 0, 100)  // length=0, capacity=100")
-	fmt.Println()
-	fmt.Println("// Preallocate slice with exact size")
-	fmt.Println("s := make([]int, 100)     // length=100, capacity=100")
-	fmt.Println()
-	fmt.Println("// Preallocate map with size hint")
-	fmt.Println("m := make(map[string]int, 100)  // preallocate for 100 entries")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "=== SYNTAX EXAMPLES ===")
+	fmt.Fprintln(out, "// Preallocate slice with capacity")
+	fmt.Fprintln(out, "s := make([]int, 0, 100)  // length=0, capacity=100")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// Preallocate slice with exact size")
+	fmt.Fprintln(out, "s := make([]int, 100)     // length=100, capacity=100")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// Preallocate map with size hint")
+	fmt.Fprintln(out, "m := make(map[string]int, 100)  // preallocate for 100 entries")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
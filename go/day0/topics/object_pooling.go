@@ -3,10 +3,90 @@ package topics
 
 import (
 	"fmt"
-	"sync"
+	"math/rand/v2"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"day0/pool"
+	"day0/report"
+	"day0/stats"
 )
 
+func init() {
+	Register(&topic{
+		order:   7,
+		id:      "pooling",
+		title:   "Object Pooling",
+		summary: "Reusing objects with sync.Pool to reduce allocations",
+		tags:    []string{"allocation", "gc", "concurrency"},
+		takeaways: []string{
+			"Object pooling reduces GC pressure for high-frequency allocations",
+			"Always reset pooled objects before reuse",
+			"Don't pool rarely used, tiny or long-lived objects",
+			"Pool variable-sized buffers in power-of-two size classes, not one size",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "With vs Without Pool",
+				Names: []string{
+					"BenchmarkWithoutPoolSmall",
+					"BenchmarkWithPoolSmall",
+					"BenchmarkWithoutPoolMedium",
+					"BenchmarkWithPoolMedium",
+					"BenchmarkWithoutPoolLarge",
+					"BenchmarkWithPoolLarge",
+				},
+			},
+			{
+				Title: "Pool Iterations",
+				Names: []string{
+					"BenchmarkPoolSmallIterations",
+					"BenchmarkPoolMediumIterations",
+					"BenchmarkPoolLargeIterations",
+				},
+			},
+			{
+				Title: "Concurrent Pool Access",
+				Names: []string{
+					"BenchmarkPoolConcurrentSmall",
+					"BenchmarkPoolConcurrentMedium",
+					"BenchmarkPoolConcurrentLarge",
+				},
+			},
+			{
+				Title: "Buffer Reuse Patterns",
+				Names: []string{
+					"BenchmarkBufferReuseSequential",
+					"BenchmarkBufferReuseMultipleSizes",
+					"BenchmarkBufferWithoutReset",
+				},
+			},
+			{
+				Title: "Mixed Sizes (64 B to 128 KB)",
+				Names: []string{
+					"BenchmarkMixedSizesAlloc",
+					"BenchmarkMixedSizesSinglePool",
+					"BenchmarkMixedSizesSizeClasses",
+				},
+			},
+			{
+				Title: "Buffer vs bytes.Buffer from sync.Pool",
+				Names: []string{
+					"BenchmarkBufferWriteSmall",
+					"BenchmarkBytesBufferWriteSmall",
+					"BenchmarkBufferWriteLarge",
+					"BenchmarkBytesBufferWriteLarge",
+				},
+			},
+		},
+		demo: RunPoolingDemo,
+	})
+}
+
 // =============================================================================
 // OBJECT POOLING
 // =============================================================================
@@ -23,42 +103,54 @@ import (
 // - Improves performance in high-frequency scenarios
 // - Reuses pre-allocated memory
 //
-// Pool is a generic object pool implementation.
-// Using sync.Pool for thread-safe object reuse.
-var pool = sync.Pool{
-	New: func() any {
-		// Create a new buffer when pool is empty
-		return &Buffer{Data: make([]byte, 1024)}
-	},
-}
+// The pools are pool.Pool[*Buffer]: a typed sync.Pool that counts gets, puts
+// and new allocations, so the demo reports how often the pool really hit.
+
+// Buffer sizes of the pooling demos and benchmarks.
+const (
+	SmallBufferSize  = 1 << 10   // 1 KB
+	MediumBufferSize = 10 << 10  // 10 KB
+	LargeBufferSize  = 100 << 10 // 100 KB
+)
 
-// Buffer represents a reusable data buffer.
-// In real scenarios, this could be a connection, a parser, etc.
-type Buffer struct {
-	Data   []byte
-	Length int
+// NewBufferPool returns a pool of buffers of size bytes. Put resets a buffer
+// and drops any that grew, so a rare large write does not keep a large buffer
+// in the pool.
+func NewBufferPool(size int) *pool.Pool[*Buffer] {
+	return pool.New(
+		func() *Buffer { return &Buffer{Data: make([]byte, size)} },
+		pool.Options[*Buffer]{
+			Reset:    (*Buffer).Reset,
+			Validate: func(b *Buffer) bool { return len(b.Data) == size },
+			Poison:   poisonBuffer,
+			Poisoned: func(b *Buffer) bool { return b.Length == -1 && pool.IsPoisoned(b.Data) },
+		})
 }
 
-// Reset clears the buffer for reuse.
-func (b *Buffer) Reset() {
-	b.Length = 0
+// poisonBuffer fills a returned buffer with pool.PoisonByte and makes Length
+// invalid, so Write, Read and String on a buffer used after PutBuffer panic
+// in pool debug mode.
+func poisonBuffer(b *Buffer) {
+	pool.PoisonBytes(b.Data)
+	b.Length = -1
 }
 
-// Write adds data to the buffer.
-func (b *Buffer) Write(data []byte) {
-	copy(b.Data[b.Length:], data)
-	b.Length += len(data)
-}
+// bufferPool is the pool of 1 KB buffers behind GetBuffer and PutBuffer.
+var bufferPool = NewBufferPool(SmallBufferSize)
 
-// GetBuffer retrieves a buffer from the pool.
+// GetBuffer retrieves a 1 KB buffer from the pool.
 func GetBuffer() *Buffer {
-	return pool.Get().(*Buffer)
+	return bufferPool.Get()
 }
 
-// PutBuffer returns a buffer to the pool.
+// PutBuffer resets a buffer and returns it to the pool.
 func PutBuffer(b *Buffer) {
-	b.Reset()
-	pool.Put(b)
+	bufferPool.Put(b)
+}
+
+// BufferPoolStats returns the counters of the pool behind GetBuffer.
+func BufferPoolStats() pool.Stats {
+	return bufferPool.Stats()
 }
 
 // =============================================================================
@@ -67,12 +159,12 @@ func PutBuffer(b *Buffer) {
 
 // simulateWorkWithoutPool demonstrates creating new objects each time.
 // This causes GC pressure and slower performance.
-func simulateWorkWithoutPool(iterations int) time.Duration {
+func simulateWorkWithoutPool(iterations, size int) time.Duration {
 	start := time.Now()
 
 	for range iterations {
 		// Create new buffer each time - causes allocation!
-		buf := &Buffer{Data: make([]byte, 1024)}
+		buf := &Buffer{Data: make([]byte, size)}
 		buf.Write([]byte("hello"))
 		_ = buf.Length
 		// Buffer is abandoned and GC will collect it
@@ -83,183 +175,369 @@ func simulateWorkWithoutPool(iterations int) time.Duration {
 
 // simulateWorkWithPool demonstrates reusing objects from the pool.
 // This reduces GC pressure and improves performance.
-func simulateWorkWithPool(iterations int) time.Duration {
+func simulateWorkWithPool(iterations int, p *pool.Pool[*Buffer]) time.Duration {
 	start := time.Now()
 
 	for range iterations {
 		// Get buffer from pool - reuse instead of allocate!
-		buf := pool.Get().(*Buffer)
+		buf := p.Get()
 		buf.Write([]byte("hello"))
 		_ = buf.Length
-		// Return buffer to pool for reuse
-		buf.Reset()
-		pool.Put(buf)
+		// Return buffer to pool for reuse; Put resets it
+		p.Put(buf)
 	}
 
 	return time.Since(start)
 }
 
 // RunPoolingDemo demonstrates the performance difference.
-func RunPoolingDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                         OBJECT POOLING DEMONSTRATION                         ")
-	fmt.Println("================================================================================")
-	fmt.Println()
+func RunPoolingDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                         OBJECT POOLING DEMONSTRATION                         ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
 
 	const iterations = 100000
 
 	// Warm up the pool
 	for range 10 {
-		buf := pool.Get()
-		pool.Put(buf)
+		PutBuffer(GetBuffer())
 	}
 
+	// Alternate the two workloads so machine noise hits both equally
+	var withoutPool, withPool []float64
+	before := bufferPool.Stats()
+	for range demoSamples {
+		withoutPool = append(withoutPool, float64(simulateWorkWithoutPool(iterations, SmallBufferSize)))
+		withPool = append(withPool, float64(simulateWorkWithPool(iterations, bufferPool)))
+	}
+	without, with := stats.Summarize(withoutPool), stats.Summarize(withPool)
+	traffic := bufferPool.Stats().Sub(before)
+
 	// Test without pooling
-	fmt.Println("=== WITHOUT OBJECT POOL ===")
-	timeWithoutPool := simulateWorkWithoutPool(iterations)
-	fmt.Printf("Iterations: %d\n", iterations)
-	fmt.Printf("Time taken: %v\n", timeWithoutPool)
-	fmt.Println()
+	fmt.Fprintln(out, "=== WITHOUT OBJECT POOL ===")
+	fmt.Fprintf(out, "Iterations: %d\n", iterations)
+	fmt.Fprintf(out, "Time taken: %s\n", formatTiming(without))
+	fmt.Fprintln(out)
 
 	// Test with pooling
-	fmt.Println("=== WITH OBJECT POOL ===")
-	timeWithPool := simulateWorkWithPool(iterations)
-	fmt.Printf("Iterations: %d\n", iterations)
-	fmt.Printf("Time taken: %v\n", timeWithPool)
-	fmt.Println()
+	fmt.Fprintln(out, "=== WITH OBJECT POOL ===")
+	fmt.Fprintf(out, "Iterations: %d\n", iterations)
+	fmt.Fprintf(out, "Time taken: %s\n", formatTiming(with))
+	fmt.Fprintf(out, "Pool:       %s\n", traffic)
+	fmt.Fprintln(out)
+
+	n := strconv.Itoa(iterations)
+	addTiming(out, "workload", without, "pool", "without", "iterations", n)
+	addTiming(out, "workload", with, "pool", "with", "iterations", n)
+	addPoolStats(out, traffic, "size", strconv.Itoa(SmallBufferSize))
 
 	// Calculate improvement
-	improvement := float64(timeWithoutPool.Nanoseconds()) / float64(timeWithPool.Nanoseconds())
-	fmt.Printf("=== PERFORMANCE IMPROVEMENT ===\n")
-	fmt.Printf("Speedup: %.2fx\n", improvement)
-	fmt.Printf("Time saved: %v\n", timeWithoutPool-timeWithPool)
-	fmt.Println()
-
-	// Run micro-benchmarks for different buffer sizes
-	const benchIterations = 100000
-
-	// Small buffer (1KB) benchmark
-	smallWithoutStart := time.Now()
-	for range benchIterations {
-		buf := &Buffer{Data: make([]byte, 1024)}
-		buf.Write([]byte("hello"))
-		_ = buf.Length
-	}
-	smallWithoutTime := time.Since(smallWithoutStart)
-	smallWithoutNsOp := float64(smallWithoutTime.Nanoseconds()) / float64(benchIterations)
+	fmt.Fprintf(out, "=== PERFORMANCE IMPROVEMENT ===\n")
+	printSpeedup(out, "workload_speedup", withoutPool, withPool, "iterations", n)
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== GC PRESSURE ===")
+	fmt.Fprintf(out, "One run of %d iterations each:\n", iterations)
+	printGCPressure(out, []gcWorkload{
+		{"without pool", func() { simulateWorkWithoutPool(iterations, SmallBufferSize) }},
+		{"with pool", func() { simulateWorkWithPool(iterations, bufferPool) }},
+	}, "iterations", n)
+	fmt.Fprintf(out, "This is with GOGC=%s. To see how GOGC and GOMEMLIMIT change the pool's lead:\n", gogc())
+	fmt.Fprintln(out, "  day0 gcsweep")
+	fmt.Fprintln(out)
+
+	printPoolSizes(out)
+	printPoolAfterGC(out)
+	printBufferGrowth(out)
+	printMixedSizes(out)
+
+	fmt.Fprintln(out, "Key Insight:")
+	fmt.Fprintln(out, "  - Every allocation the pool avoids is heap the GC never has to scan or free")
+	fmt.Fprintln(out)
 
-	smallWithStart := time.Now()
-	for range benchIterations {
-		buf := pool.Get().(*Buffer)
-		buf.Write([]byte("hello"))
-		_ = buf.Length
-		buf.Reset()
-		pool.Put(buf)
+	// Explain when to use pooling
+	fmt.Fprintln(out, "=== WHEN TO USE OBJECT POOLING ===")
+	fmt.Fprintln(out, "✓ High-frequency allocations (loops, request handlers)")
+	fmt.Fprintln(out, "✓ Objects with expensive initialization")
+	fmt.Fprintln(out, "✓ Burstable workloads with many short-lived objects")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "✗ Don't pool objects that are:")
+	fmt.Fprintln(out, "  - Rarely used (pool overhead not worth it)")
+	fmt.Fprintln(out, "  - Very small (allocation cost negligible)")
+	fmt.Fprintln(out, "  - Held for long periods (defeats pooling purpose)")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== CATCHING POOL BUGS ===")
+	if pool.Debug() {
+		fmt.Fprintln(out, "Pool debug mode is ON: buffers are poisoned on Put, double Puts panic and")
+		fmt.Fprintln(out, "buffers never returned are reported with their Get stack at exit.")
+	} else {
+		fmt.Fprintln(out, "Run with POOLDEBUG=1, or build with -tags pooldebug, to poison buffers on Put")
+		fmt.Fprintln(out, "(use after Put), panic on double Puts and report leaked buffers at exit:")
+		fmt.Fprintln(out, "  POOLDEBUG=1 day0 run pooling")
 	}
-	smallWithTime := time.Since(smallWithStart)
-	smallWithNsOp := float64(smallWithTime.Nanoseconds()) / float64(benchIterations)
+	fmt.Fprintln(out)
 
-	// Medium buffer (10KB) benchmark
-	mediumWithoutStart := time.Now()
-	for range benchIterations {
-		buf := &Buffer{Data: make([]byte, 10240)}
-		buf.Write([]byte("hello"))
-		_ = buf.Length
+	fmt.Fprintln(out, "================================================================================")
+}
+
+// gogc returns the GOGC setting of the process as GOGC would spell it.
+func gogc() string {
+	percent := debug.SetGCPercent(100)
+	debug.SetGCPercent(percent)
+	if percent < 0 {
+		return "off"
 	}
-	mediumWithoutTime := time.Since(mediumWithoutStart)
-	mediumWithoutNsOp := float64(mediumWithoutTime.Nanoseconds()) / float64(benchIterations)
+	return strconv.Itoa(percent)
+}
 
-	mediumPool := sync.Pool{
-		New: func() any {
-			return &Buffer{Data: make([]byte, 10240)}
-		},
+// addPoolStats records the counters of a pool.
+func addPoolStats(out *report.Report, s pool.Stats, params ...string) {
+	out.Add("pool_gets", float64(s.Gets), "count", params...)
+	out.Add("pool_news", float64(s.News), "count", params...)
+	out.Add("pool_hit_rate", 100*s.HitRate(), "%", params...)
+}
+
+// printPoolSizes compares allocating and pooling buffers of 1 KB, 10 KB and
+// 100 KB, each size with a pool of its own, and says whether the speedup grew
+// with the size only if every size showed a significant one.
+func printPoolSizes(out *report.Report) {
+	const iterations = 20000
+	fmt.Fprintln(out, "=== POOL SIZES ===")
+	fmt.Fprintf(out, "%d iterations per sample, one pool per size:\n", iterations)
+	fmt.Fprintf(out, "%-8s | %12s | %12s | %9s | %8s\n", "Size", "Without", "With", "Speedup", "Hit rate")
+	fmt.Fprintln(out, strings.Repeat("-", 62))
+	sizes := []int{SmallBufferSize, MediumBufferSize, LargeBufferSize}
+	speedups, allSignificant := make([]float64, 0, len(sizes)), true
+	for _, size := range sizes {
+		p := NewBufferPool(size)
+		without, with := stats.TimePair(demoSamples,
+			func() { simulateWorkWithoutPool(iterations, size) },
+			func() { simulateWorkWithPool(iterations, p) })
+		w, pooled := stats.Summarize(without), stats.Summarize(with)
+		ratio, pValue := w.Median/pooled.Median, stats.MannWhitneyU(without, with)
+		speedups = append(speedups, ratio)
+		mark := " "
+		if !stats.Significant(pValue) {
+			mark, allSignificant = "~", false
+		}
+		s := p.Stats()
+		fmt.Fprintf(out, "%-8s | %12v | %12v | %7.1fx%s | %7.2f%%\n", formatWorkingSet(int64(size)),
+			time.Duration(w.Median).Round(time.Microsecond), time.Duration(pooled.Median).Round(time.Microsecond),
+			ratio, mark, 100*s.HitRate())
+		params := []string{"size", strconv.Itoa(size), "iterations", strconv.Itoa(iterations)}
+		addTiming(out, "workload", w, append(params, "pool", "without")...)
+		addTiming(out, "workload", pooled, append(params, "pool", "with")...)
+		out.Add("workload_speedup", ratio, "x", append(params,
+			"p_value", strconv.FormatFloat(pValue, 'g', 4, 64),
+			"significant", strconv.FormatBool(stats.Significant(pValue)))...)
+		addPoolStats(out, s, params...)
 	}
-	// Warm up medium pool
-	for range 10 {
-		buf := mediumPool.Get()
-		mediumPool.Put(buf)
+	fmt.Fprintf(out, "~ marks a speedup that is not significant (p >= %.2f, Mann-Whitney U).\n", stats.Alpha)
+	fmt.Fprintln(out, "Each pool allocates once per P and per GC that empties it; everything else is a hit.")
+	switch {
+	case !allSignificant:
+		fmt.Fprintln(out, "Not every size differed significantly, so this run says nothing about how")
+		fmt.Fprintln(out, "the pool's lead scales with the buffer size.")
+	case slices.IsSorted(speedups) && speedups[0] < speedups[len(speedups)-1]:
+		fmt.Fprintf(out, "The larger the buffer, the more pooling paid off here: %.1fx at %s, %.1fx at %s.\n",
+			speedups[0], formatWorkingSet(int64(sizes[0])),
+			speedups[len(speedups)-1], formatWorkingSet(int64(sizes[len(sizes)-1])))
+	default:
+		fmt.Fprintln(out, "The pool's lead did not grow steadily with the buffer size here.")
 	}
+	fmt.Fprintln(out)
+}
 
-	mediumWithStart := time.Now()
-	for range benchIterations {
-		buf := mediumPool.Get().(*Buffer)
-		buf.Write([]byte("hello"))
-		_ = buf.Length
-		buf.Reset()
-		mediumPool.Put(buf)
+// printPoolAfterGC shows that sync.Pool does not keep objects forever: a GC
+// moves them to a victim cache, the next one frees them.
+func printPoolAfterGC(out *report.Report) {
+	const objects = 100
+	fmt.Fprintln(out, "=== AFTER GARBAGE COLLECTION ===")
+	fmt.Fprintf(out, "Putting %d buffers back, then getting %d again after 0, 1 and 2 GCs:\n", objects, objects)
+	for gcs := range 3 {
+		p := NewBufferPool(SmallBufferSize)
+		bufs := make([]*Buffer, objects)
+		for i := range bufs {
+			bufs[i] = p.Get()
+		}
+		for _, b := range bufs {
+			p.Put(b)
+		}
+		for range gcs {
+			runtime.GC()
+		}
+		before := p.Stats()
+		for i := range bufs {
+			bufs[i] = p.Get()
+		}
+		s := p.Stats().Sub(before)
+		for _, b := range bufs {
+			p.Put(b)
+		}
+		fmt.Fprintf(out, "  %d GCs: %3d of %d from the pool (%.0f%% hit rate)\n", gcs, s.Hits(), objects, 100*s.HitRate())
+		addPoolStats(out, s, "gcs", strconv.Itoa(gcs))
 	}
-	mediumWithTime := time.Since(mediumWithStart)
-	mediumWithNsOp := float64(mediumWithTime.Nanoseconds()) / float64(benchIterations)
+	fmt.Fprintln(out, "The first GC moves pooled objects to a victim cache Get still serves from;")
+	fmt.Fprintln(out, "the second frees them. A pool only helps while it is used between GCs.")
+	fmt.Fprintln(out)
+}
 
-	// Large buffer (100KB) benchmark
-	largeWithoutStart := time.Now()
-	for range benchIterations {
-		buf := &Buffer{Data: make([]byte, 102400)}
-		buf.Write([]byte("hello"))
-		_ = buf.Length
+// printBufferGrowth writes more than a pooled buffer holds and shows that the
+// buffer grows instead of corrupting Length, and that the pool drops it.
+func printBufferGrowth(out *report.Report) {
+	p := NewBufferPool(SmallBufferSize)
+	buf := p.Get()
+	n, err := buf.Write(make([]byte, 3*SmallBufferSize/2))
+	fmt.Fprintln(out, "=== BUFFER GROWTH ===")
+	fmt.Fprintf(out, "Writing %d bytes to a pooled %s Buffer: wrote %d, err %v, Length %d, grew to %s\n",
+		3*SmallBufferSize/2, formatWorkingSet(SmallBufferSize), n, err, buf.Length, formatWorkingSet(int64(len(buf.Data))))
+	p.Put(buf)
+	fmt.Fprintf(out, "Putting it back: %s\n", p.Stats())
+	fmt.Fprintln(out, "Buffer is an io.Writer/io.Reader that doubles like bytes.Buffer; the pool drops")
+	fmt.Fprintln(out, "grown buffers so one large write does not keep a large buffer alive. Set Limit")
+	fmt.Fprintln(out, "to cap growth: a write beyond it stores what fits and returns io.ErrShortWrite.")
+	fmt.Fprintln(out)
+	out.Add("pool_dropped", float64(p.Stats().Dropped), "count", "size", strconv.Itoa(SmallBufferSize))
+}
+
+// =============================================================================
+// MIXED SIZES: SIZE CLASSES
+// =============================================================================
+//
+// Services rarely need one buffer size: a request body may be 200 bytes or
+// 50 KB. A single-size pool must either use the largest size for everything,
+// holding far more memory than needed, or fall back to allocating. A
+// pool.BytePool keeps one pool per power-of-two size class instead and
+// allocates only what no class can hold.
+
+// Size classes of the mixed-size workload.
+const (
+	MinPooledBuffer = 512
+	MaxPooledBuffer = 64 << 10
+)
+
+// NewMixedSizes returns n request sizes from 64 bytes to 128 KB, spread
+// evenly over the powers of two, so every size class sees traffic and about
+// one request in eleven is too large for any class. The fixed seed makes
+// every run use the same sizes.
+func NewMixedSizes(n int) []int {
+	r := rand.New(rand.NewPCG(7, 7))
+	sizes := make([]int, n)
+	for i := range sizes {
+		lo := 64 << r.IntN(11)
+		sizes[i] = lo + r.IntN(lo)
 	}
-	largeWithoutTime := time.Since(largeWithoutStart)
-	largeWithoutNsOp := float64(largeWithoutTime.Nanoseconds()) / float64(benchIterations)
+	return sizes
+}
 
-	largePool := sync.Pool{
-		New: func() any {
-			return &Buffer{Data: make([]byte, 102400)}
-		},
+// mixedSource is the data the mixed-size workloads copy into their buffers.
+var mixedSource = make([]byte, 2*MaxPooledBuffer)
+
+// FillAllocated allocates a buffer for each size and fills it. It returns
+// the total capacity of the buffers, which the workloads compare.
+func FillAllocated(sizes []int) int {
+	held := 0
+	for _, n := range sizes {
+		b := make([]byte, n)
+		copy(b, mixedSource)
+		held += cap(b)
 	}
-	// Warm up large pool
-	for range 10 {
-		buf := largePool.Get()
-		largePool.Put(buf)
+	return held
+}
+
+// FillSinglePool serves each size from a pool of MaxPooledBuffer buffers,
+// allocating the sizes that do not fit.
+func FillSinglePool(sizes []int, p *pool.Pool[*Buffer]) int {
+	held := 0
+	for _, n := range sizes {
+		if n > MaxPooledBuffer {
+			b := make([]byte, n)
+			copy(b, mixedSource)
+			held += cap(b)
+			continue
+		}
+		buf := p.Get()
+		buf.Write(mixedSource[:n])
+		held += cap(buf.Data)
+		p.Put(buf)
 	}
+	return held
+}
 
-	largeWithStart := time.Now()
-	for range benchIterations {
-		buf := largePool.Get().(*Buffer)
-		buf.Write([]byte("hello"))
-		_ = buf.Length
-		buf.Reset()
-		largePool.Put(buf)
+// FillSizeClasses serves each size from the size class that fits it.
+func FillSizeClasses(sizes []int, p *pool.BytePool) int {
+	held := 0
+	for _, n := range sizes {
+		b := p.Get(n)
+		copy(b, mixedSource)
+		held += cap(b)
+		p.Put(b)
 	}
-	largeWithTime := time.Since(largeWithStart)
-	largeWithNsOp := float64(largeWithTime.Nanoseconds()) / float64(benchIterations)
-
-	// Print benchmark results with actual measurements
-	fmt.Println("=== BENCHMARK RESULTS ===")
-	fmt.Println("Size Comparison (1KB buffer):")
-	fmt.Printf("  - Without pool: ~%.0f ns/op\n", smallWithoutNsOp)
-	fmt.Printf("  - With pool: ~%.0f ns/op\n", smallWithNsOp)
-	smallSpeedup := smallWithoutNsOp / smallWithNsOp
-	fmt.Printf("  -> Speedup: %.1fx\n", smallSpeedup)
-	fmt.Println()
-	fmt.Println("Size Comparison (10KB buffer):")
-	fmt.Printf("  - Without pool: ~%.0f ns/op\n", mediumWithoutNsOp)
-	fmt.Printf("  - With pool: ~%.0f ns/op\n", mediumWithNsOp)
-	mediumSpeedup := mediumWithoutNsOp / mediumWithNsOp
-	fmt.Printf("  -> Speedup: %.0fx (massive improvement!)\n", mediumSpeedup)
-	fmt.Println()
-	fmt.Println("Size Comparison (100KB buffer):")
-	fmt.Printf("  - Without pool: ~%.0f ns/op\n", largeWithoutNsOp)
-	fmt.Printf("  - With pool: ~%.0f ns/op\n", largeWithNsOp)
-	largeSpeedup := largeWithoutNsOp / largeWithNsOp
-	fmt.Printf("  -> Speedup: %.0fx (AMAZING!)\n", largeSpeedup)
-	fmt.Println()
-	fmt.Println("Key Insight:")
-	fmt.Println("  - Pooling is MORE effective for larger objects")
-	fmt.Println("  - Larger allocations benefit more from reuse")
-	fmt.Println("  - GC pressure reduction is significant")
-	fmt.Println()
+	return held
+}
 
-	// Explain when to use pooling
-	fmt.Println("=== WHEN TO USE OBJECT POOLING ===")
-	fmt.Println("✓ High-frequency allocations (loops, request handlers)")
-	fmt.Println("✓ Objects with expensive initialization")
-	fmt.Println("✓ Burstable workloads with many short-lived objects")
-	fmt.Println()
-	fmt.Println("✗ Don't pool objects that are:")
-	fmt.Println("  - Rarely used (pool overhead not worth it)")
-	fmt.Println("  - Very small (allocation cost negligible)")
-	fmt.Println("  - Held for long periods (defeats pooling purpose)")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+// printMixedSizes runs the mixed-size workload with plain allocation, a
+// single-size pool and size classes, then shows the traffic per class.
+func printMixedSizes(out *report.Report) {
+	const requests = 2000
+	sizes := NewMixedSizes(requests)
+	requested := 0
+	for _, n := range sizes {
+		requested += n
+	}
+	single := NewBufferPool(MaxPooledBuffer)
+	classes := pool.NewBytePool(MinPooledBuffer, MaxPooledBuffer)
+
+	fmt.Fprintln(out, "=== MIXED SIZES ===")
+	fmt.Fprintf(out, "%d requests from 64 B to 128 KB; pools hold %s to %s, larger ones are allocated.\n",
+		requests, formatWorkingSet(MinPooledBuffer), formatWorkingSet(MaxPooledBuffer))
+	fmt.Fprintln(out, "Slack is the share of the buffer capacity the requests did not use.")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-14s | %14s | %8s | %8s\n", "Strategy", "Time/request", "Slack", "Hit rate")
+	fmt.Fprintln(out, strings.Repeat("-", 53))
+
+	singleBefore, classesBefore := single.Stats(), classes.Stats()
+	for _, w := range []struct {
+		name string
+		fill func() int
+		hits func() float64
+	}{
+		{"allocate", func() int { return FillAllocated(sizes) }, nil},
+		{"single pool", func() int { return FillSinglePool(sizes, single) },
+			func() float64 { return single.Stats().Sub(singleBefore).HitRate() }},
+		{"size classes", func() int { return FillSizeClasses(sizes, classes) },
+			func() float64 { return classes.Stats().Sub(classesBefore).Total().HitRate() }},
+	} {
+		held := 0
+		samples := stats.Time(demoSamples, func() { held = w.fill() })
+		perRequest := stats.Summarize(samples).Median / requests
+		slack := 100 * float64(held-requested) / float64(held)
+		hitRate := "-"
+		params := []string{"strategy", w.name}
+		if w.hits != nil {
+			rate := 100 * w.hits()
+			hitRate = fmt.Sprintf("%.2f%%", rate)
+			out.Add("pool_hit_rate", rate, "%", params...)
+		}
+		fmt.Fprintf(out, "%-14s | %14s | %7.1f%% | %8s\n", w.name, time.Duration(perRequest), slack, hitRate)
+		out.Add("request_time", perRequest, "ns/op", params...)
+		out.Add("buffer_slack", slack, "%", params...)
+	}
+	fmt.Fprintln(out)
+
+	s := classes.Stats().Sub(classesBefore)
+	fmt.Fprintln(out, "Traffic per size class:")
+	fmt.Fprintf(out, "  %-8s | %8s | %6s | %8s\n", "Class", "Gets", "New", "Hit rate")
+	for _, c := range s.Classes {
+		fmt.Fprintf(out, "  %-8s | %8d | %6d | %7.2f%%\n", formatWorkingSet(int64(c.Size)), c.Gets, c.News, 100*c.HitRate())
+		addPoolStats(out, c.Stats, "class", strconv.Itoa(c.Size))
+	}
+	fmt.Fprintf(out, "  %-8s | %8d | %6s | %8s\n", "> "+formatWorkingSet(MaxPooledBuffer), s.Oversized, "all", "-")
+	out.Add("pool_oversized", float64(s.Oversized), "count")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "The single pool is as fast as size classes but holds a 64 KB buffer for a")
+	fmt.Fprintln(out, "100-byte request; size classes hold at most twice what was asked for.")
+	fmt.Fprintln(out)
 }
@@ -4,8 +4,44 @@ package topics
 import (
 	"fmt"
 	"unsafe"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   2,
+		id:      "pass-by-value",
+		title:   "Pass by Value vs Pointer",
+		summary: "Copy cost of value parameters vs indirection of pointers",
+		tags:    []string{"copy", "functions"},
+		takeaways: []string{
+			"Pass small structs by value, large structs by pointer",
+			"Value parameters copy the whole struct onto the callee's stack",
+			"Pointers copy 8 bytes but add indirection and may cause escapes",
+			"Measure the crossover: inlining can make even large value parameters free",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Pass by Value vs Pointer",
+				Names: []string{
+					"BenchmarkAddByValue",
+					"BenchmarkAddByPointer",
+				},
+			},
+			{
+				Title: "Copy-Size Sweep, Inlined (8 B to 4 KB)",
+				Names: copySweepBenchmarks(true),
+			},
+			{
+				Title: "Copy-Size Sweep, //go:noinline (8 B to 4 KB)",
+				Names: copySweepBenchmarks(false),
+			},
+		},
+		demo: RunPassByValueDemo,
+	})
+}
+
 // LargeStruct is a 1KB struct to demonstrate heap vs stack allocation.
 type LargeStruct struct {
 	Field1 int64
@@ -34,7 +70,51 @@ func AddByPointer(a, b *LargeStruct) int64 {
 	return a.Field1 + b.Field1 + a.Field2 + b.Field2
 }
 
+// =============================================================================
+// DEMONSTRATION
+// =============================================================================
+
 // RunPassByValueDemo demonstrates pass by value vs pointer.
-func RunPassByValueDemo() {
-	fmt.Println("Pass by Value vs Pointer Demo")
+func RunPassByValueDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    PASS BY VALUE VS POINTER DEMONSTRATION                     ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "PASS BY VALUE:")
+	fmt.Fprintln(out, "  - Copies the entire struct onto the stack")
+	fmt.Fprintln(out, "  - No heap allocation needed (stack-to-stack copy is fast)")
+	fmt.Fprintln(out, "  - Good for small structs (< 2 words)")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "PASS BY POINTER:")
+	fmt.Fprintln(out, "  - Only copies the 8-byte pointer")
+	fmt.Fprintln(out, "  - Avoids copy overhead for large structs")
+	fmt.Fprintln(out, "  - Adds slight indirection cost")
+	fmt.Fprintln(out)
+
+	// Show LargeStruct size
+	fmt.Fprintln(out, "=== LargeStruct Size ===")
+	fmt.Fprintf(out, "LargeStruct: %d bytes (%.2f KB)\n", GetLargeStructSize(), float64(GetLargeStructSize())/1024)
+	out.Add("struct_size", float64(GetLargeStructSize()), "bytes", "type", "LargeStruct")
+	fmt.Fprintln(out, "This is large enough to show significant copy overhead!")
+	fmt.Fprintln(out)
+
+	// When to use each
+	fmt.Fprintln(out, "=== When to Use Each ===")
+	fmt.Fprintln(out, "PASS BY VALUE when:")
+	fmt.Fprintln(out, "  - Struct is small (< 16 bytes / 2 words)")
+	fmt.Fprintln(out, "  - You need thread-safety (no aliasing)")
+	fmt.Fprintln(out, "  - Data is read-only")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "PASS BY POINTER when:")
+	fmt.Fprintln(out, "  - Struct is large (> 100 bytes)")
+	fmt.Fprintln(out, "  - You need to modify the original")
+	fmt.Fprintln(out, "  - Performance is critical in hot paths")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== Where Is the Crossover on This Machine? ===")
+	printCopySweep(out)
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
@@ -4,8 +4,42 @@ package topics
 import (
 	"fmt"
 	"unsafe"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   3,
+		id:      "receiver-types",
+		title:   "Receiver Types (Value vs Pointer)",
+		summary: "Method receivers: copying the struct vs sharing the original",
+		tags:    []string{"copy", "methods"},
+		takeaways: []string{
+			"Use pointer receivers for large types or when mutation is needed",
+			"Value receivers operate on a copy; changes do not persist",
+			"Methods that must mutate through an interface need pointer receivers",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Small Struct (Counter)",
+				Names: []string{
+					"BenchmarkIncrementByValue",
+					"BenchmarkIncrementByPointer",
+				},
+			},
+			{
+				Title: "Large Struct (DataProcessor)",
+				Names: []string{
+					"BenchmarkProcessByValue",
+					"BenchmarkProcessByPointer",
+				},
+			},
+		},
+		demo: RunReceiverTypesDemo,
+	})
+}
+
 // =============================================================================
 // RECEIVER TYPES: VALUE VS POINTER
 // =============================================================================
@@ -125,43 +159,45 @@ func GetDataProcessorSize() int {
 // =============================================================================
 
 // RunReceiverTypesDemo demonstrates value vs pointer receiver performance.
-func RunReceiverTypesDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                    RECEIVER TYPES DEMONSTRATION                               ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	fmt.Println("VALUE RECEIVER:")
-	fmt.Println("  - Go makes a COPY of the struct")
-	fmt.Println("  - Changes don't affect the original")
-	fmt.Println("  - Good for small, read-only operations")
-	fmt.Println()
-	fmt.Println("POINTER RECEIVER:")
-	fmt.Println("  - Go passes a pointer to the original")
-	fmt.Println("  - Changes persist")
-	fmt.Println("  - No copy overhead - efficient for large types")
-	fmt.Println()
+func RunReceiverTypesDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    RECEIVER TYPES DEMONSTRATION                               ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "VALUE RECEIVER:")
+	fmt.Fprintln(out, "  - Go makes a COPY of the struct")
+	fmt.Fprintln(out, "  - Changes don't affect the original")
+	fmt.Fprintln(out, "  - Good for small, read-only operations")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "POINTER RECEIVER:")
+	fmt.Fprintln(out, "  - Go passes a pointer to the original")
+	fmt.Fprintln(out, "  - Changes persist")
+	fmt.Fprintln(out, "  - No copy overhead - efficient for large types")
+	fmt.Fprintln(out)
 
 	// Show sizes
-	fmt.Println("=== Receiver Type Impact ===")
+	fmt.Fprintln(out, "=== Receiver Type Impact ===")
 	counterSize := GetCounterSize()
 	processorSize := GetDataProcessorSize()
-	fmt.Printf("Counter: %d bytes (copy cost negligible)\n", counterSize)
-	fmt.Printf("DataProcessor: %d bytes (copy cost significant!)\n", processorSize)
-	fmt.Println()
+	fmt.Fprintf(out, "Counter: %d bytes (copy cost negligible)\n", counterSize)
+	fmt.Fprintf(out, "DataProcessor: %d bytes (copy cost significant!)\n", processorSize)
+	out.Add("struct_size", float64(counterSize), "bytes", "type", "Counter")
+	out.Add("struct_size", float64(processorSize), "bytes", "type", "DataProcessor")
+	fmt.Fprintln(out)
 
 	// Guidelines
-	fmt.Println("=== Guidelines ===")
-	fmt.Println("Use VALUE RECEIVER when:")
-	fmt.Println("  - Struct is small (< 16 bytes)")
-	fmt.Println("  - You don't need to modify the original")
-	fmt.Println("  - Thread-safety is important (no aliasing)")
-	fmt.Println()
-	fmt.Println("Use POINTER RECEIVER when:")
-	fmt.Println("  - Struct is large (> 100 bytes)")
-	fmt.Println("  - You need to modify the original")
-	fmt.Println("  - Method must satisfy an interface that requires pointers")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "=== Guidelines ===")
+	fmt.Fprintln(out, "Use VALUE RECEIVER when:")
+	fmt.Fprintln(out, "  - Struct is small (< 16 bytes)")
+	fmt.Fprintln(out, "  - You don't need to modify the original")
+	fmt.Fprintln(out, "  - Thread-safety is important (no aliasing)")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Use POINTER RECEIVER when:")
+	fmt.Fprintln(out, "  - Struct is large (> 100 bytes)")
+	fmt.Fprintln(out, "  - You need to modify the original")
+	fmt.Fprintln(out, "  - Method must satisfy an interface that requires pointers")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
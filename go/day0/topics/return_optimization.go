@@ -3,8 +3,35 @@ package topics
 
 import (
 	"fmt"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   4,
+		id:      "return-optimization",
+		title:   "Return Value Optimization (RVO)",
+		summary: "Returning by value vs returning pointers to locals",
+		tags:    []string{"escape", "allocation"},
+		takeaways: []string{
+			"Return by value when possible - let RVO handle optimization",
+			"The caller reserves space for the result, so no extra copy is made",
+			"Returning a pointer to a local forces a heap allocation",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Return by Value vs Pointer",
+				Names: []string{
+					"BenchmarkReturnAddByValue",
+					"BenchmarkReturnAddByPointer",
+				},
+			},
+		},
+		demo: RunReturnOptimizationDemo,
+	})
+}
+
 // =============================================================================
 // RETURN VALUE OPTIMIZATION (RVO)
 // =============================================================================
@@ -40,8 +67,9 @@ func ReturnAddByValue(a, b LargeStruct) LargeStruct {
 // ReturnAddByPointer demonstrates HEAP ESCAPE - returning a pointer to local data.
 //
 // ANALOGY: We wrote our return address on the box and mailed it to the caller.
-//          Now the caller has the box, so we can't throw it away!
-//          This forces Go to put the box in the "warehouse" (heap).
+//
+//	Now the caller has the box, so we can't throw it away!
+//	This forces Go to put the box in the "warehouse" (heap).
 //
 // WHY ESCAPE HAPPENS:
 // - We return &c (address of local variable c)
@@ -67,35 +95,38 @@ func ReturnAddByPointer(a, b LargeStruct) *LargeStruct {
 // =============================================================================
 
 // RunReturnOptimizationDemo demonstrates RVO and heap escape.
-func RunReturnOptimizationDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                RETURN VALUE OPTIMIZATION (RVO) DEMONSTRATION                   ")
-	fmt.Println("================================================================================")
-	fmt.Println()
+func RunReturnOptimizationDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                RETURN VALUE OPTIMIZATION (RVO) DEMONSTRATION                   ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "WHAT IS RVO?")
+	fmt.Fprintln(out, "Return Value Optimization allows Go to:")
+	fmt.Fprintln(out, "  1. Allocate return space in the CALLER (not in the function)")
+	fmt.Fprintln(out, "  2. Build the return value directly where it's needed")
+	fmt.Fprintln(out, "  3. Zero copies - zero allocations!")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "RETURN BY VALUE (with RVO):")
+	fmt.Fprintln(out, "  ✓ No heap allocation")
+	fmt.Fprintln(out, "  ✓ No copy overhead")
+	fmt.Fprintln(out, "  ✓ Clean code, great performance")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "RETURN POINTER (heap escape):")
+	fmt.Fprintln(out, "  ✗ Heap allocation required")
+	fmt.Fprintln(out, "  ✗ Garbage collector pressure")
+	fmt.Fprintln(out, "  ✗ Potential cache misses")
 
-	fmt.Println("WHAT IS RVO?")
-	fmt.Println("Return Value Optimization allows Go to:")
-	fmt.Println("  1. Allocate return space in the CALLER (not in the function)")
-	fmt.Println("  2. Build the return value directly where it's needed")
-	fmt.Println("  3. Zero copies - zero allocations!")
-	fmt.Println()
-	fmt.Println("RETURN BY VALUE (with RVO):")
-	fmt.Println("  ✓ No heap allocation")
-	fmt.Println("  ✓ No copy overhead")
-	fmt.Println("  ✓ Clean code, great performance")
-	fmt.Println()
-	fmt.Println("RETURN POINTER (heap escape):")
-	fmt.Println("  ✗ Heap allocation required")
-	fmt.Println("  ✗ Garbage collector pressure")
-	fmt.Println("  ✗ Potential cache misses")
+	printCompilerVerdicts(out, "ReturnAddByValue", "ReturnAddByPointer",
+		"CreateLargeStructOnStack", "CreateLargeStructOnHeap")
 
 	// Key takeaway
-	fmt.Println()
-	fmt.Println("=== Key Takeaway ===")
-	fmt.Println("✓ Let the compiler help you - return by value when possible!")
-	fmt.Println("✓ RVO is one of Go's best optimizations")
-	fmt.Println("✓ Avoid returning pointers to local variables unless necessary")
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Key Takeaway ===")
+	fmt.Fprintln(out, "✓ Let the compiler help you - return by value when possible!")
+	fmt.Fprintln(out, "✓ RVO is one of Go's best optimizations")
+	fmt.Fprintln(out, "✓ Avoid returning pointers to local variables unless necessary")
+	fmt.Fprintln(out)
 
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "================================================================================")
 }
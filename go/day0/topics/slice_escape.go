@@ -3,9 +3,37 @@ package topics
 
 import (
 	"fmt"
-	"time"
+	"strconv"
+
+	"day0/report"
+	"day0/stats"
 )
 
+func init() {
+	Register(&topic{
+		order:   5,
+		id:      "slice-escape",
+		title:   "Slice Escape Analysis",
+		summary: "When slice backing arrays escape to the heap",
+		tags:    []string{"escape", "allocation"},
+		takeaways: []string{
+			"Keep data local to avoid heap escape and GC pressure",
+			"Assigning to globals, returning or storing a slice makes it escape",
+			"Escape analysis is decided at compile time",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Escaping vs Local Slices",
+				Names: []string{
+					"BenchmarkProcessSliceWithEscape",
+					"BenchmarkProcessSliceNoEscape",
+				},
+			},
+		},
+		demo: RunSliceEscapeDemo,
+	})
+}
+
 // =============================================================================
 // SLICE ESCAPE ANALYSIS
 // =============================================================================
@@ -29,7 +57,8 @@ var globalSlice []int
 // - Therefore s's data MUST survive function scope → HEAP allocation!
 //
 // ANALOGY: Writing something in a notebook vs. publishing a book.
-//          Global = published book (can't be taken back!)
+//
+//	Global = published book (can't be taken back!)
 //
 // KEY TAKEAWAY: Assigning to globals/returning/storing = escape to heap.
 func ProcessSliceWithEscape(n int) int {
@@ -71,59 +100,71 @@ func ProcessSliceNoEscape(n int) int {
 // =============================================================================
 
 // RunSliceEscapeDemo demonstrates escape analysis with slices.
-func RunSliceEscapeDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                    SLICE ESCAPE ANALYSIS DEMONSTRATION                        ")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	fmt.Println("WHAT IS ESCAPE ANALYSIS?")
-	fmt.Println("Go determines at compile time whether data can stay on the stack")
-	fmt.Println("or must be moved to the heap (where it 'escapes' the function).")
-	fmt.Println()
-	fmt.Println("STAY ON STACK (no escape):")
-	fmt.Println("  ✓ Fast allocation (just move stack pointer)")
-	fmt.Println("  ✓ No GC pressure")
-	fmt.Println("  ✓ Automatic cleanup")
-	fmt.Println()
-	fmt.Println("ESCAPE TO HEAP:")
-	fmt.Println("  ✗ Requires allocation")
-	fmt.Println("  ✗ GC must track and collect")
-	fmt.Println("  ✗ Slower than stack")
+func RunSliceEscapeDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    SLICE ESCAPE ANALYSIS DEMONSTRATION                        ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "WHAT IS ESCAPE ANALYSIS?")
+	fmt.Fprintln(out, "Go determines at compile time whether data can stay on the stack")
+	fmt.Fprintln(out, "or must be moved to the heap (where it 'escapes' the function).")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "STAY ON STACK (no escape):")
+	fmt.Fprintln(out, "  ✓ Fast allocation (just move stack pointer)")
+	fmt.Fprintln(out, "  ✓ No GC pressure")
+	fmt.Fprintln(out, "  ✓ Automatic cleanup")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "ESCAPE TO HEAP:")
+	fmt.Fprintln(out, "  ✗ Requires allocation")
+	fmt.Fprintln(out, "  ✗ GC must track and collect")
+	fmt.Fprintln(out, "  ✗ Slower than stack")
+
+	printCompilerVerdicts(out, "ProcessSliceWithEscape", "ProcessSliceNoEscape")
 
 	// Demonstrate escape with timing
-	fmt.Println()
-	fmt.Println("=== Escape Demonstration (Timing Test) ===")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Escape Demonstration (Timing Test) ===")
 
-	// Run timing test to show difference
+	// Time both variants at each size, alternating so machine noise hits
+	// both equally
+	const iterations = 100
 	for _, size := range []int{100, 1000, 10000} {
-		start := time.Now()
 		var escapeSum, noEscapeSum int
-
-		// Warm up
-		for i := range 1000 {
-			if i%2 == 0 {
-				escapeSum += ProcessSliceWithEscape(size)
-			} else {
-				noEscapeSum += ProcessSliceNoEscape(size)
-			}
-		}
-		elapsed := time.Since(start)
+		escape, noEscape := stats.TimePair(demoSamples,
+			func() {
+				for range iterations {
+					escapeSum += ProcessSliceWithEscape(size)
+				}
+			},
+			func() {
+				for range iterations {
+					noEscapeSum += ProcessSliceNoEscape(size)
+				}
+			})
 
 		_ = escapeSum
 		_ = noEscapeSum
 
-		fmt.Printf("Slice size %5d: %v for 1000 iterations\n", size, elapsed)
+		escapeTime, noEscapeTime := stats.Summarize(escape), stats.Summarize(noEscape)
+		fmt.Fprintf(out, "Slice size %5d (%d iterations):\n", size, iterations)
+		fmt.Fprintf(out, "  Escaping:     %s\n", formatTiming(escapeTime))
+		fmt.Fprintf(out, "  Not escaping: %s\n", formatTiming(noEscapeTime))
+		fmt.Fprint(out, "  ")
+		params := []string{"slice_size", strconv.Itoa(size), "iterations", strconv.Itoa(iterations)}
+		printSpeedup(out, "no_escape_speedup", escape, noEscape, params...)
+		addTiming(out, "slice_processing", escapeTime, append(params, "escapes", "true")...)
+		addTiming(out, "slice_processing", noEscapeTime, append(params, "escapes", "false")...)
 	}
 
 	// Guidelines
-	fmt.Println()
-	fmt.Println("=== Guidelines ===")
-	fmt.Println("✓ Keep data local to avoid escape")
-	fmt.Println("✓ Avoid assigning to global variables")
-	fmt.Println("✓ Don't return slices unnecessarily")
-	fmt.Println("✓ Use //go:noinline to prevent optimization if testing")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Guidelines ===")
+	fmt.Fprintln(out, "✓ Keep data local to avoid escape")
+	fmt.Fprintln(out, "✓ Avoid assigning to global variables")
+	fmt.Fprintln(out, "✓ Don't return slices unnecessarily")
+	fmt.Fprintln(out, "✓ Use //go:noinline to prevent optimization if testing")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
 }
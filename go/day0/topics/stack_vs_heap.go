@@ -4,8 +4,35 @@ package topics
 import (
 	"fmt"
 	"runtime"
+
+	"day0/report"
 )
 
+func init() {
+	Register(&topic{
+		order:   6,
+		id:      "stack-vs-heap",
+		title:   "Stack vs Heap Allocation",
+		summary: "Where Go allocates data and what it costs",
+		tags:    []string{"allocation", "gc", "escape"},
+		takeaways: []string{
+			"Stack allocation is faster but data must not outlive function",
+			"Heap allocations are tracked and collected by the GC",
+			"Use pprof and escape analysis to find unexpected heap allocations",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Stack vs Heap Allocation",
+				Names: []string{
+					"BenchmarkCreateLargeStructOnStack",
+					"BenchmarkCreateLargeStructOnHeap",
+				},
+			},
+		},
+		demo: RunStackVsHeapDemo,
+	})
+}
+
 // =============================================================================
 // STACK VS HEAP ALLOCATION
 // =============================================================================
@@ -82,44 +109,74 @@ func CreateLargeStructOnHeap() *LargeStruct {
 // DEMONSTRATION
 // =============================================================================
 
+// gcPressureStructs is the number of structs each GC pressure workload creates.
+const gcPressureStructs = 100_000
+
+// The GC pressure workloads store their results here so that the compiler
+// cannot drop the calls; the value copy stays off the heap, the pointer not.
+var (
+	largeStructValue   LargeStruct
+	largeStructPointer *LargeStruct
+)
+
 // RunStackVsHeapDemo demonstrates stack vs heap allocation.
-func RunStackVsHeapDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                    STACK VS HEAP ALLOCATION DEMONSTRATION                     ")
-	fmt.Println("================================================================================")
-	fmt.Println()
+func RunStackVsHeapDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    STACK VS HEAP ALLOCATION DEMONSTRATION                     ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
 
-	fmt.Println("THE STACK:")
-	fmt.Println("  - Fast allocation (just move a pointer)")
-	fmt.Println("  - Automatic cleanup (no GC needed)")
-	fmt.Println("  - Limited size (~MB)")
-	fmt.Println("  - Great for short-lived data")
-	fmt.Println()
-	fmt.Println("THE HEAP:")
-	fmt.Println("  - Slower allocation (requires finding free space)")
-	fmt.Println("  - Requires garbage collection")
-	fmt.Println("  - Much larger (~GB)")
-	fmt.Println("  - For data that outlives its function")
+	fmt.Fprintln(out, "THE STACK:")
+	fmt.Fprintln(out, "  - Fast allocation (just move a pointer)")
+	fmt.Fprintln(out, "  - Automatic cleanup (no GC needed)")
+	fmt.Fprintln(out, "  - Limited size (~MB)")
+	fmt.Fprintln(out, "  - Great for short-lived data")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "THE HEAP:")
+	fmt.Fprintln(out, "  - Slower allocation (requires finding free space)")
+	fmt.Fprintln(out, "  - Requires garbage collection")
+	fmt.Fprintln(out, "  - Much larger (~GB)")
+	fmt.Fprintln(out, "  - For data that outlives its function")
 
 	// Get current runtime info
-	fmt.Println()
-	fmt.Println("=== Runtime Information ===")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Runtime Information ===")
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
-	fmt.Printf("Go version: %s\n", runtime.Version())
-	fmt.Printf("NumCPU: %d\n", runtime.NumCPU())
-	fmt.Printf("GOMAXPROCS: %d\n", runtime.GOMAXPROCS(0))
-	fmt.Printf("GC cycles: %d\n", m.NumGC)
+	fmt.Fprintf(out, "Go version: %s\n", runtime.Version())
+	fmt.Fprintf(out, "NumCPU: %d\n", runtime.NumCPU())
+	fmt.Fprintf(out, "GOMAXPROCS: %d\n", runtime.GOMAXPROCS(0))
+	fmt.Fprintf(out, "GC cycles: %d\n", m.NumGC)
+	out.Add("num_cpu", float64(runtime.NumCPU()), "count", "go_version", runtime.Version())
+	out.Add("gomaxprocs", float64(runtime.GOMAXPROCS(0)), "count")
+	out.Add("gc_cycles", float64(m.NumGC), "count")
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== GC Pressure ===")
+	fmt.Fprintf(out, "%d LargeStructs (%d bytes each), kept in a package variable:\n", gcPressureStructs, GetLargeStructSize())
+	printGCPressure(out, []gcWorkload{
+		{"on stack", func() {
+			for range gcPressureStructs {
+				largeStructValue = CreateLargeStructOnStack()
+			}
+		}},
+		{"on heap", func() {
+			for range gcPressureStructs {
+				largeStructPointer = CreateLargeStructOnHeap()
+			}
+		}},
+	})
 
 	// Key insights
-	fmt.Println()
-	fmt.Println("=== Key Insights ===")
-	fmt.Println("✓ Stack allocation is ~10-100x faster than heap")
-	fmt.Println("✓ Small allocations may not trigger GC at all")
-	fmt.Println("✓ Escape analysis happens at compile time")
-	fmt.Println("✓ Large objects (> 64KB) go directly to heap")
-	fmt.Println("✓ Use pprof to identify heap allocations: go tool pprof")
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Key Insights ===")
+	fmt.Fprintln(out, "✓ Stack allocation is ~10-100x faster than heap")
+	fmt.Fprintln(out, "✓ Small allocations may not trigger GC at all")
+	fmt.Fprintln(out, "✓ Escape analysis happens at compile time")
+	fmt.Fprintln(out, "✓ Large objects (> 64KB) go directly to heap")
+	fmt.Fprintln(out, "✓ Use pprof to identify heap allocations: go tool pprof")
+	fmt.Fprintln(out, "  (day0 bench --profile=heap stack-vs-heap shows the top allocators)")
+	fmt.Fprintln(out)
 
-	fmt.Println("================================================================================")
+	fmt.Fprintln(out, "================================================================================")
 }
@@ -3,11 +3,58 @@ package topics
 
 import (
 	"fmt"
-	"math/rand"
-	"time"
-	"unsafe"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"day0/cpucache"
+	"day0/layout"
+	"day0/report"
+	"day0/stats"
 )
 
+func init() {
+	Register(&topic{
+		order:   1,
+		id:      "alignment",
+		title:   "Struct Alignment & Memory Padding",
+		summary: "How field ordering affects struct size and cache efficiency",
+		tags:    []string{"memory", "layout", "cache"},
+		takeaways: []string{
+			"Order struct fields largest to smallest to minimize padding",
+			"The compiler never reorders fields; padding is inserted to satisfy alignment",
+			"Smaller structs fit more elements per cache line",
+		},
+		benchmarks: []BenchmarkGroup{
+			{
+				Title: "Struct Alignment",
+				Names: []string{
+					"BenchmarkProcessUnaligned",
+					"BenchmarkProcessAligned",
+					"BenchmarkProcessUnalignedPtr",
+					"BenchmarkProcessAlignedPtr",
+					"BenchmarkMixedTypesAligned",
+					"BenchmarkMixedTypesUnaligned",
+				},
+			},
+			{
+				Title: "Access Patterns (working set beyond L3)",
+				Names: []string{
+					"BenchmarkSequentialUnaligned",
+					"BenchmarkSequentialAligned",
+					"BenchmarkStridedUnaligned",
+					"BenchmarkStridedAligned",
+					"BenchmarkRandomUnaligned",
+					"BenchmarkRandomAligned",
+				},
+			},
+		},
+		demo: RunAlignmentDemo,
+	})
+}
+
 // =============================================================================
 // STRUCT ALIGNMENT AND DATA PADDING
 // =============================================================================
@@ -68,15 +115,23 @@ type MixedTypesUnaligned struct {
 	Pointer *int64
 }
 
+// AlignmentDemoTypes are the structs the alignment demo reports on.
+var AlignmentDemoTypes = []reflect.Type{
+	reflect.TypeFor[UnalignedStruct](),
+	reflect.TypeFor[AlignedStruct](),
+	reflect.TypeFor[PoorlyPaddedStruct](),
+	reflect.TypeFor[MixedTypesAligned](),
+	reflect.TypeFor[MixedTypesUnaligned](),
+}
+
 // GetStructSizes demonstrates how to check struct sizes at runtime.
+// reflect.Type.Size reports the same value as unsafe.Sizeof.
 func GetStructSizes() map[string]int {
-	return map[string]int{
-		"UnalignedStruct":      int(unsafe.Sizeof(UnalignedStruct{})),
-		"AlignedStruct":        int(unsafe.Sizeof(AlignedStruct{})),
-		"PoorlyPaddedStruct":   int(unsafe.Sizeof(PoorlyPaddedStruct{})),
-		"MixedTypesAligned":    int(unsafe.Sizeof(MixedTypesAligned{})),
-		"MixedTypesUnaligned":  int(unsafe.Sizeof(MixedTypesUnaligned{})),
+	sizes := make(map[string]int, len(AlignmentDemoTypes))
+	for _, t := range AlignmentDemoTypes {
+		sizes[t.Name()] = int(t.Size())
 	}
+	return sizes
 }
 
 // ProcessUnaligned demonstrates processing with poor alignment.
@@ -100,11 +155,59 @@ func ProcessAlignedPtr(s *AlignedStruct) int64 {
 }
 
 const (
-	BenchSliceSize   = 1000000
-	BenchCacheLine   = 64
-	BenchL1CacheSize = 32 * 1024
+	BenchSliceSize = 1000000
+	BenchCacheLine = 64
 )
 
+// printLayout prints the field table and byte map of a struct type and
+// records the offset and trailing padding of every field.
+func printLayout(out *report.Report, t reflect.Type) {
+	l, err := layout.Analyze(t)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+	fmt.Fprint(out, l.Table())
+	fmt.Fprintln(out)
+	fmt.Fprint(out, l.ByteMap())
+	fmt.Fprintln(out)
+
+	out.Add("struct_padding", float64(l.Padding), "bytes", "type", t.Name())
+	for _, f := range l.Fields {
+		out.Add("field_offset", float64(f.Offset), "bytes", "type", t.Name(), "field", f.Path)
+		if f.PaddingAfter > 0 {
+			out.Add("field_padding_after", float64(f.PaddingAfter), "bytes", "type", t.Name(), "field", f.Path)
+		}
+	}
+}
+
+// printArchSizes prints the size and padding of each type on every
+// architecture in layout.Archs, computed with go/types instead of
+// cross-compiling, and records them.
+func printArchSizes(out *report.Report, ts []reflect.Type) {
+	fmt.Fprintf(out, "%-24s", "Type")
+	for _, arch := range layout.Archs {
+		fmt.Fprintf(out, " | %-12s", arch)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, strings.Repeat("-", 24+15*len(layout.Archs)))
+
+	for _, t := range ts {
+		sizes, err := layout.SizesFor(t, layout.Archs)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+		fmt.Fprintf(out, "%-24s", t.Name())
+		for _, s := range sizes {
+			fmt.Fprintf(out, " | %-12s", fmt.Sprintf("%3d (%d pad)", s.Size, s.Padding))
+			out.Add("arch_struct_size", float64(s.Size), "bytes", "type", t.Name(), "arch", s.Arch)
+			out.Add("arch_struct_padding", float64(s.Padding), "bytes", "type", t.Name(), "arch", s.Arch)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
 func createUnalignedSliceForDemo(size int) []UnalignedStruct {
 	data := make([]UnalignedStruct, size)
 	for i := range data {
@@ -135,103 +238,117 @@ func createAlignedSliceForDemo(size int) []AlignedStruct {
 	return data
 }
 
-func init() {
-	rand.Seed(42)
-}
-
 // RunAlignmentDemo demonstrates the performance impact of struct alignment
-func RunAlignmentDemo() {
-	fmt.Println("================================================================================")
-	fmt.Println("                    STRUCT ALIGNMENT PERFORMANCE ANALYSIS                     ")
-	fmt.Println("================================================================================")
-	fmt.Println()
+func RunAlignmentDemo(out *report.Report) {
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out, "                    STRUCT ALIGNMENT PERFORMANCE ANALYSIS                     ")
+	fmt.Fprintln(out, "================================================================================")
+	fmt.Fprintln(out)
 
-	fmt.Println("=== STRUCT SIZES ===")
+	fmt.Fprintln(out, "=== STRUCT SIZES ===")
 	sizes := GetStructSizes()
-	for name, size := range sizes {
-		fmt.Printf("%-24s: %3d bytes\n", name, size)
+	for _, name := range slices.Sorted(maps.Keys(sizes)) {
+		fmt.Fprintf(out, "%-24s: %3d bytes\n", name, sizes[name])
+		out.Add("struct_size", float64(sizes[name]), "bytes", "type", name)
+	}
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== SIZES ACROSS ARCHITECTURES ===")
+	fmt.Fprintln(out, "The same structs as laid out by the gc compiler for other GOARCH values (go/types):")
+	fmt.Fprintln(out)
+	printArchSizes(out, AlignmentDemoTypes)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "On 32-bit targets int64 and float64 are only 4-byte aligned and pointers take")
+	fmt.Fprintln(out, "4 bytes, so both the sizes and the padding shrink. The field order still matters.")
+	fmt.Fprintln(out, "Check your own types with layout.SizesFor or \"day0 align -archs=amd64,arm,386 ./...\".")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== FIELD LAYOUT ===")
+	fmt.Fprintln(out, "Offsets, sizes and padding as the compiler laid them out (layout.Analyze):")
+	fmt.Fprintln(out)
+	for _, t := range []reflect.Type{reflect.TypeFor[UnalignedStruct](), reflect.TypeFor[AlignedStruct]()} {
+		printLayout(out, t)
 	}
-	fmt.Println()
 
 	unalignedSize := sizes["UnalignedStruct"]
 	alignedSize := sizes["AlignedStruct"]
 	savings := unalignedSize - alignedSize
 	savingsPercent := float64(savings) / float64(unalignedSize) * 100
 
-	fmt.Println("=== MEMORY SAVINGS ===")
-	fmt.Printf("UnalignedStruct: %d bytes\n", unalignedSize)
-	fmt.Printf("AlignedStruct:   %d bytes\n", alignedSize)
-	fmt.Printf("Savings:         %d bytes (%.1f%% reduction)\n", savings, savingsPercent)
-	fmt.Println()
-
-	fmt.Println("=== PERFORMANCE DEMONSTRATION ===")
-	fmt.Printf("Slice size: %d elements\n", BenchSliceSize)
-	fmt.Printf("Unaligned memory: %d bytes (%.2f MB)\n",
+	fmt.Fprintln(out, "=== MEMORY SAVINGS ===")
+	fmt.Fprintf(out, "UnalignedStruct: %d bytes\n", unalignedSize)
+	fmt.Fprintf(out, "AlignedStruct:   %d bytes\n", alignedSize)
+	fmt.Fprintf(out, "Savings:         %d bytes (%.1f%% reduction)\n", savings, savingsPercent)
+	out.Add("padding_savings", float64(savings), "bytes", "from", "UnalignedStruct", "to", "AlignedStruct")
+	out.Add("padding_savings", savingsPercent, "percent", "from", "UnalignedStruct", "to", "AlignedStruct")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== PERFORMANCE DEMONSTRATION ===")
+	fmt.Fprintf(out, "Slice size: %d elements\n", BenchSliceSize)
+	fmt.Fprintf(out, "Unaligned memory: %d bytes (%.2f MB)\n",
 		BenchSliceSize*unalignedSize,
 		float64(BenchSliceSize*unalignedSize)/1024/1024)
-	fmt.Printf("Aligned memory:   %d bytes (%.2f MB)\n",
+	fmt.Fprintf(out, "Aligned memory:   %d bytes (%.2f MB)\n",
 		BenchSliceSize*alignedSize,
 		float64(BenchSliceSize*alignedSize)/1024/1024)
-	fmt.Printf("Memory saved:     %d bytes (%.2f MB)\n",
+	fmt.Fprintf(out, "Memory saved:     %d bytes (%.2f MB)\n",
 		BenchSliceSize*savings,
 		float64(BenchSliceSize*savings)/1024/1024)
-	fmt.Println()
-
-	fmt.Println("=== CACHE EFFECTS ===")
-	elementsPerCacheLineUnaligned := BenchCacheLine / unalignedSize
-	elementsPerCacheLineAligned := BenchCacheLine / alignedSize
-
-	fmt.Printf("Cache line size: %d bytes\n", BenchCacheLine)
-	fmt.Printf("Unaligned: %d elements per cache line\n", elementsPerCacheLineUnaligned)
-	fmt.Printf("Aligned:   %d elements per cache line\n", elementsPerCacheLineAligned)
-	fmt.Printf("Efficiency improvement: %.1fx\n",
-		float64(elementsPerCacheLineAligned)/float64(elementsPerCacheLineUnaligned))
-	fmt.Println()
-
-	elementsInL1Unaligned := BenchL1CacheSize / unalignedSize
-	elementsInL1Aligned := BenchL1CacheSize / alignedSize
-
-	fmt.Printf("L1 Cache: %d KB\n", BenchL1CacheSize/1024)
-	fmt.Printf("Unaligned: ~%d elements fit in L1\n", elementsInL1Unaligned)
-	fmt.Printf("Aligned:   ~%d elements fit in L1\n", elementsInL1Aligned)
-	fmt.Printf("Cache capacity improvement: %.1fx\n",
-		float64(elementsInL1Aligned)/float64(elementsInL1Unaligned))
-	fmt.Println()
-
-	fmt.Println("=== RUN BENCHMARKS ===")
-	fmt.Println("To run benchmarks, execute:")
-	fmt.Println("  go test -bench=. -benchmem -run=^$ .")
-	fmt.Println()
-
-	fmt.Println("================================================================================")
-
-	fmt.Println()
-	fmt.Println("=== QUICK TIMING TEST ===")
-	
+	fmt.Fprintln(out)
+	elements := strconv.Itoa(BenchSliceSize)
+	out.Add("slice_memory", float64(BenchSliceSize*unalignedSize), "bytes", "type", "UnalignedStruct", "elements", elements)
+	out.Add("slice_memory", float64(BenchSliceSize*alignedSize), "bytes", "type", "AlignedStruct", "elements", elements)
+
+	fmt.Fprintln(out, "=== CACHE EFFECTS ===")
+	cache := cpucache.Detect()
+	fmt.Fprintf(out, "Caches (from %s):", cache.Source)
+	for _, level := range cache.Levels() {
+		fmt.Fprintf(out, " L%d %s", level, formatWorkingSet(cache.DataSize(level)))
+		out.Add("cache_size", float64(cache.DataSize(level)), "bytes", "level", "L"+strconv.Itoa(level))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Cache line size: %d bytes\n", cache.LineSize())
+	fmt.Fprintln(out, "Whether the smaller structs pay off depends on where the data lives; the")
+	fmt.Fprintln(out, "sweep below measures it per working set instead of dividing sizes.")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== ACCESS PATTERNS FROM L1 TO DRAM ===")
+	printAccessSweep(out, cache)
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "=== RUN BENCHMARKS ===")
+	fmt.Fprintln(out, "To run benchmarks, execute:")
+	fmt.Fprintln(out, "  day0 bench alignment")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "================================================================================")
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== QUICK TIMING TEST ===")
+
 	unalignedData := createUnalignedSliceForDemo(100000)
 	alignedData := createAlignedSliceForDemo(100000)
 
-	start := time.Now()
-	var sum1 int64
-	for _, v := range unalignedData {
-		sum1 += v.Field2 + v.Field4 + v.Field6
-	}
-	unalignedTime := time.Since(start)
-
-	start = time.Now()
-	var sum2 int64
-	for _, v := range alignedData {
-		sum2 += v.Field2 + v.Field4 + v.Field6
-	}
-	alignedTime := time.Since(start)
+	var sum1, sum2 int64
+	unaligned, aligned := stats.TimePair(demoSamples,
+		func() {
+			for _, v := range unalignedData {
+				sum1 += v.Field2 + v.Field4 + v.Field6
+			}
+		},
+		func() {
+			for _, v := range alignedData {
+				sum2 += v.Field2 + v.Field4 + v.Field6
+			}
+		})
 
 	_ = sum1
 	_ = sum2
 
-	fmt.Printf("Unaligned sequential: %v\n", unalignedTime)
-	fmt.Printf("Aligned sequential:   %v\n", alignedTime)
-	if alignedTime < unalignedTime {
-		fmt.Printf("Speedup:              %.2fx\n",
-			float64(unalignedTime.Nanoseconds())/float64(alignedTime.Nanoseconds()))
-	}
+	unalignedTime, alignedTime := stats.Summarize(unaligned), stats.Summarize(aligned)
+	fmt.Fprintf(out, "Unaligned sequential: %s\n", formatTiming(unalignedTime))
+	fmt.Fprintf(out, "Aligned sequential:   %s\n", formatTiming(alignedTime))
+	addTiming(out, "sequential_sum", unalignedTime, "type", "UnalignedStruct", "elements", "100000")
+	addTiming(out, "sequential_sum", alignedTime, "type", "AlignedStruct", "elements", "100000")
+	printSpeedup(out, "sequential_sum_speedup", unaligned, aligned, "elements", "100000")
 }
@@ -0,0 +1,97 @@
+// Package topics provides Go performance optimization demonstrations.
+package topics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"day0/report"
+)
+
+// =============================================================================
+// TOPIC REGISTRY
+// =============================================================================
+//
+// Every topic file registers itself from init(), so adding a topic is a matter
+// of adding a file here - the demo binary discovers it through All().
+
+// Topic is one optimization topic of the demo.
+type Topic interface {
+	// ID is the short, stable name used on the command line, e.g. "pooling".
+	ID() string
+	// Title is the human-readable name of the topic.
+	Title() string
+	// Summary is a one-line description shown by `list`.
+	Summary() string
+	// Tags group related topics for filtering.
+	Tags() []string
+	// Takeaways are the key points of the topic; the first one is the headline.
+	Takeaways() []string
+	// Benchmarks lists the topic's benchmark functions by name, grouped into tables.
+	Benchmarks() []BenchmarkGroup
+	// Order positions the topic in the curriculum; lower comes first.
+	Order() int
+	// Demo writes the explanation and runs the demonstration. Every number it
+	// prints is also added to out as a record, for the machine-readable formats.
+	Demo(out *report.Report)
+}
+
+// BenchmarkGroup is a titled set of benchmark function names shown as one table.
+// The names refer to Benchmark* functions in package benchmarks.
+type BenchmarkGroup struct {
+	Title string
+	Names []string
+}
+
+// registry holds every registered topic.
+var registry []Topic
+
+// Register adds a topic. It panics if the ID is already taken, since that is a
+// programming error caught on the first run.
+func Register(t Topic) {
+	if _, ok := Lookup(t.ID()); ok {
+		panic(fmt.Sprintf("topics: duplicate topic ID %q", t.ID()))
+	}
+	registry = append(registry, t)
+}
+
+// All returns every registered topic in curriculum order.
+func All() []Topic {
+	all := slices.Clone(registry)
+	slices.SortStableFunc(all, func(a, b Topic) int {
+		return cmp.Compare(a.Order(), b.Order())
+	})
+	return all
+}
+
+// Lookup returns the topic with the given ID.
+func Lookup(id string) (Topic, bool) {
+	for _, t := range registry {
+		if t.ID() == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// topic is the Topic implementation shared by the built-in topics.
+type topic struct {
+	order      int
+	id         string
+	title      string
+	summary    string
+	tags       []string
+	takeaways  []string
+	benchmarks []BenchmarkGroup
+	demo       func(out *report.Report)
+}
+
+func (t *topic) ID() string                   { return t.id }
+func (t *topic) Title() string                { return t.title }
+func (t *topic) Summary() string              { return t.summary }
+func (t *topic) Tags() []string               { return t.tags }
+func (t *topic) Takeaways() []string          { return t.takeaways }
+func (t *topic) Benchmarks() []BenchmarkGroup { return t.benchmarks }
+func (t *topic) Order() int                   { return t.order }
+func (t *topic) Demo(out *report.Report)      { t.demo(out) }